@@ -1,11 +1,15 @@
 package cleanup
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/openshift/osde2e/cmd/osde2e/common"
+	"github.com/openshift/osde2e/pkg/common/cleanup"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/metadata"
 	"github.com/openshift/osde2e/pkg/common/providers"
 	"github.com/openshift/osde2e/pkg/common/spi"
@@ -15,7 +19,7 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Cleans up expired clusters.",
-	Long:  "Cleans up expired clusters.",
+	Long:  "Cleans up expired clusters, and clusters left behind by failed or abandoned jobs according to a cleanup policy.",
 	Args:  cobra.OnlyValidArgs,
 	RunE:  run,
 }
@@ -24,6 +28,10 @@ var args struct {
 	configString    string
 	customConfig    string
 	secretLocations string
+	policy          string
+	dryRun          bool
+	maxAge          time.Duration
+	orphanAfter     time.Duration
 }
 
 func init() {
@@ -47,6 +55,30 @@ func init() {
 		"",
 		"A comma separated list of possible secret directory locations for loading secret configs.",
 	)
+	flags.StringVar(
+		&args.policy,
+		"policy",
+		"",
+		"A YAML cleanup policy file. Overrides CLEANUP_POLICY.",
+	)
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Report which clusters would be deleted without deleting them.",
+	)
+	flags.DurationVar(
+		&args.maxAge,
+		"max-age",
+		0,
+		"Delete clusters older than this, overriding the policy.",
+	)
+	flags.DurationVar(
+		&args.orphanAfter,
+		"orphan-after",
+		0,
+		"Delete clusters a job never marked completed once their status has not changed for this long, overriding the policy.",
+	)
 
 	Cmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"json", "prom"}, cobra.ShellCompDirectiveDefault
@@ -60,27 +92,47 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("error loading initial state: %v", err)
 	}
 
+	if args.policy != "" {
+		viper.Set(config.Cleanup.Policy, args.policy)
+	}
+
 	if provider, err = providers.ClusterProvider(); err != nil {
 		return fmt.Errorf("could not setup cluster provider: %v", err)
 	}
 
 	metadata.Instance.SetEnvironment(provider.Environment())
 
-	clusters, err := provider.ListClusters("properties.MadeByOSDe2e='true'")
+	policy := cleanup.Policy{}
+	if policyFile := viper.GetString(config.Cleanup.Policy); policyFile != "" {
+		file, err := cleanup.LoadPolicyFile(policyFile)
+		if err != nil {
+			return err
+		}
+		policy = file.ForEnvironment(provider.Environment())
+	}
+	if args.maxAge != 0 {
+		policy.MaxAge = args.maxAge
+	}
+	if args.orphanAfter != 0 {
+		policy.OrphanAfter = args.orphanAfter
+	}
+
+	summary, err := cleanup.Run(context.Background(), provider, policy, args.dryRun || viper.GetBool(config.Cleanup.DryRun))
 	if err != nil {
 		return err
 	}
 
-	now := time.Now()
+	log.Printf("Cleanup: %d deleted, %d would be deleted, %d skipped, %d failed",
+		summary.Count(cleanup.ActionDeleted), summary.Count(cleanup.ActionWouldDelete), summary.Count(cleanup.ActionSkipped), summary.Count(cleanup.ActionFailed))
 
-	for _, cluster := range clusters {
-		if !cluster.ExpirationTimestamp().IsZero() && now.UTC().After(cluster.ExpirationTimestamp().UTC()) {
-			log.Printf("%s %s has expired. Deleting cluster...", cluster.ID(), cluster.Name())
-			if err := provider.DeleteCluster(cluster.ID()); err != nil {
-				log.Printf("Error deleting cluster: %s", err.Error())
-			}
+	if reportDir := viper.GetString(config.ReportDir); reportDir != "" {
+		if err := summary.WriteReports(reportDir); err != nil {
+			return err
 		}
 	}
 
+	if summary.Failed() {
+		return fmt.Errorf("failed to delete %d clusters", summary.Count(cleanup.ActionFailed))
+	}
 	return nil
 }
@@ -0,0 +1,465 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/osde2e/cmd/osde2e/common"
+	"github.com/openshift/osde2e/cmd/osde2e/helpers"
+	clusterutil "github.com/openshift/osde2e/pkg/common/cluster"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/rosaprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/versions"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manages clusters.",
+	Long:  "Creates, inspects and manages clusters through the configured cluster provider, the same way osde2e test does.",
+}
+
+var args struct {
+	configString    string
+	customConfig    string
+	secretLocations string
+	environment     string
+	output          string
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Creates a cluster.",
+	Long:  "Creates a cluster from the loaded configs, named like the clusters osde2e test creates.",
+	Args:  cobra.NoArgs,
+	RunE:  create,
+}
+
+var createArgs struct {
+	name string
+	wait bool
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <cluster-id>",
+	Short: "Shows a cluster.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  get,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists clusters.",
+	Long:  "Lists clusters matching a query such as \"properties.Status like 'completed-%'\". Only clusters made by osde2e are listed by default.",
+	Args:  cobra.NoArgs,
+	RunE:  list,
+}
+
+var listArgs struct {
+	query string
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <cluster-id>",
+	Short: "Deletes a cluster.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  deleteCluster,
+}
+
+var extendCmd = &cobra.Command{
+	Use:   "extend <cluster-id>",
+	Short: "Extends a cluster's expiration.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  extend,
+}
+
+var extendArgs struct {
+	hours   uint64
+	minutes uint64
+}
+
+var hibernateCmd = &cobra.Command{
+	Use:   "hibernate <cluster-id>",
+	Short: "Hibernates a cluster.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  hibernate,
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <cluster-id>",
+	Short: "Resumes a hibernating cluster.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  resume,
+}
+
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig <cluster-id>",
+	Short: "Prints a cluster's kubeconfig.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  kubeconfig,
+}
+
+var kubeconfigArgs struct {
+	file string
+}
+
+var addPropertyCmd = &cobra.Command{
+	Use:               "add-property <cluster-id> <property> <value>",
+	Short:             "Sets a property on a cluster.",
+	Long:              "Sets a property on a cluster. The properties osde2e uses are completed, but any property can be set.",
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: propertyComplete,
+	RunE:              addProperty,
+}
+
+func init() {
+	pfs := Cmd.PersistentFlags()
+	pfs.StringVar(
+		&args.configString,
+		"configs",
+		"",
+		"A comma separated list of built in configs to use",
+	)
+	Cmd.RegisterFlagCompletionFunc("configs", helpers.ConfigComplete)
+	pfs.StringVar(
+		&args.customConfig,
+		"custom-config",
+		"",
+		"Custom config file for osde2e",
+	)
+	pfs.StringVar(
+		&args.secretLocations,
+		"secret-locations",
+		"",
+		"A comma separated list of possible secret directory locations for loading secret configs.",
+	)
+	pfs.StringVarP(
+		&args.environment,
+		"environment",
+		"e",
+		"",
+		"Environment of the ocm or rosa provider to use.",
+	)
+	pfs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		outputTable,
+		"Output format (json|table).",
+	)
+	Cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputJSON, outputTable}, cobra.ShellCompDirectiveDefault
+	})
+
+	createCmd.Flags().StringVar(
+		&createArgs.name,
+		"name",
+		"",
+		"Name of the cluster. Defaults to CLUSTER_NAME, or a generated osde2e name.",
+	)
+	createCmd.Flags().BoolVar(
+		&createArgs.wait,
+		"wait",
+		false,
+		"Wait for the cluster to be installed and pass its health checks.",
+	)
+
+	listCmd.Flags().StringVarP(
+		&listArgs.query,
+		"query",
+		"q",
+		fmt.Sprintf("properties.%s='true'", clusterproperties.MadeByOSDe2e),
+		"Query clusters must match. An empty query lists every cluster.",
+	)
+
+	extendCmd.Flags().Uint64Var(
+		&extendArgs.hours,
+		"hours",
+		1,
+		"Hours to extend the expiration by.",
+	)
+	extendCmd.Flags().Uint64Var(
+		&extendArgs.minutes,
+		"minutes",
+		0,
+		"Minutes to extend the expiration by.",
+	)
+
+	kubeconfigCmd.Flags().StringVarP(
+		&kubeconfigArgs.file,
+		"file",
+		"f",
+		"",
+		"Write the kubeconfig to this file instead of stdout.",
+	)
+
+	Cmd.AddCommand(createCmd, getCmd, listCmd, deleteCmd, extendCmd, hibernateCmd, resumeCmd, kubeconfigCmd, addPropertyCmd)
+}
+
+// setup loads the configs and returns the cluster provider along with a context that is cancelled on interrupt.
+func setup() (context.Context, context.CancelFunc, spi.Provider, error) {
+	if args.output != outputJSON && args.output != outputTable {
+		return nil, nil, nil, fmt.Errorf("unsupported output format %q, expected %s or %s", args.output, outputJSON, outputTable)
+	}
+
+	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
+		return nil, nil, nil, fmt.Errorf("error loading initial state: %v", err)
+	}
+	if args.environment != "" {
+		switch provider := viper.GetString(config.Provider); provider {
+		case "ocm":
+			viper.Set(ocmprovider.Env, args.environment)
+		case "rosa":
+			viper.Set(rosaprovider.Env, args.environment)
+		default:
+			return nil, nil, nil, fmt.Errorf("the %s provider has no environments to choose from", provider)
+		}
+	}
+
+	provider, err := providers.ClusterProvider()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not setup cluster provider: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	return ctx, stop, provider, nil
+}
+
+// requireCapability fails if the provider doesn't support an optional feature.
+func requireCapability(provider spi.Provider, capability spi.Capability) error {
+	if !provider.Capabilities().Supports(capability) {
+		return fmt.Errorf("the %s provider does not support %s", provider.Type(), capability)
+	}
+	return nil
+}
+
+func create(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if createArgs.name != "" {
+		viper.Set(config.Cluster.Name, createArgs.name)
+	}
+	viper.Set(config.Cluster.ID, "")
+	// Pooled clusters are only leased for as long as this process keeps renewing the lease, after which
+	// the pool would delete them, so always create a new cluster.
+	viper.Set(config.Pool.Enabled, false)
+
+	// Pick the install and upgrade versions as osde2e test would.
+	if err := versions.ChooseVersions(); err != nil {
+		return fmt.Errorf("could not choose versions: %v", err)
+	}
+	if viper.GetString(config.Cluster.Version) == "" {
+		return fmt.Errorf("no cluster version was selected")
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	cluster, err := clusterutil.ProvisionCluster(ctx, logger)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Created cluster %s (%s)", cluster.Name(), cluster.ID())
+
+	if createArgs.wait {
+		if err := clusterutil.WaitForClusterReadyPostInstall(ctx, cluster.ID(), logger); err != nil {
+			return err
+		}
+		if cluster, err = provider.GetClusterContext(ctx, cluster.ID()); err != nil {
+			return fmt.Errorf("could not get cluster after it became ready: %v", err)
+		}
+	}
+
+	return printCluster(os.Stdout, args.output, cluster)
+}
+
+func get(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	cluster, err := provider.GetClusterContext(ctx, argv[0])
+	if err != nil {
+		return fmt.Errorf("could not get cluster %s: %v", argv[0], err)
+	}
+
+	return printCluster(os.Stdout, args.output, cluster)
+}
+
+func list(cmd *cobra.Command, argv []string) error {
+	if _, err := spi.ParseClusterQuery(listArgs.query); err != nil {
+		return err
+	}
+
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	clusters, err := provider.ListClustersContext(ctx, listArgs.query)
+	if err != nil {
+		return fmt.Errorf("could not list clusters: %v", err)
+	}
+
+	return printClusters(os.Stdout, args.output, clusters)
+}
+
+func deleteCluster(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := provider.DeleteClusterContext(ctx, argv[0]); err != nil {
+		return fmt.Errorf("could not delete cluster %s: %v", argv[0], err)
+	}
+
+	log.Printf("Deleting cluster %s", argv[0])
+	return nil
+}
+
+func extend(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := requireCapability(provider, spi.ExtendExpiryCapability); err != nil {
+		return err
+	}
+
+	if err := provider.ExtendExpiryContext(ctx, argv[0], extendArgs.hours, extendArgs.minutes, 0); err != nil {
+		return fmt.Errorf("could not extend cluster %s: %v", argv[0], err)
+	}
+
+	cluster, err := provider.GetClusterContext(ctx, argv[0])
+	if err != nil {
+		return fmt.Errorf("could not get cluster %s: %v", argv[0], err)
+	}
+
+	return printCluster(os.Stdout, args.output, cluster)
+}
+
+func hibernate(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := requireCapability(provider, spi.HibernationCapability); err != nil {
+		return err
+	}
+
+	if !provider.HibernateContext(ctx, argv[0]) {
+		return fmt.Errorf("could not hibernate cluster %s", argv[0])
+	}
+
+	log.Printf("Hibernating cluster %s", argv[0])
+	return nil
+}
+
+func resume(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := requireCapability(provider, spi.HibernationCapability); err != nil {
+		return err
+	}
+
+	if !provider.ResumeContext(ctx, argv[0]) {
+		return fmt.Errorf("could not resume cluster %s", argv[0])
+	}
+
+	log.Printf("Resuming cluster %s", argv[0])
+	return nil
+}
+
+func kubeconfig(cmd *cobra.Command, argv []string) error {
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	contents, err := provider.ClusterKubeconfigContext(ctx, argv[0])
+	if err != nil {
+		return fmt.Errorf("could not get kubeconfig for cluster %s: %v", argv[0], err)
+	}
+
+	if kubeconfigArgs.file == "" {
+		_, err = os.Stdout.Write(contents)
+		return err
+	}
+
+	if err := os.WriteFile(kubeconfigArgs.file, contents, os.FileMode(0o600)); err != nil {
+		return fmt.Errorf("could not write kubeconfig: %v", err)
+	}
+	log.Printf("Wrote kubeconfig for cluster %s to %s", argv[0], kubeconfigArgs.file)
+	return nil
+}
+
+func addProperty(cmd *cobra.Command, argv []string) error {
+	clusterID, property, value := argv[0], argv[1], argv[2]
+
+	ctx, stop, provider, err := setup()
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if !isKnownProperty(property) {
+		log.Printf("%s is not a property osde2e uses", property)
+	}
+
+	cluster, err := provider.GetClusterContext(ctx, clusterID)
+	if err != nil {
+		return fmt.Errorf("could not get cluster %s: %v", clusterID, err)
+	}
+
+	if err := provider.AddPropertyContext(ctx, cluster, property, value); err != nil {
+		return fmt.Errorf("could not set %s on cluster %s: %v", property, clusterID, err)
+	}
+
+	if cluster, err = provider.GetClusterContext(ctx, clusterID); err != nil {
+		return fmt.Errorf("could not get cluster %s: %v", clusterID, err)
+	}
+
+	return printCluster(os.Stdout, args.output, cluster)
+}
+
+func isKnownProperty(property string) bool {
+	for _, known := range clusterproperties.Properties {
+		if property == known {
+			return true
+		}
+	}
+	return false
+}
+
+func propertyComplete(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return clusterproperties.Properties, cobra.ShellCompDirectiveNoFileComp
+}
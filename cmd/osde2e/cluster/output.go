@@ -0,0 +1,114 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+const (
+	outputJSON  = "json"
+	outputTable = "table"
+)
+
+// clusterView is how a cluster is printed as JSON.
+type clusterView struct {
+	ID                  string            `json:"id"`
+	Name                string            `json:"name"`
+	State               spi.ClusterState  `json:"state"`
+	Version             string            `json:"version"`
+	CloudProvider       string            `json:"cloudProvider"`
+	Region              string            `json:"region"`
+	Product             string            `json:"product"`
+	Flavour             string            `json:"flavour,omitempty"`
+	NumComputeNodes     int               `json:"numComputeNodes"`
+	CreationTimestamp   time.Time         `json:"creationTimestamp"`
+	ExpirationTimestamp *time.Time        `json:"expirationTimestamp,omitempty"`
+	Addons              []string          `json:"addons,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+func newClusterView(cluster *spi.Cluster) clusterView {
+	view := clusterView{
+		ID:                cluster.ID(),
+		Name:              cluster.Name(),
+		State:             cluster.State(),
+		Version:           cluster.Version(),
+		CloudProvider:     cluster.CloudProvider(),
+		Region:            cluster.Region(),
+		Product:           cluster.Product(),
+		Flavour:           cluster.Flavour(),
+		NumComputeNodes:   cluster.NumComputeNodes(),
+		CreationTimestamp: cluster.CreationTimestamp(),
+		Addons:            cluster.Addons(),
+		Properties:        cluster.Properties(),
+	}
+	if expiration := cluster.ExpirationTimestamp(); !expiration.IsZero() {
+		view.ExpirationTimestamp = &expiration
+	}
+	return view
+}
+
+// printClusters prints clusters as a JSON array or a table with a row per cluster.
+func printClusters(w io.Writer, format string, clusters []*spi.Cluster) error {
+	if format == outputJSON {
+		views := []clusterView{}
+		for _, cluster := range clusters {
+			views = append(views, newClusterView(cluster))
+		}
+		return printJSON(w, views)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tSTATE\tVERSION\tCLOUD\tREGION\tPRODUCT\tEXPIRES")
+	for _, cluster := range clusters {
+		expires := "-"
+		if expiration := cluster.ExpirationTimestamp(); !expiration.IsZero() {
+			expires = expiration.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", cluster.ID(), cluster.Name(), cluster.State(), cluster.Version(),
+			cluster.CloudProvider(), cluster.Region(), cluster.Product(), expires)
+	}
+	return table.Flush()
+}
+
+// printCluster prints a single cluster as a JSON object, or as a table followed by its properties.
+func printCluster(w io.Writer, format string, cluster *spi.Cluster) error {
+	if format == outputJSON {
+		return printJSON(w, newClusterView(cluster))
+	}
+
+	if err := printClusters(w, format, []*spi.Cluster{cluster}); err != nil {
+		return err
+	}
+
+	properties := cluster.Properties()
+	if len(properties) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "PROPERTY\tVALUE")
+	for _, name := range names {
+		fmt.Fprintf(table, "%s\t%s\n", name, properties[name])
+	}
+	return table.Flush()
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -1,14 +1,16 @@
 package healthcheck
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/openshift/osde2e/cmd/osde2e/common"
 	"github.com/openshift/osde2e/cmd/osde2e/helpers"
 	clusterutil "github.com/openshift/osde2e/pkg/common/cluster"
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
@@ -18,11 +20,25 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "healthcheck",
 	Short: "Runs a healthcheck.",
-	Long:  "Runs a healthcheck on a cluster using the provided arguments.",
-	Args:  cobra.OnlyValidArgs,
-	RunE:  run,
+	Long: `Runs a healthcheck on a cluster using the provided arguments.
+
+Exits with 0 if the cluster is healthy, 1 if the checks couldn't be run, 2 if a check found the
+cluster unhealthy, 3 if a check couldn't tell whether the cluster is healthy and 4 if watching
+timed out before the cluster became healthy. When watching, checks that couldn't be run are
+retried until the timeout.`,
+	Args: cobra.OnlyValidArgs,
+	Run:  run,
 }
 
+// Exit codes for each outcome.
+const (
+	exitHealthy = iota
+	exitNotRun
+	exitUnhealthy
+	exitCheckError
+	exitTimedOut
+)
+
 var args struct {
 	configString    string
 	customConfig    string
@@ -30,6 +46,10 @@ var args struct {
 	clusterID       string
 	environment     string
 	kubeConfig      string
+	output          string
+	watch           bool
+	interval        time.Duration
+	timeout         time.Duration
 }
 
 func init() {
@@ -76,25 +96,136 @@ func init() {
 		"Path to local Kube config for running tests against.",
 	)
 
+	pfs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		outputText,
+		"Output format for check results (text|json|junit).",
+	)
+	Cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputText, outputJSON, outputJUnit}, cobra.ShellCompDirectiveDefault
+	})
+	pfs.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Keep checking until the cluster is healthy or the timeout is reached.",
+	)
+	pfs.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"How long to wait between checks when watching.",
+	)
+	pfs.DurationVar(
+		&args.timeout,
+		"timeout",
+		time.Hour,
+		"How long to watch for before giving up.",
+	)
+
 	viper.BindPFlag(config.Cluster.ID, Cmd.PersistentFlags().Lookup("cluster-id"))
 	viper.BindPFlag(ocmprovider.Env, Cmd.PersistentFlags().Lookup("environment"))
 	viper.BindPFlag(config.Kubeconfig.Path, Cmd.PersistentFlags().Lookup("kube-config"))
 }
 
-func run(cmd *cobra.Command, argv []string) error {
+func run(cmd *cobra.Command, argv []string) {
+	os.Exit(check())
+}
+
+func check() int {
 	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
-		return fmt.Errorf("error loading initial state: %v", err)
+		log.Printf("error loading initial state: %v", err)
+		return exitNotRun
+	}
+
+	if args.output != outputText && args.output != outputJSON && args.output != outputJUnit {
+		log.Printf("unknown output format %q", args.output)
+		return exitNotRun
 	}
+
+	// Keep the check logs out of machine-readable output.
 	logger := log.New(os.Stdout, "", log.LstdFlags)
-	isHealthy, failures, _ := clusterutil.PollClusterHealth(args.clusterID, logger)
-	if isHealthy {
-		log.Printf("Cluster %s is healthy!\n", args.clusterID)
-	} else {
-		log.Printf("Cluster %s is not healthy yet.\n", args.clusterID)
-		if len(failures) > 0 {
-			log.Printf("Currently failing %s health checks", strings.Join(failures, ", "))
+	if args.output != outputText {
+		logger.SetOutput(os.Stderr)
+	}
+
+	ctx := context.Background()
+	if args.watch {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, args.timeout)
+		defer cancel()
+	}
+
+	report := &report{ClusterID: args.clusterID, StartedAt: time.Now().UTC()}
+	for {
+		results, err := clusterutil.CheckClusterHealth(ctx, args.clusterID, logger)
+		if err != nil {
+			log.Printf("Unable to check the health of cluster %s: %v", args.clusterID, err)
+			if !args.watch {
+				return exitNotRun
+			}
+		}
+
+		// An iteration that couldn't check the cluster counts as unhealthy, and keeps the results of
+		// the last one that could.
+		report.Iterations++
+		if err == nil {
+			report.Results = results
+		}
+		report.Healthy = err == nil && healthy(results)
+		report.Duration = time.Since(report.StartedAt).Seconds()
+
+		if report.Healthy || !args.watch {
+			break
+		}
+
+		logger.Printf("Cluster %s is not healthy yet, checking again in %s.\n", args.clusterID, args.interval)
+		select {
+		case <-ctx.Done():
+			report.TimedOut = true
+		case <-time.After(args.interval):
+		}
+		if report.TimedOut {
+			break
+		}
+	}
+
+	if err := report.write(os.Stdout, args.output); err != nil {
+		log.Printf("error writing healthcheck results: %v", err)
+	}
+
+	switch {
+	case report.Healthy:
+		logger.Printf("Cluster %s is healthy!\n", args.clusterID)
+		return exitHealthy
+	case report.TimedOut:
+		logger.Printf("Timed out after %s waiting for cluster %s to be healthy.\n", args.timeout, args.clusterID)
+		return exitTimedOut
+	}
+
+	// A check that found the cluster unhealthy is more telling than one that couldn't tell.
+	failures := []string{}
+	exitCode := exitCheckError
+	for _, result := range report.Results {
+		if !result.Healthy() {
+			failures = append(failures, result.Name)
+		}
+		if result.Status == healthchecks.StatusFailed {
+			exitCode = exitUnhealthy
+		}
+	}
+	logger.Printf("Cluster %s is not healthy yet.\n", args.clusterID)
+	logger.Printf("Currently failing %s health checks", strings.Join(failures, ", "))
+	return exitCode
+}
+
+func healthy(results []healthchecks.Result) bool {
+	for _, result := range results {
+		if !result.Healthy() {
+			return false
 		}
-		return fmt.Errorf("healthcheck failed")
 	}
-	return nil
+	return true
 }
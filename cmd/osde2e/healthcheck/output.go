@@ -0,0 +1,126 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
+)
+
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJUnit = "junit"
+)
+
+// report is the outcome of the last round of health checks.
+type report struct {
+	ClusterID  string                `json:"clusterID"`
+	Healthy    bool                  `json:"healthy"`
+	TimedOut   bool                  `json:"timedOut,omitempty"`
+	Iterations int                   `json:"iterations"`
+	StartedAt  time.Time             `json:"startedAt"`
+	Duration   float64               `json:"duration"`
+	Results    []healthchecks.Result `json:"results"`
+}
+
+func (r *report) write(w io.Writer, format string) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case outputJUnit:
+		data, err := xml.MarshalIndent(r.junit(), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "CHECK\tSTATUS\tDURATION\tMESSAGE")
+	for _, result := range r.Results {
+		fmt.Fprintf(table, "%s\t%s\t%.2fs\t%s\n", result.Name, result.Status, result.Duration, result.Message)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	for _, result := range r.Results {
+		for _, object := range result.Objects {
+			fmt.Fprintf(w, "%s: %s\n", result.Name, object)
+		}
+	}
+	return nil
+}
+
+// junit reports a test case per check, with the offending objects as the failure description.
+func (r *report) junit() reporters.JUnitTestSuites {
+	suite := reporters.JUnitTestSuite{
+		Name:      "healthcheck",
+		Package:   r.ClusterID,
+		Time:      r.Duration,
+		Timestamp: r.StartedAt.Format("2006-01-02T15:04:05"),
+	}
+
+	for _, result := range r.Results {
+		testCase := reporters.JUnitTestCase{
+			Name:      fmt.Sprintf("[healthcheck] %s", result.Name),
+			Classname: "healthcheck",
+			Status:    string(result.Status),
+			Time:      result.Duration,
+		}
+
+		switch result.Status {
+		case healthchecks.StatusFailed:
+			testCase.Failure = &reporters.JUnitFailure{
+				Message:     result.Message,
+				Type:        failureType(result),
+				Description: strings.Join(result.Objects, "\n"),
+			}
+			suite.Failures++
+		case healthchecks.StatusError:
+			testCase.Error = &reporters.JUnitError{
+				Message: result.Message,
+				Type:    "error",
+			}
+			suite.Errors++
+		case healthchecks.StatusSkipped:
+			testCase.Skipped = &reporters.JUnitSkipped{Message: "skipped - " + result.Message}
+			suite.Skipped++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	return reporters.JUnitTestSuites{
+		TestSuites: []reporters.JUnitTestSuite{suite},
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Errors:     suite.Errors,
+		Time:       suite.Time,
+	}
+}
+
+// failureType is the reasons the check's objects are unhealthy, if the check can tell, so that failures can
+// be grouped by reason.
+func failureType(result healthchecks.Result) string {
+	if len(result.Reasons) == 0 {
+		return "failed"
+	}
+	reasons := make([]string, 0, len(result.Reasons))
+	for reason := range result.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return strings.Join(reasons, ",")
+}
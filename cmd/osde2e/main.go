@@ -13,8 +13,10 @@ import (
 	"github.com/openshift/osde2e/cmd/osde2e/alert"
 	"github.com/openshift/osde2e/cmd/osde2e/arguments"
 	"github.com/openshift/osde2e/cmd/osde2e/cleanup"
+	"github.com/openshift/osde2e/cmd/osde2e/cluster"
 	"github.com/openshift/osde2e/cmd/osde2e/completion"
 	"github.com/openshift/osde2e/cmd/osde2e/healthcheck"
+	"github.com/openshift/osde2e/cmd/osde2e/pool"
 	"github.com/openshift/osde2e/cmd/osde2e/query"
 	"github.com/openshift/osde2e/cmd/osde2e/report"
 	"github.com/openshift/osde2e/cmd/osde2e/test"
@@ -46,6 +48,8 @@ func init() {
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(alert.Cmd)
 	root.AddCommand(cleanup.Cmd)
+	root.AddCommand(pool.Cmd)
+	root.AddCommand(cluster.Cmd)
 }
 
 func main() {
@@ -0,0 +1,144 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/osde2e/cmd/osde2e/common"
+	"github.com/openshift/osde2e/cmd/osde2e/helpers"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/pool"
+	"github.com/openshift/osde2e/pkg/common/providers"
+)
+
+// metricsFile is where the pool's metrics are written in the report directory.
+const metricsFile = "pool.metrics.prom"
+
+var Cmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Maintains a pool of warm clusters.",
+	Long:  "Keeps CLUSTER_POOL_SIZE hibernated clusters of the configured version, cloud provider and product ready for test runs to lease with USE_CLUSTER_POOL.",
+	Args:  cobra.OnlyValidArgs,
+	RunE:  run,
+}
+
+var args struct {
+	configString    string
+	customConfig    string
+	secretLocations string
+	size            int
+	interval        time.Duration
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.configString,
+		"configs",
+		"",
+		"A comma separated list of built in configs to use",
+	)
+	Cmd.RegisterFlagCompletionFunc("configs", helpers.ConfigComplete)
+	flags.StringVar(
+		&args.customConfig,
+		"custom-config",
+		"",
+		"Custom config file for osde2e",
+	)
+	flags.StringVar(
+		&args.secretLocations,
+		"secret-locations",
+		"",
+		"A comma separated list of possible secret directory locations for loading secret configs.",
+	)
+	flags.IntVar(
+		&args.size,
+		"size",
+		0,
+		"Number of unleased clusters to keep. Overrides CLUSTER_POOL_SIZE.",
+	)
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		0,
+		"Keep reconciling the pool at this interval instead of reconciling once.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	if err := common.LoadConfigs(args.configString, args.customConfig, args.secretLocations); err != nil {
+		return fmt.Errorf("error loading initial state: %v", err)
+	}
+
+	if args.size > 0 {
+		viper.Set(config.Pool.Size, args.size)
+	}
+	if viper.GetString(config.Cluster.Version) == "" {
+		return fmt.Errorf("a cluster version is required to know which clusters to pool")
+	}
+
+	// Pooled clusters must always be freshly provisioned.
+	viper.Set(config.Cluster.UseExistingCluster, false)
+
+	provider, err := providers.ClusterProvider()
+	if err != nil {
+		return fmt.Errorf("could not setup cluster provider: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clusterPool := pool.NewFromConfig(provider)
+	log.Printf("Maintaining %d clusters in pool %s", viper.GetInt(config.Pool.Size), clusterPool.Key())
+
+	for {
+		if err := reconcile(ctx, clusterPool); err != nil {
+			if args.interval <= 0 {
+				return err
+			}
+			log.Printf("%v, retrying in %s", err, args.interval)
+		}
+
+		if reportDir := viper.GetString(config.ReportDir); reportDir != "" {
+			if err := pool.WritePrometheusFile(filepath.Join(reportDir, metricsFile)); err != nil {
+				log.Printf("Error writing pool metrics: %v", err)
+			}
+		}
+
+		if args.interval <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(args.interval):
+		}
+	}
+}
+
+// reconcile reconciles the pool once and prints its status.
+func reconcile(ctx context.Context, clusterPool *pool.Pool) error {
+	status, err := clusterPool.Reconcile(ctx)
+	if err != nil {
+		return fmt.Errorf("error reconciling pool %s: %v", clusterPool.Key(), err)
+	}
+
+	output, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package cleanup deletes clusters left behind by osde2e according to a policy.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Action is what cleanup did with a cluster.
+type Action string
+
+const (
+	// ActionDeleted clusters were deleted.
+	ActionDeleted Action = "deleted"
+
+	// ActionWouldDelete clusters would have been deleted if it weren't a dry run.
+	ActionWouldDelete Action = "would-delete"
+
+	// ActionSkipped clusters were left alone.
+	ActionSkipped Action = "skipped"
+
+	// ActionFailed clusters should have been deleted but deleting them failed.
+	ActionFailed Action = "failed"
+)
+
+// Result is the outcome for a single cluster.
+type Result struct {
+	ClusterID string `json:"clusterID"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Status    string `json:"status,omitempty"`
+	Action    Action `json:"action"`
+	Reason    string `json:"reason"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Summary is the outcome of a cleanup run.
+type Summary struct {
+	Environment string    `json:"environment"`
+	DryRun      bool      `json:"dryRun"`
+	StartedAt   time.Time `json:"startedAt"`
+	Duration    float64   `json:"durationSeconds"`
+	Results     []Result  `json:"results"`
+}
+
+// Count returns how many clusters had the given action.
+func (s *Summary) Count(action Action) int {
+	count := 0
+	for _, result := range s.Results {
+		if result.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns whether any deletion failed.
+func (s *Summary) Failed() bool {
+	return s.Count(ActionFailed) > 0
+}
+
+// Run applies the policy to the provider's clusters. In a dry run, clusters that would be deleted are
+// reported without being deleted. Deletion failures are recorded in the summary rather than stopping
+// the run; an error is only returned if the clusters couldn't be listed.
+func Run(ctx context.Context, provider spi.Provider, policy Policy, dryRun bool) (*Summary, error) {
+	compiled, err := policy.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Environment: provider.Environment(),
+		DryRun:      dryRun,
+		StartedAt:   time.Now().UTC(),
+		Results:     []Result{},
+	}
+
+	clusters, err := listIncluded(ctx, provider, compiled.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, cluster := range clusters {
+		result := Result{
+			ClusterID: cluster.ID(),
+			Name:      cluster.Name(),
+			State:     string(cluster.State()),
+			Status:    cluster.Properties()[clusterproperties.Status],
+			Action:    ActionSkipped,
+		}
+
+		var remove bool
+		remove, result.Reason = compiled.decide(cluster, now)
+		switch {
+		case !remove:
+		case cluster.State() == spi.ClusterStateUninstalling:
+			result.Reason = "already uninstalling"
+		case dryRun:
+			log.Printf("%s %s would be deleted: %s", cluster.ID(), cluster.Name(), result.Reason)
+			result.Action = ActionWouldDelete
+		default:
+			log.Printf("%s %s: %s. Deleting cluster...", cluster.ID(), cluster.Name(), result.Reason)
+			if err := provider.DeleteClusterContext(ctx, cluster.ID()); err != nil {
+				log.Printf("Error deleting cluster: %s", err.Error())
+				result.Action = ActionFailed
+				result.Error = err.Error()
+			} else {
+				result.Action = ActionDeleted
+			}
+		}
+		summary.Results = append(summary.Results, result)
+	}
+
+	summary.Duration = time.Since(summary.StartedAt).Seconds()
+	return summary, nil
+}
+
+// listIncluded lists the clusters matching any of the queries once each, sorted by ID.
+func listIncluded(ctx context.Context, provider spi.Provider, queries []string) ([]*spi.Cluster, error) {
+	seen := map[string]*spi.Cluster{}
+	for _, query := range queries {
+		clusters, err := provider.ListClustersContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("error listing clusters matching %q: %v", query, err)
+		}
+		for _, cluster := range clusters {
+			seen[cluster.ID()] = cluster
+		}
+	}
+
+	clusters := make([]*spi.Cluster, 0, len(seen))
+	for _, cluster := range seen {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].ID() < clusters[j].ID()
+	})
+	return clusters, nil
+}
@@ -0,0 +1,349 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/providers/mock"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// Mock clusters are all created two hours ago.
+var testClusters = []mock.ScenarioCluster{
+	{
+		ID:         "a-expired",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true"},
+	},
+	{
+		ID:         "b-errored",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true", clusterproperties.Status: clusterproperties.StatusCompletedError},
+		ExpiresIn:  24 * time.Hour,
+	},
+	{
+		ID:         "c-orphaned",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true", clusterproperties.JobID: "123", clusterproperties.Status: clusterproperties.StatusHealthy},
+		ExpiresIn:  24 * time.Hour,
+	},
+	{
+		ID:         "d-finished",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true", clusterproperties.JobID: "124", clusterproperties.Status: clusterproperties.StatusCompletedPassing},
+		ExpiresIn:  24 * time.Hour,
+	},
+	{
+		ID:         "e-pooled",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true", clusterproperties.PoolKey: "4.11/mock/osd", clusterproperties.Status: clusterproperties.StatusCompletedError},
+		ExpiresIn:  24 * time.Hour,
+	},
+	{
+		ID:         "f-kept",
+		Properties: map[string]string{clusterproperties.MadeByOSDe2e: "true", clusterproperties.OwnedBy: "keep", clusterproperties.Status: clusterproperties.StatusCompletedError},
+	},
+	{
+		ID:         "g-foreign",
+		Properties: map[string]string{},
+	},
+}
+
+var testPolicy = Policy{
+	StatusMaxAge: map[string]time.Duration{clusterproperties.StatusCompletedError: time.Hour},
+	OrphanAfter:  time.Hour,
+	Exclude:      []string{"properties.OwnedBy='keep'"},
+}
+
+func newTestProvider(t *testing.T, methods map[string]mock.MethodScript) *mock.MockProvider {
+	provider, err := mock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock provider: %v", err)
+	}
+	if err := provider.SetScenario(&mock.MockScenario{Clusters: testClusters, Methods: methods}); err != nil {
+		t.Fatalf("unable to set scenario: %v", err)
+	}
+	return provider
+}
+
+func actions(summary *Summary) map[string]Action {
+	actions := map[string]Action{}
+	for _, result := range summary.Results {
+		actions[result.ClusterID] = result.Action
+	}
+	return actions
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Policy    Policy
+		DryRun    bool
+		Methods   map[string]mock.MethodScript
+		Expected  map[string]Action
+		Remaining []string
+	}{
+		{
+			Name:   "expired only",
+			Policy: Policy{},
+			Expected: map[string]Action{
+				"a-expired":  ActionDeleted,
+				"b-errored":  ActionSkipped,
+				"c-orphaned": ActionSkipped,
+				"d-finished": ActionSkipped,
+				"e-pooled":   ActionSkipped,
+				"f-kept":     ActionDeleted,
+			},
+			Remaining: []string{"b-errored", "c-orphaned", "d-finished", "e-pooled", "g-foreign"},
+		},
+		{
+			Name:   "policy",
+			Policy: testPolicy,
+			Expected: map[string]Action{
+				"a-expired":  ActionDeleted,
+				"b-errored":  ActionDeleted,
+				"c-orphaned": ActionDeleted,
+				"d-finished": ActionSkipped,
+				"e-pooled":   ActionSkipped,
+				"f-kept":     ActionSkipped,
+			},
+			Remaining: []string{"d-finished", "e-pooled", "f-kept", "g-foreign"},
+		},
+		{
+			Name:   "dry run",
+			Policy: testPolicy,
+			DryRun: true,
+			Expected: map[string]Action{
+				"a-expired":  ActionWouldDelete,
+				"b-errored":  ActionWouldDelete,
+				"c-orphaned": ActionWouldDelete,
+				"d-finished": ActionSkipped,
+				"e-pooled":   ActionSkipped,
+				"f-kept":     ActionSkipped,
+			},
+			Remaining: []string{"a-expired", "b-errored", "c-orphaned", "d-finished", "e-pooled", "f-kept", "g-foreign"},
+		},
+		{
+			Name:    "failed deletion",
+			Policy:  testPolicy,
+			Methods: map[string]mock.MethodScript{"DeleteCluster": {Errors: []string{"", "boom"}}},
+			Expected: map[string]Action{
+				"a-expired":  ActionDeleted,
+				"b-errored":  ActionFailed,
+				"c-orphaned": ActionDeleted,
+				"d-finished": ActionSkipped,
+				"e-pooled":   ActionSkipped,
+				"f-kept":     ActionSkipped,
+			},
+			Remaining: []string{"b-errored", "d-finished", "e-pooled", "f-kept", "g-foreign"},
+		},
+		{
+			Name:   "max age and includes",
+			Policy: Policy{MaxAge: time.Hour, Include: []string{"id like 'a%'", "id='g-foreign'"}},
+			Expected: map[string]Action{
+				"a-expired": ActionDeleted,
+				"g-foreign": ActionDeleted,
+			},
+			Remaining: []string{"b-errored", "c-orphaned", "d-finished", "e-pooled", "f-kept"},
+		},
+		{
+			Name: "compound queries",
+			Policy: Policy{
+				MaxAge:  time.Hour,
+				Include: []string{"properties.MadeByOSDe2e='true' and properties.Status like 'completed-%'"},
+				Exclude: []string{"properties.Status='completed-error' and id like 'b%'"},
+			},
+			Expected: map[string]Action{
+				"b-errored":  ActionSkipped,
+				"d-finished": ActionDeleted,
+				"e-pooled":   ActionSkipped,
+				"f-kept":     ActionDeleted,
+			},
+			Remaining: []string{"a-expired", "b-errored", "c-orphaned", "e-pooled", "g-foreign"},
+		},
+	}
+
+	for _, test := range tests {
+		provider := newTestProvider(t, test.Methods)
+
+		summary, err := Run(context.Background(), provider, test.Policy, test.DryRun)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.Name, err)
+			continue
+		}
+		if got := actions(summary); !reflect.DeepEqual(got, test.Expected) {
+			t.Errorf("%s: expected actions %v, got %v", test.Name, test.Expected, got)
+		}
+		if summary.Failed() != (summary.Count(ActionFailed) > 0) || summary.DryRun != test.DryRun {
+			t.Errorf("%s: inconsistent summary %+v", test.Name, summary)
+		}
+
+		clusters, err := provider.ListClusters("")
+		if err != nil {
+			t.Fatalf("%s: unexpected error listing clusters: %v", test.Name, err)
+		}
+		remaining := []string{}
+		for _, cluster := range clusters {
+			if cluster.State() != spi.ClusterStateUninstalling {
+				remaining = append(remaining, cluster.ID())
+			}
+		}
+		if !reflect.DeepEqual(remaining, test.Remaining) {
+			t.Errorf("%s: expected %v to remain, got %v", test.Name, test.Remaining, remaining)
+		}
+	}
+}
+
+func TestRunInvalidPolicy(t *testing.T) {
+	provider := newTestProvider(t, nil)
+	for _, policy := range []Policy{{Include: []string{"id=="}}, {Exclude: []string{"bogus='x'"}}} {
+		if _, err := Run(context.Background(), provider, policy, true); err == nil {
+			t.Errorf("expected an error for policy %+v", policy)
+		}
+	}
+}
+
+func TestDecideStatusAge(t *testing.T) {
+	policy, err := testPolicy.compile()
+	if err != nil {
+		t.Fatalf("unable to compile policy: %v", err)
+	}
+	now := time.Now()
+	created := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		Name       string
+		Properties map[string]string
+		Delete     bool
+	}{
+		{"recycled by a live job", map[string]string{clusterproperties.JobID: "125", clusterproperties.Status: clusterproperties.StatusHealthy}, false},
+		{"orphaned", map[string]string{clusterproperties.JobID: "125", clusterproperties.Status: clusterproperties.StatusHealthy}, true},
+		{"recently errored", map[string]string{clusterproperties.Status: clusterproperties.StatusCompletedError}, false},
+		{"long errored", map[string]string{clusterproperties.Status: clusterproperties.StatusCompletedError}, true},
+		{"status never stamped", map[string]string{clusterproperties.Status: clusterproperties.StatusCompletedError, clusterproperties.StatusUpdated: ""}, true},
+	}
+
+	for i, test := range tests {
+		// Even tests set their status ten minutes ago, odd ones two hours ago.
+		updated := now.Add(-10 * time.Minute)
+		if i%2 == 1 {
+			updated = now.Add(-2 * time.Hour)
+		}
+		properties := map[string]string{}
+		for key, value := range test.Properties {
+			clusterproperties.Set(properties, key, value, updated)
+		}
+		if value, ok := test.Properties[clusterproperties.StatusUpdated]; ok {
+			properties[clusterproperties.StatusUpdated] = value
+		}
+
+		cluster := spi.NewClusterBuilder().ID("a").CreationTimestamp(created).Properties(properties).Build()
+		if deleted, reason := policy.decide(cluster, now); deleted != test.Delete {
+			t.Errorf("%s: expected deletion %v, got %v: %s", test.Name, test.Delete, deleted, reason)
+		}
+	}
+}
+
+func TestPolicyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "policy.yaml")
+	err := os.WriteFile(filename, []byte(`
+maxAge: 48h
+orphanAfter: 6h
+statusMaxAge:
+  completed-error: 2h
+exclude:
+  - properties.OwnedBy='keep'
+environments:
+  stage:
+    orphanAfter: 3h
+    statusMaxAge:
+      completed-failing: 4h
+    exclude:
+      - name like 'perf-%'
+  prod:
+    include:
+      - properties.MadeByOSDe2e='true' and region.id='us-east-1'
+`), os.FileMode(0o644))
+	if err != nil {
+		t.Fatalf("unable to write policy: %v", err)
+	}
+
+	file, err := LoadPolicyFile(filename)
+	if err != nil {
+		t.Fatalf("unexpected error loading policy: %v", err)
+	}
+
+	tests := []struct {
+		Environment string
+		Expected    Policy
+	}{
+		{
+			Environment: "int",
+			Expected: Policy{
+				MaxAge:       48 * time.Hour,
+				OrphanAfter:  6 * time.Hour,
+				StatusMaxAge: map[string]time.Duration{"completed-error": 2 * time.Hour},
+				Exclude:      []string{"properties.OwnedBy='keep'"},
+			},
+		},
+		{
+			Environment: "stage",
+			Expected: Policy{
+				MaxAge:       48 * time.Hour,
+				OrphanAfter:  3 * time.Hour,
+				StatusMaxAge: map[string]time.Duration{"completed-error": 2 * time.Hour, "completed-failing": 4 * time.Hour},
+				Exclude:      []string{"properties.OwnedBy='keep'", "name like 'perf-%'"},
+			},
+		},
+		{
+			Environment: "prod",
+			Expected: Policy{
+				MaxAge:       48 * time.Hour,
+				OrphanAfter:  6 * time.Hour,
+				StatusMaxAge: map[string]time.Duration{"completed-error": 2 * time.Hour},
+				Include:      []string{"properties.MadeByOSDe2e='true' and region.id='us-east-1'"},
+				Exclude:      []string{"properties.OwnedBy='keep'"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if policy := file.ForEnvironment(test.Environment); !reflect.DeepEqual(policy, test.Expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.Environment, test.Expected, policy)
+		}
+	}
+}
+
+func TestWriteReports(t *testing.T) {
+	summary := &Summary{
+		Environment: "stage",
+		Results: []Result{
+			{ClusterID: "a", Action: ActionDeleted, Reason: "expired"},
+			{ClusterID: "b", Action: ActionSkipped, Reason: "within policy"},
+			{ClusterID: "c", Action: ActionFailed, Reason: "expired", Error: "boom"},
+		},
+	}
+
+	reportDir := t.TempDir()
+	if err := summary.WriteReports(reportDir); err != nil {
+		t.Fatalf("unexpected error writing reports: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(reportDir, SummaryFile)); err != nil {
+		t.Errorf("expected a JSON summary: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportDir, JUnitFile))
+	if err != nil {
+		t.Fatalf("expected a JUnit summary: %v", err)
+	}
+	suites := reporters.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("unable to parse JUnit summary: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.TestSuites[0].Skipped != 1 {
+		t.Errorf("unexpected JUnit counts: %d tests, %d failures, %d skipped", suites.Tests, suites.Failures, suites.TestSuites[0].Skipped)
+	}
+}
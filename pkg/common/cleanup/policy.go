@@ -0,0 +1,160 @@
+package cleanup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultInclude selects the clusters osde2e made.
+var DefaultInclude = []string{fmt.Sprintf("properties.%s='true'", clusterproperties.MadeByOSDe2e)}
+
+// Policy decides which clusters are deleted. Clusters matching an exclude are never deleted, even
+// once expired. Other expired clusters are always deleted; the thresholds only make cleanup more
+// aggressive. A zero threshold is disabled.
+//
+// Status thresholds are measured from when the Status or JobID property was last set, so that
+// recycled clusters aren't judged by their age. Clusters without a StatusUpdated property, set before
+// it was recorded, are measured from their creation instead.
+type Policy struct {
+	// MaxAge deletes clusters older than this whatever their status.
+	MaxAge time.Duration `yaml:"maxAge"`
+
+	// StatusMaxAge deletes clusters whose Status property is a key once they have had that status for
+	// longer than its value, e.g. clusters left in completed-error after an hour.
+	StatusMaxAge map[string]time.Duration `yaml:"statusMaxAge"`
+
+	// OrphanAfter deletes clusters that a job took but never marked completed once their status or
+	// job hasn't changed for this long, as the job that owned them has most likely died.
+	OrphanAfter time.Duration `yaml:"orphanAfter"`
+
+	// Include are cluster queries, any of which a cluster must match to be considered.
+	// Defaults to DefaultInclude.
+	Include []string `yaml:"include"`
+
+	// Exclude are cluster queries whose matches are never deleted, including expired ones.
+	Exclude []string `yaml:"exclude"`
+}
+
+// PolicyFile is a policy with overrides for individual environments.
+type PolicyFile struct {
+	Policy `yaml:",inline"`
+
+	// Environments override the policy for the provider environment they are keyed by.
+	Environments map[string]Policy `yaml:"environments"`
+}
+
+// LoadPolicyFile reads a YAML policy file.
+func LoadPolicyFile(filename string) (*PolicyFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cleanup policy: %v", err)
+	}
+
+	file := &PolicyFile{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("error parsing cleanup policy %s: %v", filename, err)
+	}
+	return file, nil
+}
+
+// ForEnvironment returns the policy for env. Thresholds and includes set for the environment replace
+// the defaults, status thresholds are merged and excludes are added to the default excludes.
+func (f *PolicyFile) ForEnvironment(env string) Policy {
+	policy := f.Policy
+	override, ok := f.Environments[env]
+	if !ok {
+		return policy
+	}
+
+	if override.MaxAge != 0 {
+		policy.MaxAge = override.MaxAge
+	}
+	if override.OrphanAfter != 0 {
+		policy.OrphanAfter = override.OrphanAfter
+	}
+	if len(override.StatusMaxAge) > 0 {
+		statusMaxAge := map[string]time.Duration{}
+		for status, maxAge := range f.StatusMaxAge {
+			statusMaxAge[status] = maxAge
+		}
+		for status, maxAge := range override.StatusMaxAge {
+			statusMaxAge[status] = maxAge
+		}
+		policy.StatusMaxAge = statusMaxAge
+	}
+	if len(override.Include) > 0 {
+		policy.Include = override.Include
+	}
+	policy.Exclude = append(append([]string{}, f.Exclude...), override.Exclude...)
+	return policy
+}
+
+// compiledPolicy is a policy whose excludes have been parsed. Includes are passed to the provider.
+type compiledPolicy struct {
+	Policy
+
+	exclude []*spi.ClusterQuery
+}
+
+func (p Policy) compile() (*compiledPolicy, error) {
+	compiled := &compiledPolicy{Policy: p}
+	if len(compiled.Include) == 0 {
+		compiled.Include = DefaultInclude
+	}
+
+	for _, query := range compiled.Include {
+		if _, err := spi.ParseClusterQuery(query); err != nil {
+			return nil, fmt.Errorf("invalid include %q: %v", query, err)
+		}
+	}
+	for _, query := range compiled.Exclude {
+		parsed, err := spi.ParseClusterQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude %q: %v", query, err)
+		}
+		compiled.exclude = append(compiled.exclude, parsed)
+	}
+	return compiled, nil
+}
+
+// decide returns whether a cluster should be deleted and why.
+func (p *compiledPolicy) decide(cluster *spi.Cluster, now time.Time) (bool, string) {
+	for i, query := range p.exclude {
+		if query.Matches(cluster) {
+			return false, fmt.Sprintf("excluded by %q", p.Exclude[i])
+		}
+	}
+
+	if expires := cluster.ExpirationTimestamp(); !expires.IsZero() && now.After(expires) {
+		return true, fmt.Sprintf("expired at %s", expires.UTC().Format(time.RFC3339))
+	}
+
+	properties := cluster.Properties()
+	if properties[clusterproperties.PoolKey] != "" {
+		return false, "managed by the cluster pool"
+	}
+
+	age := now.Sub(cluster.CreationTimestamp())
+	status := properties[clusterproperties.Status]
+	statusAge := age
+	if updated, ok := clusterproperties.StatusUpdatedAt(properties); ok {
+		statusAge = now.Sub(updated)
+	}
+
+	if p.MaxAge != 0 && age > p.MaxAge {
+		return true, fmt.Sprintf("older than %s", p.MaxAge)
+	}
+	if maxAge, ok := p.StatusMaxAge[status]; ok && maxAge != 0 && statusAge > maxAge {
+		return true, fmt.Sprintf("%s for longer than %s", status, maxAge)
+	}
+	if p.OrphanAfter != 0 && statusAge > p.OrphanAfter && properties[clusterproperties.JobID] != "" && !strings.HasPrefix(status, clusterproperties.StatusCompleted) {
+		return true, fmt.Sprintf("job %s left it in status %q for longer than %s", properties[clusterproperties.JobID], status, p.OrphanAfter)
+	}
+	return false, "within policy"
+}
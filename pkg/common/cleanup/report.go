@@ -0,0 +1,86 @@
+package cleanup
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+)
+
+const (
+	// SummaryFile is the name of the JSON summary written to the report directory.
+	SummaryFile = "cleanup-summary.json"
+
+	// JUnitFile is the name of the JUnit summary written to the report directory.
+	JUnitFile = "junit_cleanup.xml"
+)
+
+// WriteReports writes the JSON and JUnit summaries to reportDir.
+func (s *Summary) WriteReports(reportDir string) error {
+	if err := os.MkdirAll(reportDir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating report directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cleanup summary: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, SummaryFile), data, os.FileMode(0o644)); err != nil {
+		return fmt.Errorf("error writing cleanup summary: %v", err)
+	}
+
+	data, err = xml.MarshalIndent(s.junit(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cleanup junit: %v", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(filepath.Join(reportDir, JUnitFile), data, os.FileMode(0o644)); err != nil {
+		return fmt.Errorf("error writing cleanup junit: %v", err)
+	}
+	return nil
+}
+
+// junit reports a test case per cluster, failing those whose deletion failed and skipping those left alone.
+func (s *Summary) junit() reporters.JUnitTestSuites {
+	suite := reporters.JUnitTestSuite{
+		Name:      "cleanup",
+		Package:   s.Environment,
+		Time:      s.Duration,
+		Timestamp: s.StartedAt.Format("2006-01-02T15:04:05"),
+	}
+
+	for _, result := range s.Results {
+		testCase := reporters.JUnitTestCase{
+			Name:      fmt.Sprintf("[cleanup] %s %s", result.ClusterID, result.Name),
+			Classname: "cleanup",
+			Status:    string(result.Action),
+			SystemOut: result.Reason,
+		}
+
+		switch result.Action {
+		case ActionSkipped:
+			testCase.Skipped = &reporters.JUnitSkipped{Message: "skipped - " + result.Reason}
+			suite.Skipped++
+		case ActionFailed:
+			testCase.Failure = &reporters.JUnitFailure{
+				Message:     fmt.Sprintf("error deleting cluster: %s", result.Error),
+				Type:        "failed",
+				Description: result.Reason,
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+	}
+
+	return reporters.JUnitTestSuites{
+		TestSuites: []reporters.JUnitTestSuite{suite},
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Time:       suite.Time,
+	}
+}
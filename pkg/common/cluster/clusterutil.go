@@ -2,11 +2,14 @@ package cluster
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver"
@@ -18,21 +21,24 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/logging"
 	"github.com/openshift/osde2e/pkg/common/metadata"
+	"github.com/openshift/osde2e/pkg/common/pool"
 	"github.com/openshift/osde2e/pkg/common/providers"
 	"github.com/openshift/osde2e/pkg/common/spi"
 	"github.com/openshift/osde2e/pkg/common/util"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
-	// errorWindow is the number of checks made to determine if a cluster has truly failed.
+	// errorWindow is the number of attempts in a row to get a cluster's clients that must fail before giving up.
 	errorWindow = 20
+	// readinessRecheckInterval is how long to wait before watching for readiness again when the full health
+	// checks fail after the cluster settled.
+	readinessRecheckInterval = 30 * time.Second
 	// pendingPodThreshold is the maximum number of times a pod is allowed to be in pending state before erroring out in PollClusterHealth.
 	pendingPodThreshold = 10
 )
@@ -66,24 +72,84 @@ func GetClusterVersion(provider spi.Provider, clusterID string) (*semver.Version
 }
 
 // ScaleCluster will scale the cluster up to the provided size.
-func ScaleCluster(clusterID string, numComputeNodes int) error {
+func ScaleCluster(ctx context.Context, clusterID string, numComputeNodes int) error {
 	provider, err := providers.ClusterProvider()
 	if err != nil {
 		return fmt.Errorf("error getting cluster provisioning client: %v", err)
 	}
 
-	err = provider.ScaleCluster(clusterID, numComputeNodes)
+	scaleStarted := time.Now()
+	err = provider.ScaleClusterContext(ctx, clusterID, numComputeNodes)
 	if err != nil {
 		return fmt.Errorf("error trying to scale cluster: %v", err)
 	}
 
+	if _, err := VerifyScale(ctx, clusterID, numComputeNodes, scaleStarted, nil); err != nil {
+		return err
+	}
+
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
-	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID, nil, false, true)
+	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, nil, false, true)
+}
+
+// VerifyScale blocks until the cluster's default machine pool and compute nodes have at least numComputeNodes spread
+// evenly across the cluster's availability zones, and records how long each node took to join or leave since
+// started in the metadata. Clusters without the machine API or a default machine pool aren't verified.
+func VerifyScale(ctx context.Context, clusterID string, numComputeNodes int, started time.Time, logger *log.Logger) (healthchecks.ScaleResult, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	provider, err := providers.ClusterProvider()
+	if err != nil {
+		return healthchecks.ScaleResult{}, fmt.Errorf("error getting cluster provisioning client: %v", err)
+	}
+
+	expected := healthchecks.ScaleExpectation{Nodes: numComputeNodes}
+	// Without zones from the provider, the zones of the worker MachineSets are expected.
+	if zoneProvider, ok := provider.(availabilityZoneProvider); ok {
+		if expected.Zones, err = zoneProvider.AvailabilityZonesContext(ctx, clusterID); err != nil {
+			return healthchecks.ScaleResult{}, fmt.Errorf("error getting availability zones: %v", err)
+		}
+	}
+
+	restConfig, _, err := ClusterConfig(clusterID)
+	if err != nil {
+		return healthchecks.ScaleResult{}, fmt.Errorf("error getting cluster config: %v", err)
+	}
+	clients, err := healthchecks.NewClients(restConfig)
+	if err != nil {
+		return healthchecks.ScaleResult{}, err
+	}
+
+	scaleCtx, cancel := context.WithTimeout(ctx, time.Duration(viper.GetInt64(config.Cluster.InstallTimeout))*time.Minute)
+	defer cancel()
+
+	logger.Printf("Waiting for cluster '%s' to scale to %d compute nodes across %v...", clusterID, numComputeNodes, expected.Zones)
+	result, err := healthchecks.WaitForScale(scaleCtx, clients, expected, started, logger)
+	if errors.Is(err, healthchecks.ErrNotApplicable) {
+		logger.Printf("Not verifying the scale of cluster '%s', it has no MachineSets of a default machine pool", clusterID)
+		return result, nil
+	}
+	for node, latency := range result.ScaleUp {
+		metadata.Instance.SetScaleUpLatency(node, latency.Seconds())
+	}
+	for node, latency := range result.ScaleDown {
+		metadata.Instance.SetScaleDownLatency(node, latency.Seconds())
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed verifying cluster scale: %w", err)
+	}
+	return result, nil
+}
+
+// availabilityZoneProvider is implemented by providers that know which availability zones a cluster's compute
+// nodes are spread across.
+type availabilityZoneProvider interface {
+	AvailabilityZonesContext(ctx context.Context, clusterID string) ([]string, error)
 }
 
 // WaitForClusterReadyPostInstall blocks until the cluster is ready for testing using mechanisms appropriate
-// for a newly-installed cluster.
-func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error {
+// for a newly-installed cluster. Waiting stops early if ctx is cancelled.
+func WaitForClusterReadyPostInstall(ctx context.Context, clusterID string, logger *log.Logger) error {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
 	provider, err := providers.ClusterProvider()
@@ -98,7 +164,7 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 	}
 	logger.Printf("Waiting %v minutes for cluster '%s' to be ready...\n", installTimeout, clusterID)
 
-	_, err = waitForOCMProvisioning(provider, clusterID, installTimeout, logger, false)
+	_, err = waitForOCMProvisioning(ctx, provider, clusterID, installTimeout, logger, false)
 	if err != nil {
 		return fmt.Errorf("OCM never became ready: %w", err)
 	}
@@ -124,19 +190,21 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	healthcheckCtx, cancel := context.WithTimeout(ctx, duration)
 	defer cancel()
-	err = healthchecks.CheckHealthcheckJob(kubeClient, ctx, nil)
+	healthcheckStarted := time.Now()
+	err = healthchecks.CheckHealthcheckJob(kubeClient, healthcheckCtx, nil)
+	metadata.Instance.RecordHealthcheckJob(time.Since(healthcheckStarted), err)
 	if err != nil {
 		return fmt.Errorf("cluster failed health check: %w", err)
 	}
 
-	cluster, err := provider.GetCluster(clusterID)
+	cluster, err := provider.GetClusterContext(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("failed getting cluster from provider: %w", err)
 	}
 
-	if err := provider.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusHealthy); err != nil {
+	if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, clusterproperties.StatusHealthy); err != nil {
 		return fmt.Errorf("error trying to add healthy property to cluster ID %s: %w", cluster.ID(), err)
 	}
 	return nil
@@ -144,32 +212,36 @@ func WaitForClusterReadyPostInstall(clusterID string, logger *log.Logger) error
 
 // WaitForClusterReadyPostUpgrade blocks until the cluster is ready for testing using healthcheck mechanisms appropriate
 // for after a cluster version upgrade.
-func WaitForClusterReadyPostUpgrade(clusterID string, logger *log.Logger) error {
+func WaitForClusterReadyPostUpgrade(ctx context.Context, clusterID string, logger *log.Logger) error {
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
-	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID, logger, true, false)
+	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, true, false)
 }
 
 // WaitForClusterReadyPostScale blocks until the cluster is ready for testing and uses healthcheck mechanisms appropriate
 // for after the cluster has been scaled.
-func WaitForClusterReadyPostScale(clusterID string, logger *log.Logger) error {
+func WaitForClusterReadyPostScale(ctx context.Context, clusterID string, logger *log.Logger) error {
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
-	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID, logger, false, false)
+	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, false, false)
 }
 
 // WaitForClusterReadyPostWake blocks until the cluster is ready for testing, deletes errored pods, and then uses
 // healthcheck mechanisms appropriate for after the cluster resumed from hibernation.
-func WaitForClusterReadyPostWake(clusterID string, logger *log.Logger) error {
+func WaitForClusterReadyPostWake(ctx context.Context, clusterID string, logger *log.Logger) error {
 	log.Printf("Cluster %s just woke up, waiting for 10 minutes...", clusterID)
 	provider, err := providers.ClusterProvider()
 	if err != nil {
 		return fmt.Errorf("error getting cluster provider: %s", err.Error())
 	}
-	cluster, err := provider.GetCluster(clusterID)
+	cluster, err := provider.GetClusterContext(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("error getting cluster from provider: %s", err.Error())
 	}
-	provider.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusHealthCheck)
-	time.Sleep(10 * time.Minute)
+	provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, clusterproperties.StatusHealthCheck)
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for cluster %s to wake: %w", clusterID, ctx.Err())
+	case <-time.After(10 * time.Minute):
+	}
 
 	restConfig, _, err := ClusterConfig(clusterID)
 	if err != nil {
@@ -183,7 +255,7 @@ func WaitForClusterReadyPostWake(clusterID string, logger *log.Logger) error {
 
 	var continueToken string
 	nextPods := func() (*corev1.PodList, error) {
-		return kubeClient.CoreV1().Pods("").List(context.TODO(), v1.ListOptions{Continue: continueToken})
+		return kubeClient.CoreV1().Pods("").List(ctx, v1.ListOptions{Continue: continueToken})
 	}
 	for list, err := nextPods(); len(list.Items) > 0; list, err = nextPods() {
 		if err != nil {
@@ -195,16 +267,16 @@ func WaitForClusterReadyPostWake(clusterID string, logger *log.Logger) error {
 				if len(pod.Finalizers) > 0 {
 					log.Printf("Removing finalizers from %s", pod.Name)
 					pod.Finalizers = []string{}
-					kubeClient.CoreV1().Pods(pod.Namespace).Update(context.TODO(), &pod, v1.UpdateOptions{})
+					kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, &pod, v1.UpdateOptions{})
 				}
 				log.Printf("Deleting pod %s", pod.Name)
-				err = kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, v1.DeleteOptions{})
+				err = kubeClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, v1.DeleteOptions{})
 				if err != nil {
 					log.Printf("Error deleting stale pod: %s", err.Error())
 				}
 			}
 			if len(pod.OwnerReferences) > 0 && pod.OwnerReferences[0].Kind == "Job" {
-				err = kubeClient.BatchV1().Jobs(pod.Namespace).Delete(context.TODO(), pod.OwnerReferences[0].Name, v1.DeleteOptions{})
+				err = kubeClient.BatchV1().Jobs(pod.Namespace).Delete(ctx, pod.OwnerReferences[0].Name, v1.DeleteOptions{})
 				if err != nil {
 					log.Printf("Error deleting stale job: %s", err.Error())
 				}
@@ -217,11 +289,10 @@ func WaitForClusterReadyPostWake(clusterID string, logger *log.Logger) error {
 	}
 
 	podErrorTracker.NewPodErrorTracker(pendingPodThreshold)
-	viper.Set(config.Cluster.CleanCheckRuns, 5)
-	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID, logger, false, false)
+	return waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx, clusterID, logger, false, false)
 }
 
-func waitForOCMProvisioning(provider spi.Provider, clusterID string, installTimeout int64, logger *log.Logger, isUpgrade bool) (becameReadyAt time.Time, err error) {
+func waitForOCMProvisioning(ctx context.Context, provider spi.Provider, clusterID string, installTimeout int64, logger *log.Logger, isUpgrade bool) (becameReadyAt time.Time, err error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	readinessSet := false
 	var readinessStarted time.Time
@@ -232,19 +303,20 @@ func waitForOCMProvisioning(provider spi.Provider, clusterID string, installTime
 		healthcheckStatus = clusterproperties.StatusUpgradeHealthCheck
 	}
 
-	return readinessStarted, wait.PollImmediate(30*time.Second, time.Duration(installTimeout)*time.Minute, func() (bool, error) {
-		cluster, err := provider.GetCluster(clusterID)
+	return readinessStarted, wait.PollImmediateWithContext(ctx, 30*time.Second, time.Duration(installTimeout)*time.Minute, func(ctx context.Context) (bool, error) {
+		cluster, err := provider.GetClusterContext(ctx, clusterID)
 		if err != nil {
 			logger.Printf("Error fetching cluster details from provider: %s", err)
 			return false, nil
 		}
 
 		metadata.Instance.IncrementHealthcheckIteration()
+		metadata.Instance.RecordClusterState(string(cluster.State()))
 		properties := cluster.Properties()
 		currentStatus := properties[clusterproperties.Status]
 
 		if currentStatus == clusterproperties.StatusProvisioning && !readinessSet {
-			err = provider.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusWaitingForReady)
+			err = provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, clusterproperties.StatusWaitingForReady)
 			if err != nil {
 				logger.Printf("Error adding property to cluster: %s", err.Error())
 				return false, nil
@@ -256,7 +328,7 @@ func waitForOCMProvisioning(provider spi.Provider, clusterID string, installTime
 				metadata.Instance.SetTimeToOCMReportingInstalled(time.Since(clusterStarted).Seconds())
 			}
 
-			if err := provider.AddProperty(cluster, clusterproperties.Status, healthcheckStatus); err != nil {
+			if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, healthcheckStatus); err != nil {
 				logger.Printf("error trying to add health-check property to cluster ID %s: %v", cluster.ID(), err)
 				return false, nil
 			}
@@ -271,7 +343,7 @@ func waitForOCMProvisioning(provider spi.Provider, clusterID string, installTime
 	})
 }
 
-func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, logger *log.Logger, isUpgrade, overrideSkipCheck bool) error {
+func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(ctx context.Context, clusterID string, logger *log.Logger, isUpgrade, overrideSkipCheck bool) error {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	if viper.GetBool(config.Tests.SkipClusterHealthChecks) && !overrideSkipCheck {
 		logger.Println("Skipping health checks...")
@@ -292,72 +364,103 @@ func waitForClusterReadyWithOverrideAndExpectedNumberOfNodes(clusterID string, l
 
 	installTimeout := viper.GetInt64(config.Cluster.InstallTimeout)
 	logger.Printf("Waiting %v minutes for cluster '%s' to be ready...\n", installTimeout, clusterID)
-	cleanRunsNeeded := viper.GetInt(config.Cluster.CleanCheckRuns)
-	cleanRuns := 0
-	errRuns := 0
 
-	readinessStarted, err := waitForOCMProvisioning(provider, clusterID, installTimeout, logger, isUpgrade)
+	settle, err := time.ParseDuration(viper.GetString(config.HealthChecks.Settle))
+	if err != nil {
+		return fmt.Errorf("failed parsing health check settle duration: %w", err)
+	}
+
+	readinessStarted, err := waitForOCMProvisioning(ctx, provider, clusterID, installTimeout, logger, isUpgrade)
 	if err != nil {
 		return fmt.Errorf("OCM never became ready: %w", err)
 	}
 
-	cluster, err := provider.GetCluster(clusterID)
+	cluster, err := provider.GetClusterContext(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("Error fetching cluster details from provider: %w", err)
 	}
 
-	if pollErr := wait.PollImmediate(30*time.Second, time.Duration(installTimeout)*time.Minute, func() (bool, error) {
-		if cluster.State() != spi.ClusterStateReady {
-			logger.Printf("Cluster is not ready, current status '%s'.", cluster.State())
+	readinessCtx, cancel := context.WithTimeout(ctx, time.Duration(installTimeout)*time.Minute)
+	defer cancel()
+
+	// The cluster's API may not be reachable yet, so only give up after errorWindow attempts in a row fail.
+	var clients *healthchecks.Clients
+	errRuns := 0
+	err = wait.PollImmediateUntilWithContext(readinessCtx, 30*time.Second, func(ctx context.Context) (bool, error) {
+		restConfig, _, err := ClusterConfig(clusterID)
+		if err == nil {
+			clients, err = healthchecks.NewClients(restConfig)
+		}
+		if err != nil {
+			errRuns++
+			logger.Printf("Error getting cluster clients: %v", err)
+			if errRuns >= errorWindow {
+				return false, fmt.Errorf("error getting cluster clients: %w", err)
+			}
 			return false, nil
 		}
+		return true, nil
+	})
+	if err != nil {
+		if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, unhealthyStatus); err != nil {
+			log.Printf("error trying to add unhealthy property to cluster ID %s: %v", clusterID, err)
+		}
+		return fmt.Errorf("failed waiting for cluster health: %w", err)
+	}
 
+	currentStatus := cluster.Properties()[clusterproperties.Status]
+	setFailures := func(failures []string) {
+		failureString := strings.Join(failures, ",")
+		if len(failures) > 0 && currentStatus != failureString {
+			if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, failureString); err != nil {
+				log.Printf("error trying to add property to cluster ID %s: %v", clusterID, err)
+			}
+			currentStatus = failureString
+		}
+	}
+	// PollClusterHealth counts its own iterations.
+	onChange := func(problems map[string][]string) {
 		metadata.Instance.IncrementHealthcheckIteration()
-		properties := cluster.Properties()
-		currentStatus := properties[clusterproperties.Status]
+		failures := make([]string, 0, len(problems))
+		for check := range problems {
+			failures = append(failures, check)
+		}
+		sort.Strings(failures)
+		setFailures(failures)
+	}
 
-		if success, failures, err := PollClusterHealth(clusterID, logger); success {
-			cleanRuns++
-			logger.Printf("Clean run %d/%d...", cleanRuns, cleanRunsNeeded)
-			errRuns = 0
-			if cleanRuns == cleanRunsNeeded {
-				return true, nil
+	// Once nodes, operators, machines and pods settle, confirm with the full set of health checks, and go back
+	// to waiting if any of those still fail.
+	for {
+		if err := healthchecks.WaitForReadiness(readinessCtx, clients, settle, onChange, logger); err != nil {
+			if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, unhealthyStatus); err != nil {
+				log.Printf("error trying to add unhealthy property to cluster ID %s: %v", clusterID, err)
 			}
-			return false, nil
-		} else {
-			if err != nil {
-				errRuns++
-				logger.Printf("Error in PollClusterHealth: %v", err)
-				if errRuns >= errorWindow {
-					if err := provider.AddProperty(cluster, clusterproperties.Status, unhealthyStatus); err != nil {
-						log.Printf("error trying to add unhealthy property to cluster ID %s: %v", clusterID, err)
-					}
-					return false, nil
-				}
-			}
-			cleanRuns = 0
+			return fmt.Errorf("failed waiting for cluster health: %w", err)
+		}
 
-			failureString := strings.Join(failures, ",")
-			if currentStatus != failureString {
-				err = provider.AddProperty(cluster, clusterproperties.Status, failureString)
-				if err != nil {
-					log.Printf("error trying to add property to cluster ID %s: %v", clusterID, err)
-				}
-			}
+		success, failures, err := PollClusterHealth(clusterID, logger)
+		if success {
+			break
+		}
+		logger.Printf("Cluster settled but health checks failed: %v %v", failures, err)
+		setFailures(failures)
 
-			return false, nil
+		select {
+		case <-readinessCtx.Done():
+			return fmt.Errorf("failed waiting for cluster health: %w", readinessCtx.Err())
+		case <-time.After(readinessRecheckInterval):
 		}
-	}); pollErr != nil {
-		return fmt.Errorf("failed polling for cluster health: %w", err)
 	}
-	// polling succeeded and the cluster is healthy
+
+	// the cluster settled and is healthy
 	if metadata.Instance.TimeToClusterReady == 0 {
 		metadata.Instance.SetTimeToClusterReady(time.Since(readinessStarted).Seconds())
 	} else {
 		metadata.Instance.SetTimeToUpgradedClusterReady(time.Since(readinessStarted).Seconds())
 	}
 
-	if err := provider.AddProperty(cluster, clusterproperties.Status, healthyStatus); err != nil {
+	if err := provider.AddPropertyContext(ctx, cluster, clusterproperties.Status, healthyStatus); err != nil {
 		return fmt.Errorf("error trying to add healthy property to cluster ID %s: %w", cluster.ID(), err)
 	}
 	return nil
@@ -399,86 +502,147 @@ func ClusterConfig(clusterID string) (restConfig *rest.Config, providerType stri
 func PollClusterHealth(clusterID string, logger *log.Logger) (status bool, failures []string, err error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
-	logger.Print("Polling Cluster Health...\n")
+	metadata.Instance.IncrementHealthcheckIteration()
+	iteration := int(metadata.Instance.HealthCheckIteration)
 
-	restConfig, providerType, err := ClusterConfig(clusterID)
+	results, err := CheckClusterHealth(context.TODO(), clusterID, logger)
 	if err != nil {
-		logger.Printf("Error getting cluster config: %v\n", err)
+		logger.Printf("Error checking cluster health: %v\n", err)
+		healthCheckHistory.add(iteration, []healthchecks.Result{
+			{Name: checksNotRun, Status: healthchecks.StatusError, Message: err.Error()},
+		})
 		return false, nil, nil
 	}
+	healthCheckHistory.add(iteration, results)
 
-	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	var healthErr *multierror.Error
+	for _, result := range results {
+		if result.Healthy() {
+			continue
+		}
+		failures = append(failures, result.Name)
+		if result.Status == healthchecks.StatusError {
+			healthErr = multierror.Append(healthErr, fmt.Errorf("%s: %s", result.Name, result.Message))
+		}
+	}
+
+	return len(failures) == 0, failures, healthErr.ErrorOrNil()
+}
+
+// HealthCheckRecord is the result of a health check run by PollClusterHealth.
+type HealthCheckRecord struct {
+	healthchecks.Result
+
+	// Iteration is the run's health check iteration, as kept in the metadata, when the checks ran.
+	Iteration int
+	Checked   time.Time
+}
+
+// checksNotRun names the record of a PollClusterHealth iteration whose checks couldn't be run at all.
+const checksNotRun = "healthchecks"
+
+// healthCheckHistory keeps every result from PollClusterHealth, to be stored with the job.
+var healthCheckHistory healthCheckRecords
+
+type healthCheckRecords struct {
+	mu      sync.Mutex
+	records []HealthCheckRecord
+}
+
+func (h *healthCheckRecords) add(iteration int, results []healthchecks.Result) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	checked := time.Now().UTC()
+	for _, result := range results {
+		h.records = append(h.records, HealthCheckRecord{Result: result, Iteration: iteration, Checked: checked})
+	}
+}
+
+// HealthCheckHistory returns the results of the health checks run by PollClusterHealth so far.
+func HealthCheckHistory() []HealthCheckRecord {
+	healthCheckHistory.mu.Lock()
+	defer healthCheckHistory.mu.Unlock()
+
+	return append([]HealthCheckRecord{}, healthCheckHistory.records...)
+}
+
+// CheckClusterHealth runs the health checks selected for the cluster's provider and returns their results.
+// An error means the checks couldn't be run at all.
+// param clusterID: If specified, Provider will be discovered through OCM. If the empty string,
+// assume we are running in a cluster and use in-cluster REST config instead.
+func CheckClusterHealth(ctx context.Context, clusterID string, logger *log.Logger) ([]healthchecks.Result, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Polling Cluster Health...\n")
+
+	restConfig, providerType, err := ClusterConfig(clusterID)
 	if err != nil {
-		logger.Printf("Error generating Kube Clientset: %v\n", err)
-		return false, nil, nil
+		return nil, fmt.Errorf("error getting cluster config: %v", err)
 	}
 
-	oscfg, err := osconfig.NewForConfig(restConfig)
+	checks, err := healthchecks.ChecksFor(providerType)
 	if err != nil {
-		logger.Printf("Error generating OpenShift Clientset: %v\n", err)
-		return false, nil, nil
+		return nil, err
+	}
+	if len(checks) == 0 {
+		logger.Printf("No health checks selected for %q", providerType)
+		return nil, nil
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	clients, err := healthchecks.NewClients(restConfig)
 	if err != nil {
-		logger.Printf("Error generating Dynamic Clientset: %v\n", err)
-		return false, nil, nil
+		return nil, err
 	}
 
-	clusterHealthy := true
+	return healthchecks.Run(ctx, checks, clients, logger), nil
+}
 
-	var healthErr *multierror.Error
-	switch providerType {
-	case "rosa":
-		fallthrough
-	case "ocm":
-		if check, err := healthchecks.CheckCVOReadiness(oscfg.ConfigV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "cvo")
-			clusterHealthy = false
-		}
+// MonitorHealth runs the cluster's health checks in the background until the returned function is called,
+// which adds what was seen during the phase to the health timeline in the report directory.
+// Monitoring is best effort: if it can't be started, the returned function does nothing.
+func MonitorHealth(ctx context.Context, phase string, logger *log.Logger) (stop func()) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+	noop := func() {}
 
-		if check, err := healthchecks.CheckNodeHealth(kubeClient.CoreV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "node")
-			clusterHealthy = false
-		}
+	interval, err := time.ParseDuration(viper.GetString(config.HealthChecks.MonitorInterval))
+	if err != nil {
+		logger.Printf("Not monitoring cluster health, invalid interval: %v", err)
+		return noop
+	}
+	if interval <= 0 || viper.GetBool(config.Tests.SkipClusterHealthChecks) {
+		return noop
+	}
 
-		if check, err := healthchecks.CheckMachinesObjectState(dynamicClient, logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "machine")
-			clusterHealthy = false
-		}
+	restConfig, providerType, err := ClusterConfig(viper.GetString(config.Cluster.ID))
+	if err != nil {
+		logger.Printf("Not monitoring cluster health, error getting cluster config: %v", err)
+		return noop
+	}
 
-		if check, err := healthchecks.CheckOperatorReadiness(oscfg.ConfigV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "operator")
-			clusterHealthy = false
-		}
+	checks, err := healthchecks.ChecksFor(providerType)
+	if err != nil || len(checks) == 0 {
+		logger.Printf("Not monitoring cluster health, no health checks selected: %v", err)
+		return noop
+	}
 
-		if check, err := healthchecks.CheckCerts(kubeClient.CoreV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "cert")
-			clusterHealthy = false
-		}
+	clients, err := healthchecks.NewClients(restConfig)
+	if err != nil {
+		logger.Printf("Not monitoring cluster health: %v", err)
+		return noop
+	}
 
-		if check, err := healthchecks.CheckReplicaCountForDaemonSets(kubeClient.AppsV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "daemonset")
-			clusterHealthy = false
-		}
+	logger.Printf("Monitoring cluster health every %s during %s", interval, phase)
+	monitor := healthchecks.NewMonitor(phase, checks, clients, interval)
+	monitor.Start(ctx)
 
-		if check, err := healthchecks.CheckReplicaCountForReplicaSets(kubeClient.AppsV1(), logger); !check || err != nil {
-			healthErr = multierror.Append(healthErr, err)
-			failures = append(failures, "replicaset")
-			clusterHealthy = false
+	return func() {
+		timeline := monitor.Stop()
+		logger.Printf("Cluster health during %s: %d samples, %d transitions", phase, timeline.Samples, len(timeline.Events))
+		if err := healthchecks.WriteTimeline(viper.GetString(config.ReportDir), timeline); err != nil {
+			logger.Printf("Error writing health timeline: %v", err)
 		}
-
-	default:
-		logger.Printf("No provisioner-specific logic for %q", providerType)
 	}
-
-	return clusterHealthy, failures, healthErr.ErrorOrNil()
 }
 
 func getRestConfig(provider spi.Provider, clusterID string) (*rest.Config, error) {
@@ -510,7 +674,8 @@ func getRestConfig(provider spi.Provider, clusterID string) (*rest.Config, error
 }
 
 // ProvisionCluster will provision a cluster and immediately return.
-func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
+// Provider calls made while provisioning are abandoned once ctx is cancelled.
+func ProvisionCluster(ctx context.Context, logger *log.Logger) (*spi.Cluster, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	provider, err := providers.ClusterProvider()
@@ -521,13 +686,32 @@ func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
 	var cluster *spi.Cluster
 	// create a new cluster if no ID is specified
 	clusterID := viper.GetString(config.Cluster.ID)
+	if clusterID == "" && viper.GetBool(config.Pool.Enabled) {
+		lease, err := pool.NewFromConfig(provider).Acquire(ctx)
+		if err == nil {
+			// The lease is ended by EndPoolLease once the run is done with the cluster, or lapses if
+			// the run dies without doing so.
+			keepAliveCtx, stop := context.WithCancel(ctx)
+			go lease.KeepAlive(keepAliveCtx)
+			poolLease.Lock()
+			poolLease.lease, poolLease.stop = lease, stop
+			poolLease.Unlock()
+			viper.Set(config.Cluster.Reused, true)
+			return provider.GetClusterContext(ctx, lease.ClusterID)
+		} else if errors.Is(err, pool.ErrPoolEmpty) {
+			logger.Printf("No pooled cluster is available, provisioning a new one")
+		} else {
+			logger.Printf("Error leasing a pooled cluster, provisioning a new one: %v", err)
+		}
+	}
+
 	if clusterID == "" {
 		name := viper.GetString(config.Cluster.Name)
 		if name == "" || name == "random" {
 			attemptLimit := 10
 			for attempt := 1; attempt <= attemptLimit; attempt++ {
 				name = clusterName()
-				validName, err := provider.IsValidClusterName(name)
+				validName, err := provider.IsValidClusterNameContext(ctx, name)
 				if err != nil {
 					fmt.Printf("an error occurred validating the cluster name %v\n", err)
 				} else if validName {
@@ -542,22 +726,23 @@ func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
 			}
 		}
 
-		if clusterID, err = provider.LaunchCluster(name); err != nil {
+		if clusterID, err = provider.LaunchClusterContext(ctx, name); err != nil {
 			return nil, fmt.Errorf("could not launch cluster: %v", err)
 		}
 
-		if cluster, err = provider.GetCluster(clusterID); err != nil {
+		if cluster, err = provider.GetClusterContext(ctx, clusterID); err != nil {
 			return nil, fmt.Errorf("could not get cluster after launching: %v", err)
 		}
+		metadata.Instance.RecordClusterState(string(cluster.State()))
 	} else {
 		logger.Printf("CLUSTER_ID of '%s' was provided, skipping cluster creation and using it instead", clusterID)
 
-		cluster, err = provider.GetCluster(clusterID)
+		cluster, err = provider.GetClusterContext(ctx, clusterID)
 		if err != nil {
 			return nil, fmt.Errorf("could not retrieve cluster information from OCM: %v", err)
 		}
 
-		if cluster.State() == spi.ClusterStateHibernating && !provider.Resume(cluster.ID()) {
+		if cluster.State() == spi.ClusterStateHibernating && !provider.ResumeContext(ctx, cluster.ID()) {
 			return cluster, fmt.Errorf("cluster errored while resuming")
 		}
 	}
@@ -565,6 +750,39 @@ func ProvisionCluster(logger *log.Logger) (*spi.Cluster, error) {
 	return cluster, nil
 }
 
+// poolLease is the lease on the pooled cluster provisioned for this run, if any.
+var poolLease struct {
+	sync.Mutex
+	lease *pool.Lease
+	stop  context.CancelFunc
+}
+
+// PoolLease returns whether this run leased a pooled cluster, and whether that cluster had to be resumed.
+func PoolLease() (leased, resumed bool) {
+	poolLease.Lock()
+	defer poolLease.Unlock()
+
+	if poolLease.lease == nil {
+		return false, false
+	}
+	return true, poolLease.lease.Resumed
+}
+
+// EndPoolLease ends the lease on the pooled cluster provisioned for this run, if there is one, so that
+// the pool replaces the used cluster straight away rather than once the lease lapses.
+func EndPoolLease(ctx context.Context) error {
+	poolLease.Lock()
+	defer poolLease.Unlock()
+
+	if poolLease.lease == nil {
+		return nil
+	}
+	poolLease.stop()
+	err := poolLease.lease.End(ctx)
+	poolLease.lease, poolLease.stop = nil, nil
+	return err
+}
+
 // clusterName returns a cluster name with a format which must be short enough to support all versions
 func clusterName() string {
 	suffix := viper.GetString(config.Suffix)
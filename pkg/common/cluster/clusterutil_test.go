@@ -3,8 +3,10 @@ package cluster
 import (
 	"testing"
 
+	"github.com/openshift/osde2e/pkg/common/cluster/healthchecks"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/metadata"
 )
 
 func TestRandomClusterName(t *testing.T) {
@@ -17,3 +19,46 @@ func TestRandomClusterName(t *testing.T) {
 		}
 	}
 }
+
+func TestHealthCheckHistory(t *testing.T) {
+	healthCheckHistory = healthCheckRecords{}
+	defer func() { healthCheckHistory = healthCheckRecords{} }()
+
+	healthCheckHistory.add(3, []healthchecks.Result{
+		{Name: "node", Status: healthchecks.StatusFailed, Message: "1 unhealthy"},
+		{Name: "cvo", Status: healthchecks.StatusPassed},
+	})
+	healthCheckHistory.add(5, []healthchecks.Result{{Name: "node", Status: healthchecks.StatusPassed}})
+
+	history := HealthCheckHistory()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 records, got %+v", history)
+	}
+	for i, iteration := range []int{3, 3, 5} {
+		if history[i].Iteration != iteration || history[i].Checked.IsZero() {
+			t.Errorf("record %d: expected iteration %d, got %+v", i, iteration, history[i])
+		}
+	}
+	if history[0].Name != "node" || history[0].Message != "1 unhealthy" {
+		t.Errorf("unexpected first record %+v", history[0])
+	}
+}
+
+func TestPollClusterHealthWithoutChecks(t *testing.T) {
+	healthCheckHistory = healthCheckRecords{}
+	defer func() { healthCheckHistory = healthCheckRecords{} }()
+
+	// Without a cluster to get clients for, the checks can't be run at all.
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	metadata.Instance.ReportDir = t.TempDir()
+	metadata.Instance.HealthCheckIteration = 6
+
+	if healthy, _, _ := PollClusterHealth("", nil); healthy {
+		t.Errorf("expected the cluster not to be healthy when the checks can't be run")
+	}
+
+	history := HealthCheckHistory()
+	if len(history) != 1 || history[0].Name != checksNotRun || history[0].Status != healthchecks.StatusError || history[0].Iteration != 7 {
+		t.Errorf("expected an error record for iteration 7, got %+v", history)
+	}
+}
@@ -0,0 +1,60 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var apiServiceResource = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+func init() {
+	Register(NewCheck("apiservice", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return apiServiceProblems(ctx, clients.Dynamic, logger)
+	}))
+}
+
+// apiServiceProblems returns the aggregated APIServices that aren't available. APIs served by the
+// kube-apiserver itself are always available.
+func apiServiceProblems(ctx context.Context, dynamicClient dynamic.Interface, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that aggregated APIServices are available...")
+
+	list, err := dynamicClient.Resource(apiServiceResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting apiservice list: %v", err)
+	}
+
+	var problems []string
+	for _, apiService := range list.Items {
+		conditions, _, err := unstructured.NestedSlice(apiService.Object, "status", "conditions")
+		if err != nil {
+			return nil, fmt.Errorf("error reading apiservice %s conditions: %v", apiService.GetName(), err)
+		}
+
+		available, reason, message := false, "", ""
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != "Available" {
+				continue
+			}
+			available = condition["status"] == "True"
+			reason, _ = condition["reason"].(string)
+			message, _ = condition["message"].(string)
+		}
+
+		if !available {
+			problems = append(problems, fmt.Sprintf("apiservice/%s: not available (%s) %s", apiService.GetName(), reason, message))
+			logger.Printf("APIService %s is not available: %s %s", apiService.GetName(), reason, message)
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,62 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func apiService(name string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	list := []interface{}{}
+	for _, condition := range conditions {
+		list = append(list, condition)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiregistration.k8s.io/v1",
+		"kind":       "APIService",
+		"metadata":   map[string]interface{}{"name": name},
+		"status":     map[string]interface{}{"conditions": list},
+	}}
+}
+
+func TestAPIServiceProblems(t *testing.T) {
+	tests := []struct {
+		description      string
+		expectedProblems int
+		objs             []runtime.Object
+	}{
+		{"no apiservices", 0, nil},
+		{"available", 0, []runtime.Object{
+			apiService("v1.apps", condition("Available", "True")),
+			apiService("v1.metrics.k8s.io", condition("Available", "True")),
+		}},
+		{"unavailable", 1, []runtime.Object{
+			apiService("v1.apps", condition("Available", "True")),
+			apiService("v1beta1.metrics.k8s.io", map[string]interface{}{
+				"type":    "Available",
+				"status":  "False",
+				"reason":  "FailedDiscoveryCheck",
+				"message": "failing or missing response",
+			}),
+		}},
+		{"no conditions", 1, []runtime.Object{apiService("v1.packages.operators.coreos.com")}},
+	}
+
+	for _, test := range tests {
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{apiServiceResource: "APIServiceList"}, test.objs...)
+		problems, err := apiServiceProblems(context.TODO(), dynamicClient, nil)
+
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.description, err)
+		}
+
+		if len(problems) != test.expectedProblems {
+			t.Errorf("%v: expected %d problems, got %v", test.description, test.expectedProblems, problems)
+		}
+	}
+}
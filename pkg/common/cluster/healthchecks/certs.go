@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/openshift/osde2e/pkg/common/logging"
@@ -12,7 +13,11 @@ import (
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// certCheckData is shared by every run of the cert check, including those of health monitors running
+// alongside the main checks.
 type certCheckData struct {
+	mu sync.Mutex
+
 	checkStarted bool
 	startTime    time.Time
 	certFound    bool
@@ -23,36 +28,49 @@ var certCheck = certCheckData{
 	certFound:    false,
 }
 
+func init() {
+	Register(NewOpenShiftCheck("cert", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return certProblems(ctx, clients.Kube.CoreV1(), logger)
+	}))
+}
+
 // CheckCerts will check for the presence of a cert issued by certman
 func CheckCerts(secretClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
+	problems, err := certProblems(context.TODO(), secretClient, logger)
+	return err == nil && len(problems) == 0, err
+}
+
+// certProblems reports the certificate secret as missing until certman has issued it.
+func certProblems(ctx context.Context, secretClient v1.CoreV1Interface, logger *log.Logger) ([]string, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
+	certCheck.mu.Lock()
 	if !certCheck.checkStarted {
 		certCheck.checkStarted = true
 		certCheck.startTime = time.Now()
 	}
+	certCheck.mu.Unlock()
 
 	listOpts := metav1.ListOptions{
 		LabelSelector: "certificate_request",
 	}
-	secrets, err := secretClient.Secrets("openshift-config").List(context.TODO(), listOpts)
+	secrets, err := secretClient.Secrets("openshift-config").List(ctx, listOpts)
 	if err != nil {
-		metadata.Instance.SetHealthcheckValue("certs", []string{"error"})
-		return false, fmt.Errorf("error trying to find issued certificate(s): %v", err)
+		return nil, fmt.Errorf("error trying to find issued certificate(s): %v", err)
 	}
 	if len(secrets.Items) < 1 {
 		logger.Printf("Certificate(s) not yet issued.")
-		metadata.Instance.SetHealthcheckValue("certs", []string{"pending"})
-		return false, nil
+		return []string{"secret/openshift-config/certificate_request: pending"}, nil
 	}
 
+	certCheck.mu.Lock()
 	if !certCheck.certFound {
 		certCheck.certFound = true
 		metadata.Instance.SetTimeToCertificateIssued(time.Since(certCheck.startTime).Seconds())
-		metadata.Instance.ClearHealthcheckValue("certs")
 	}
+	certCheck.mu.Unlock()
 
 	logger.Printf("Certificate(s) has been found.")
 
-	return true, nil
+	return nil, nil
 }
@@ -1,6 +1,8 @@
 package healthchecks
 
 import (
+	"context"
+	"sync"
 	"testing"
 
 	"github.com/openshift/osde2e/pkg/common/util"
@@ -66,3 +68,20 @@ func TestCerts(t *testing.T) {
 		}
 	}
 }
+
+// TestCertsConcurrently runs the check as the main checks and a health monitor would, for the race detector.
+func TestCertsConcurrently(t *testing.T) {
+	kubeClient := kubernetes.NewSimpleClientset(secretList(1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if problems, err := certProblems(context.TODO(), kubeClient.CoreV1(), nil); err != nil || len(problems) != 0 {
+				t.Errorf("expected no problems, got %v: %v", problems, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,335 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	"github.com/openshift/osde2e/pkg/common/metadata"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ErrNotApplicable is returned by checks that don't apply to the cluster they were run against.
+var ErrNotApplicable = errors.New("check does not apply to this cluster")
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPassed checks found nothing wrong.
+	StatusPassed Status = "passed"
+
+	// StatusFailed checks found unhealthy objects.
+	StatusFailed Status = "failed"
+
+	// StatusError checks couldn't determine whether the cluster is healthy.
+	StatusError Status = "error"
+
+	// StatusSkipped checks don't apply to the cluster.
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of running a check once.
+type Result struct {
+	Name     string   `json:"name"`
+	Status   Status   `json:"status"`
+	Message  string   `json:"message,omitempty"`
+	Duration float64  `json:"duration"`
+	Objects  []string `json:"objects,omitempty"`
+
+	// Reasons counts the unhealthy objects by why they are unhealthy, for checks that can tell.
+	Reasons map[string]int `json:"reasons,omitempty"`
+}
+
+// Healthy returns whether the check didn't find the cluster unhealthy.
+func (r Result) Healthy() bool {
+	return r.Status == StatusPassed || r.Status == StatusSkipped
+}
+
+// Clients are the clients checks may use to inspect a cluster.
+type Clients struct {
+	Kube     kubernetes.Interface
+	Config   configclient.Interface
+	Operator operatorclient.Interface
+	Dynamic  dynamic.Interface
+
+	// OpenShift is whether the cluster serves OpenShift's config API. Checks of OpenShift objects
+	// are skipped on plain Kubernetes clusters.
+	OpenShift bool
+}
+
+// NewClients creates the clients for a cluster and discovers whether it is an OpenShift cluster.
+func NewClients(restConfig *rest.Config) (*Clients, error) {
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Kube Clientset: %v", err)
+	}
+
+	configClient, err := configclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating OpenShift Clientset: %v", err)
+	}
+
+	operatorClient, err := operatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating OpenShift Operator Clientset: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Dynamic Clientset: %v", err)
+	}
+
+	// Only a cluster that says it doesn't serve the API is treated as plain Kubernetes, so that an
+	// unreachable OpenShift cluster fails its OpenShift checks rather than skipping them.
+	_, err = kubeClient.Discovery().ServerResourcesForGroupVersion(configv1.GroupVersion.String())
+
+	return &Clients{
+		Kube:      kubeClient,
+		Config:    configClient,
+		Operator:  operatorClient,
+		Dynamic:   dynamicClient,
+		OpenShift: !apierrors.IsNotFound(err),
+	}, nil
+}
+
+// Check inspects one aspect of a cluster's health.
+type Check interface {
+	// Name identifies the check in results, metadata and configuration.
+	Name() string
+
+	// Run returns the objects that are unhealthy. An error means the check couldn't tell, or is
+	// ErrNotApplicable if there is nothing for the check to inspect on this cluster.
+	Run(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error)
+}
+
+// Classifier is implemented by checks that can tell why an object they reported is unhealthy.
+type Classifier interface {
+	// Classify returns the reason the object is unhealthy.
+	Classify(object string) string
+}
+
+// Recorder is implemented by checks that keep details of what they found in the metadata.
+type Recorder interface {
+	// Record adds the details of the check's last run to the metadata. It is only called by Run, so checks
+	// run in the background don't write to the metadata.
+	Record()
+}
+
+// CheckFunc is the function run by a check created with NewCheck.
+type CheckFunc func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error)
+
+type funcCheck struct {
+	name      string
+	openShift bool
+	run       CheckFunc
+}
+
+func (c *funcCheck) Name() string {
+	return c.name
+}
+
+func (c *funcCheck) Run(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+	if c.openShift && !clients.OpenShift {
+		return nil, ErrNotApplicable
+	}
+	return c.run(ctx, clients, logger)
+}
+
+// NewCheck creates a check from a function.
+func NewCheck(name string, run CheckFunc) Check {
+	return &funcCheck{name: name, run: run}
+}
+
+// NewOpenShiftCheck creates a check from a function that is skipped on clusters that aren't OpenShift.
+func NewOpenShiftCheck(name string, run CheckFunc) Check {
+	return &funcCheck{name: name, openShift: true, run: run}
+}
+
+var registry = struct {
+	mu        sync.RWMutex
+	checks    map[string]Check
+	providers map[string][]string
+}{
+	checks:    map[string]Check{},
+	providers: map[string][]string{},
+}
+
+// DefaultChecks are run against clusters from providers that haven't registered their own checks.
+var DefaultChecks = []string{
+	"cvo", "node", "operator", "daemonset", "replicaset",
+}
+
+func init() {
+	// OSD clusters also have machines and a certificate issued by certman.
+	osdChecks := []string{
+		"cvo", "node", "machine", "operator", "cert", "daemonset", "replicaset",
+	}
+	RegisterProviderChecks("ocm", osdChecks...)
+	RegisterProviderChecks("rosa", osdChecks...)
+}
+
+// Register adds a check to the registry.
+func Register(check Check) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.checks[check.Name()]; ok {
+		panic(fmt.Sprintf("Duplicate health check name %s!", check.Name()))
+	}
+	registry.checks[check.Name()] = check
+}
+
+// RegisterProviderChecks sets the checks run against clusters from the given provider type.
+func RegisterProviderChecks(providerType string, names ...string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.providers[providerType] = names
+}
+
+// Names returns the names of every registered check.
+func Names() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registeredNames()
+}
+
+func registeredNames() []string {
+	names := make([]string, 0, len(registry.checks))
+	for name := range registry.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ChecksFor returns the checks to run against clusters from the given provider type. The configured
+// checks take precedence over the provider's, and skipped checks are left out of either.
+func ChecksFor(providerType string) ([]Check, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	names := splitNames(viper.GetString(config.HealthChecks.Checks))
+	if len(names) == 0 {
+		var ok bool
+		if names, ok = registry.providers[providerType]; !ok {
+			names = DefaultChecks
+		}
+	}
+
+	skip := map[string]bool{}
+	for _, name := range splitNames(viper.GetString(config.HealthChecks.Skip)) {
+		skip[name] = true
+	}
+
+	checks := []Check{}
+	for _, name := range names {
+		check, ok := registry.checks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown health check %q, expected one of %s", name, strings.Join(registeredNames(), ", "))
+		}
+		if !skip[name] {
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+func splitNames(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Run runs each check in turn and records the outcome of unhealthy checks in the metadata.
+func Run(ctx context.Context, checks []Check, clients *Clients, logger *log.Logger) []Result {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		result := runCheck(ctx, check, clients, logger)
+		if recorder, ok := check.(Recorder); ok {
+			recorder.Record()
+		}
+		if result.Healthy() {
+			metadata.Instance.ClearHealthcheckValue(result.Name)
+		} else {
+			metadata.Instance.SetHealthcheckValue(result.Name, metadata.HealthCheck{
+				Status:   string(result.Status),
+				Message:  result.Message,
+				Duration: result.Duration,
+				Objects:  result.Objects,
+				Reasons:  result.Reasons,
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func runCheck(ctx context.Context, check Check, clients *Clients, logger *log.Logger) Result {
+	start := time.Now()
+	objects, err := check.Run(ctx, clients, logger)
+
+	result := Result{
+		Name:     check.Name(),
+		Status:   StatusPassed,
+		Duration: time.Since(start).Seconds(),
+		Objects:  objects,
+	}
+	switch {
+	case errors.Is(err, ErrNotApplicable):
+		result.Status = StatusSkipped
+		result.Message = err.Error()
+	case err != nil:
+		result.Status = StatusError
+		result.Message = err.Error()
+	case len(objects) > 0:
+		result.Status = StatusFailed
+		result.Message = fmt.Sprintf("%d unhealthy", len(objects))
+
+		if classifier, ok := check.(Classifier); ok {
+			result.Reasons = map[string]int{}
+			for _, object := range objects {
+				result.Reasons[classifier.Classify(object)]++
+			}
+			result.Message += ": " + formatReasons(result.Reasons)
+		}
+	}
+	return result
+}
+
+// formatReasons lists reason counts by reason, e.g. "2 CrashLoopBackOff, 1 OOMKilled".
+func formatReasons(reasons map[string]int) string {
+	names := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		names = append(names, reason)
+	}
+	sort.Strings(names)
+
+	counts := make([]string, 0, len(names))
+	for _, reason := range names {
+		counts = append(counts, fmt.Sprintf("%d %s", reasons[reason], reason))
+	}
+	return strings.Join(counts, ", ")
+}
@@ -0,0 +1,106 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/metadata"
+	v1 "k8s.io/api/core/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func checkNames(checks []Check) []string {
+	names := []string{}
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	return names
+}
+
+func TestChecksFor(t *testing.T) {
+	tests := []struct {
+		description   string
+		providerType  string
+		checks        string
+		skip          string
+		expected      []string
+		expectedError bool
+	}{
+		{"ocm", "ocm", "", "", []string{"cvo", "node", "machine", "operator", "cert", "daemonset", "replicaset"}, false},
+		{"unregistered provider", "mock", "", "", DefaultChecks, false},
+		{"configured checks", "ocm", "node, cvo", "", []string{"node", "cvo"}, false},
+		{"skipped checks", "rosa", "", "cert,machine", []string{"cvo", "node", "operator", "daemonset", "replicaset"}, false},
+		{"opt-in checks", "ocm", "etcd,pdb", "", []string{"etcd", "pdb"}, false},
+		{"unknown check", "ocm", "node,bogus", "", nil, true},
+	}
+
+	for _, test := range tests {
+		viper.Reset()
+		viper.Set(config.HealthChecks.Checks, test.checks)
+		viper.Set(config.HealthChecks.Skip, test.skip)
+
+		checks, err := ChecksFor(test.providerType)
+		if (err != nil) != test.expectedError {
+			t.Errorf("%v: unexpected error: %v", test.description, err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(checkNames(checks), test.expected) {
+			t.Errorf("%v: expected checks %v, got %v", test.description, test.expected, checkNames(checks))
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		NewCheck("passing", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+			return nil, nil
+		}),
+		NewCheck("failing", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+			return []string{"node/a: Ready=False", "node/b: Ready=Unknown"}, nil
+		}),
+		NewCheck("erroring", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+			return nil, errors.New("connection refused")
+		}),
+		NewOpenShiftCheck("openshift", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+			return []string{"should not run"}, nil
+		}),
+		NewCheck("node", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+			return nodeProblems(ctx, clients.Kube.CoreV1(), logger)
+		}),
+	}
+
+	clients := &Clients{Kube: kubernetes.NewSimpleClientset(node("ready", []v1.NodeCondition{{Type: "Ready", Status: "True"}}))}
+	metadata.Instance.SetHealthcheckValue("passing", metadata.HealthCheck{Status: string(StatusFailed)})
+
+	expected := map[string]Status{
+		"passing":   StatusPassed,
+		"failing":   StatusFailed,
+		"erroring":  StatusError,
+		"openshift": StatusSkipped,
+		"node":      StatusPassed,
+	}
+
+	results := Run(context.Background(), checks, clients, nil)
+	if len(results) != len(checks) {
+		t.Fatalf("expected %d results, got %d", len(checks), len(results))
+	}
+	for _, result := range results {
+		if result.Status != expected[result.Name] {
+			t.Errorf("%v: expected status %v, got %v (%s)", result.Name, expected[result.Name], result.Status, result.Message)
+		}
+
+		recorded, ok := metadata.Instance.HealthChecks[result.Name]
+		if ok == result.Healthy() {
+			t.Errorf("%v: expected metadata to hold only unhealthy checks, got %+v", result.Name, recorded)
+		}
+	}
+
+	if objects := metadata.Instance.HealthChecks["failing"].Objects; len(objects) != 2 {
+		t.Errorf("expected the failing check's objects in metadata, got %v", objects)
+	}
+}
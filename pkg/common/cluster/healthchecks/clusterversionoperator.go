@@ -8,7 +8,6 @@ import (
 	v1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
 	"github.com/openshift/osde2e/pkg/common/logging"
-	"github.com/openshift/osde2e/pkg/common/metadata"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,19 +17,30 @@ func GetClusterVersionObject(configClient configclient.ConfigV1Interface) (*v1.C
 	return configClient.ClusterVersions().Get(context.TODO(), "version", getOpts)
 }
 
+func init() {
+	Register(NewOpenShiftCheck("cvo", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return cvoProblems(ctx, clients.Config.ConfigV1(), logger)
+	}))
+}
+
 // CheckCVOReadiness attempts to look at the state of the ClusterVersionOperator and returns true if things are healthy.
 func CheckCVOReadiness(configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
+	problems, err := cvoProblems(context.TODO(), configClient, logger)
+	return err == nil && len(problems) == 0, err
+}
+
+// cvoProblems returns the ClusterVersion conditions that show the cluster isn't healthy.
+func cvoProblems(ctx context.Context, configClient configclient.ConfigV1Interface, logger *log.Logger) ([]string, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
-	success := true
 	logger.Print("Checking that CVO says the cluster is healthy...")
 
-	cvInfo, err := GetClusterVersionObject(configClient)
+	cvInfo, err := configClient.ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	var metadataState []string
+	var problems []string
 
 	for _, v := range cvInfo.Status.Conditions {
 		switch v.Type {
@@ -49,16 +59,9 @@ func CheckCVOReadiness(configClient configclient.ConfigV1Interface, logger *log.
 				continue
 			}
 		}
-		metadataState = append(metadataState, fmt.Sprintf("%v", v))
+		problems = append(problems, fmt.Sprintf("clusterversion/%s: %v=%v %v", cvInfo.Name, v.Type, v.Status, v.Message))
 		logger.Printf("CVO State not complete: %v: %v %v", v.Type, v.Status, v.Message)
-		success = false
-	}
-
-	if len(metadataState) > 0 {
-		metadata.Instance.SetHealthcheckValue("cvo", metadataState)
-	} else {
-		metadata.Instance.ClearHealthcheckValue("cvo")
 	}
 
-	return success, nil
+	return problems, nil
 }
@@ -0,0 +1,53 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	Register(NewOpenShiftCheck("etcd", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return etcdProblems(ctx, clients.Operator.OperatorV1(), logger)
+	}))
+}
+
+// etcdProblems returns the etcd operator's conditions that show members are unavailable or degraded.
+// Clusters whose control plane is hosted elsewhere have no etcd operator and are skipped.
+func etcdProblems(ctx context.Context, operatorClient operatorclient.OperatorV1Interface, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that etcd members are healthy...")
+
+	etcd, err := operatorClient.Etcds().Get(ctx, "cluster", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrNotApplicable
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting etcd operator: %v", err)
+	}
+
+	var problems []string
+	for _, condition := range etcd.Status.Conditions {
+		unhealthy := false
+		switch {
+		case condition.Type == "EtcdMembersAvailable":
+			unhealthy = condition.Status != operatorv1.ConditionTrue
+		case strings.HasSuffix(condition.Type, "Degraded"):
+			unhealthy = condition.Status == operatorv1.ConditionTrue
+		}
+
+		if unhealthy {
+			problems = append(problems, fmt.Sprintf("etcd/%s: %s=%s %s", etcd.Name, condition.Type, condition.Status, condition.Message))
+			logger.Printf("etcd is unhealthy: %s=%s %s", condition.Type, condition.Status, condition.Message)
+		}
+	}
+
+	return problems, nil
+}
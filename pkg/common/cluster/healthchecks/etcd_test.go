@@ -0,0 +1,60 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	fakeOperator "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func etcdOperator(conditions ...operatorv1.OperatorCondition) *operatorv1.Etcd {
+	return &operatorv1.Etcd{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status: operatorv1.EtcdStatus{
+			StaticPodOperatorStatus: operatorv1.StaticPodOperatorStatus{
+				OperatorStatus: operatorv1.OperatorStatus{Conditions: conditions},
+			},
+		},
+	}
+}
+
+func TestEtcdProblems(t *testing.T) {
+	tests := []struct {
+		description      string
+		expectedProblems int
+		expectedError    error
+		objs             []runtime.Object
+	}{
+		{"no etcd operator", 0, ErrNotApplicable, nil},
+		{"healthy", 0, nil, []runtime.Object{etcdOperator(
+			operatorv1.OperatorCondition{Type: "EtcdMembersAvailable", Status: "True"},
+			operatorv1.OperatorCondition{Type: "EtcdMembersDegraded", Status: "False"},
+			operatorv1.OperatorCondition{Type: "Available", Status: "True"},
+		)}},
+		{"member unavailable", 2, nil, []runtime.Object{etcdOperator(
+			operatorv1.OperatorCondition{Type: "EtcdMembersAvailable", Status: "False", Message: "2 of 3 members are available"},
+			operatorv1.OperatorCondition{Type: "EtcdMembersDegraded", Status: "True"},
+		)}},
+		{"controller degraded", 1, nil, []runtime.Object{etcdOperator(
+			operatorv1.OperatorCondition{Type: "EtcdMembersAvailable", Status: "True"},
+			operatorv1.OperatorCondition{Type: "ClusterMemberControllerDegraded", Status: "True"},
+		)}},
+	}
+
+	for _, test := range tests {
+		operatorClient := fakeOperator.NewSimpleClientset(test.objs...)
+		problems, err := etcdProblems(context.TODO(), operatorClient.OperatorV1(), nil)
+
+		if !errors.Is(err, test.expectedError) {
+			t.Errorf("%v: expected error %v, got %v", test.description, test.expectedError, err)
+		}
+
+		if len(problems) != test.expectedProblems {
+			t.Errorf("%v: expected %d problems, got %v", test.description, test.expectedProblems, problems)
+		}
+	}
+}
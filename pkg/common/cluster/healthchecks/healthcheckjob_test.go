@@ -0,0 +1,47 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckHealthcheckJob(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "osd-cluster-ready"},
+		Status:     batchv1.JobStatus{Failed: 1},
+	}
+	kubeClient := kubernetes.NewSimpleClientset(job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- CheckHealthcheckJob(kubeClient, ctx, nil)
+	}()
+
+	// A failed attempt is waited out until a retry succeeds.
+	time.Sleep(200 * time.Millisecond)
+	job.Status.Succeeded = 1
+	if _, err := kubeClient.BatchV1().Jobs(job.Namespace).Update(ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update job: %v", err)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error waiting for the healthcheck job: %v", err)
+	}
+}
+
+func TestCheckHealthcheckJobTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err := CheckHealthcheckJob(kubernetes.NewSimpleClientset(), ctx, nil); err == nil {
+		t.Error("expected an error when the healthcheck job never appears")
+	}
+}
@@ -0,0 +1,57 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ingressOperatorNamespace = "openshift-ingress-operator"
+
+func init() {
+	Register(NewOpenShiftCheck("ingresscontroller", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return ingressControllerProblems(ctx, clients.Operator.OperatorV1(), logger)
+	}))
+}
+
+// ingressControllerProblems returns the IngressControllers that are unavailable or degraded.
+func ingressControllerProblems(ctx context.Context, operatorClient operatorclient.OperatorV1Interface, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that IngressControllers are available...")
+
+	list, err := operatorClient.IngressControllers(ingressOperatorNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting ingresscontroller list: %v", err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no ingresscontrollers found in the %s namespace", ingressOperatorNamespace)
+	}
+
+	var problems []string
+	for _, ic := range list.Items {
+		available := false
+		for _, condition := range ic.Status.Conditions {
+			switch {
+			case condition.Type == operatorv1.OperatorStatusTypeAvailable:
+				available = condition.Status == operatorv1.ConditionTrue
+			case condition.Type == operatorv1.OperatorStatusTypeDegraded && condition.Status == operatorv1.ConditionTrue:
+				problems = append(problems, fmt.Sprintf("ingresscontroller/%s: %s=%s %s", ic.Name, condition.Type, condition.Status, condition.Message))
+				logger.Printf("IngressController %s is degraded: %s", ic.Name, condition.Message)
+			}
+		}
+
+		if !available {
+			problems = append(problems, fmt.Sprintf("ingresscontroller/%s: not available", ic.Name))
+			logger.Printf("IngressController %s is not available", ic.Name)
+		}
+	}
+
+	return problems, nil
+}
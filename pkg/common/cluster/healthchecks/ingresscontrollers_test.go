@@ -0,0 +1,54 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	fakeOperator "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func ingressController(name string, conditions ...operatorv1.OperatorCondition) *operatorv1.IngressController {
+	return &operatorv1.IngressController{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ingressOperatorNamespace},
+		Status:     operatorv1.IngressControllerStatus{Conditions: conditions},
+	}
+}
+
+func TestIngressControllerProblems(t *testing.T) {
+	available := operatorv1.OperatorCondition{Type: "Available", Status: "True"}
+
+	tests := []struct {
+		description      string
+		expectedProblems int
+		expectedError    bool
+		objs             []runtime.Object
+	}{
+		{"no ingresscontrollers", 0, true, nil},
+		{"available", 0, false, []runtime.Object{
+			ingressController("default", available, operatorv1.OperatorCondition{Type: "Degraded", Status: "False"}),
+		}},
+		{"unavailable", 1, false, []runtime.Object{
+			ingressController("default", operatorv1.OperatorCondition{Type: "Available", Status: "False"}),
+		}},
+		{"degraded", 1, false, []runtime.Object{
+			ingressController("default", available),
+			ingressController("apps2", available, operatorv1.OperatorCondition{Type: "Degraded", Status: "True", Message: "DNSReady=False"}),
+		}},
+	}
+
+	for _, test := range tests {
+		operatorClient := fakeOperator.NewSimpleClientset(test.objs...)
+		problems, err := ingressControllerProblems(context.TODO(), operatorClient.OperatorV1(), nil)
+
+		if (err != nil) != test.expectedError {
+			t.Errorf("%v: unexpected error: %v", test.description, err)
+		}
+
+		if len(problems) != test.expectedProblems {
+			t.Errorf("%v: expected %d problems, got %v", test.description, test.expectedProblems, problems)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var machineConfigPoolResource = schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"}
+
+func init() {
+	Register(NewOpenShiftCheck("machineconfigpool", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return machineConfigPoolProblems(ctx, clients.Dynamic, logger)
+	}))
+}
+
+// machineConfigPoolProblems returns the MachineConfigPools that are degraded or still rolling out a config.
+func machineConfigPoolProblems(ctx context.Context, dynamicClient dynamic.Interface, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that MachineConfigPools are updated and not degraded...")
+
+	list, err := dynamicClient.Resource(machineConfigPoolResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting machineconfigpool list: %v", err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no machineconfigpools found")
+	}
+
+	var problems []string
+	for _, pool := range list.Items {
+		conditions, _, err := unstructured.NestedSlice(pool.Object, "status", "conditions")
+		if err != nil {
+			return nil, fmt.Errorf("error reading machineconfigpool %s conditions: %v", pool.GetName(), err)
+		}
+
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _ := condition["type"].(string)
+			status, _ := condition["status"].(string)
+			message, _ := condition["message"].(string)
+
+			switch conditionType {
+			case "Degraded", "NodeDegraded", "RenderDegraded", "Updating":
+				if status == "True" {
+					problems = append(problems, fmt.Sprintf("machineconfigpool/%s: %s=%s %s", pool.GetName(), conditionType, status, message))
+					logger.Printf("MachineConfigPool %s is not settled: %s=%s %s", pool.GetName(), conditionType, status, message)
+				}
+			}
+		}
+
+		if degraded, _, _ := unstructured.NestedInt64(pool.Object, "status", "degradedMachineCount"); degraded > 0 {
+			problems = append(problems, fmt.Sprintf("machineconfigpool/%s: %d degraded machines", pool.GetName(), degraded))
+			logger.Printf("MachineConfigPool %s has %d degraded machines", pool.GetName(), degraded)
+		}
+	}
+
+	return problems, nil
+}
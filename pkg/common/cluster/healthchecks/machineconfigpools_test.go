@@ -0,0 +1,67 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func machineConfigPool(name string, degradedMachines int64, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	list := []interface{}{}
+	for _, condition := range conditions {
+		list = append(list, condition)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "machineconfiguration.openshift.io/v1",
+		"kind":       "MachineConfigPool",
+		"metadata":   map[string]interface{}{"name": name},
+		"status": map[string]interface{}{
+			"degradedMachineCount": degradedMachines,
+			"conditions":           list,
+		},
+	}}
+}
+
+func condition(conditionType, status string) map[string]interface{} {
+	return map[string]interface{}{"type": conditionType, "status": status}
+}
+
+func TestMachineConfigPoolProblems(t *testing.T) {
+	tests := []struct {
+		description      string
+		expectedProblems int
+		expectedError    bool
+		objs             []runtime.Object
+	}{
+		{"no pools", 0, true, nil},
+		{"updated", 0, false, []runtime.Object{
+			machineConfigPool("master", 0, condition("Updated", "True"), condition("Updating", "False"), condition("Degraded", "False")),
+			machineConfigPool("worker", 0, condition("Updated", "True"), condition("Updating", "False"), condition("Degraded", "False")),
+		}},
+		{"updating", 1, false, []runtime.Object{
+			machineConfigPool("worker", 0, condition("Updated", "False"), condition("Updating", "True")),
+		}},
+		{"degraded", 3, false, []runtime.Object{
+			machineConfigPool("master", 0, condition("Updated", "True")),
+			machineConfigPool("worker", 1, condition("Degraded", "True"), condition("NodeDegraded", "True")),
+		}},
+	}
+
+	for _, test := range tests {
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{machineConfigPoolResource: "MachineConfigPoolList"}, test.objs...)
+		problems, err := machineConfigPoolProblems(context.TODO(), dynamicClient, nil)
+
+		if (err != nil) != test.expectedError {
+			t.Errorf("%v: unexpected error: %v", test.description, err)
+		}
+
+		if len(problems) != test.expectedProblems {
+			t.Errorf("%v: expected %d problems, got %v", test.description, test.expectedProblems, problems)
+		}
+	}
+}
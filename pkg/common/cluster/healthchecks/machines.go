@@ -7,8 +7,8 @@ import (
 
 	machineapi "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/osde2e/pkg/common/logging"
-	"github.com/openshift/osde2e/pkg/common/metadata"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -19,43 +19,59 @@ const (
 	machinesNamespace = "openshift-machine-api"
 )
 
+var machineResource = schema.GroupVersionResource{Group: "machine.openshift.io", Resource: "machines", Version: "v1beta1"}
+
+func init() {
+	Register(NewOpenShiftCheck("machine", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return machineProblems(ctx, clients.Dynamic, logger)
+	}))
+}
+
 // CheckMachinesObjectState lists all openshift machines and validates that they are "Running"
 func CheckMachinesObjectState(dynamicClient dynamic.Interface, logger *log.Logger) (bool, error) {
+	problems, err := machineProblems(context.TODO(), dynamicClient, logger)
+	return err == nil && len(problems) == 0, err
+}
+
+// machineProblems returns the machines that aren't running.
+func machineProblems(ctx context.Context, dynamicClient dynamic.Interface, logger *log.Logger) ([]string, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
 	logger.Print("Checking that machines are healthy...")
 
-	mc := dynamicClient.
-		Resource(schema.GroupVersionResource{Group: "machine.openshift.io", Resource: "machines", Version: "v1beta1"}).
-		Namespace(machinesNamespace)
-	obj, err := mc.List(context.TODO(), metav1.ListOptions{})
+	mc := dynamicClient.Resource(machineResource).Namespace(machinesNamespace)
+	obj, err := mc.List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if len(obj.Items) == 0 {
-		return false, fmt.Errorf("No machines found in the %s namespace", machinesNamespace)
+	return unhealthyMachines(obj.Items, logger)
+}
+
+// unhealthyMachines returns the machines that aren't running.
+func unhealthyMachines(machines []unstructured.Unstructured, logger *log.Logger) ([]string, error) {
+	if len(machines) == 0 {
+		return nil, fmt.Errorf("No machines found in the %s namespace", machinesNamespace)
 	}
 
-	var metadataState []string
+	var problems []string
 
-	for _, item := range obj.Items {
+	for _, item := range machines {
 		var machine machineapi.Machine
-		err = runtime.DefaultUnstructuredConverter.
+		err := runtime.DefaultUnstructuredConverter.
 			FromUnstructured(item.UnstructuredContent(), &machine)
 		if err != nil {
-			return false, fmt.Errorf("Error casting object: %s", err.Error())
+			return nil, fmt.Errorf("Error casting object: %s", err.Error())
 		}
 
 		if machine.Status.Phase == nil || *machine.Status.Phase != runningPhase {
-			metadataState = append(metadataState, fmt.Sprintf("%v", machine))
+			phase := "unknown"
+			if machine.Status.Phase != nil {
+				phase = *machine.Status.Phase
+			}
+			problems = append(problems, fmt.Sprintf("machine/%s: phase %s", machine.Name, phase))
 			logger.Printf("machine %s not ready", machine.Name)
 		}
 	}
-	if len(metadataState) > 0 {
-		metadata.Instance.SetHealthcheckValue("machines", metadataState)
-		return false, nil
-	}
 
-	metadata.Instance.ClearHealthcheckValue("machines")
-	return true, nil
+	return problems, nil
 }
@@ -0,0 +1,235 @@
+package healthchecks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimelineFile is the name of the health timeline written to the report directory.
+const TimelineFile = "health-timeline.json"
+
+// Event is a check, or an object it inspects, becoming unhealthy or recovering.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Check   string    `json:"check"`
+	Object  string    `json:"object,omitempty"`
+	Healthy bool      `json:"healthy"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// PhaseTimeline is what a monitor saw during one phase of a run.
+type PhaseTimeline struct {
+	Phase    string    `json:"phase"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Interval float64   `json:"interval"`
+	Samples  int       `json:"samples"`
+	Events   []Event   `json:"events"`
+
+	// Unhealthy is what was still unhealthy when monitoring stopped.
+	Unhealthy []string `json:"unhealthy,omitempty"`
+}
+
+// Timeline is every phase that was monitored in a run.
+type Timeline struct {
+	Phases []PhaseTimeline `json:"phases"`
+}
+
+// Monitor runs checks on an interval in the background and records when the cluster, or parts of it,
+// become unhealthy and recover. Objects are tracked by kind, name and condition, so that a node going
+// NotReady and coming back shows up as one window however long it lasts.
+type Monitor struct {
+	checks   []Check
+	clients  *Clients
+	interval time.Duration
+	logger   *log.Logger
+
+	mu        sync.Mutex
+	timeline  PhaseTimeline
+	unhealthy map[string]Event
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMonitor creates a monitor for a phase. The checks' own logging is discarded, only transitions are logged.
+func NewMonitor(phase string, checks []Check, clients *Clients, interval time.Duration) *Monitor {
+	return &Monitor{
+		checks:   checks,
+		clients:  clients,
+		interval: interval,
+		logger:   log.New(io.Discard, "", 0),
+		timeline: PhaseTimeline{
+			Phase:    phase,
+			Interval: interval.Seconds(),
+			Events:   []Event{},
+		},
+		unhealthy: map[string]Event{},
+	}
+}
+
+// Start checks the cluster every interval until Stop is called or ctx is done.
+func (m *Monitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	m.timeline.Start = time.Now().UTC()
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.sample(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops monitoring and returns the timeline.
+func (m *Monitor) Stop() PhaseTimeline {
+	if m.cancel != nil {
+		m.cancel()
+		<-m.done
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.timeline.End = time.Now().UTC()
+	m.timeline.Unhealthy = []string{}
+	for _, event := range m.unhealthy {
+		m.timeline.Unhealthy = append(m.timeline.Unhealthy, strings.TrimSpace(event.Check+" "+event.Object))
+	}
+	sort.Strings(m.timeline.Unhealthy)
+	return m.timeline
+}
+
+// sample runs the checks once and records what changed since the last sample.
+func (m *Monitor) sample(ctx context.Context) {
+	now := time.Now().UTC()
+	current := map[string]Event{}
+
+	for _, check := range m.checks {
+		result := runCheck(ctx, check, m.clients, m.logger)
+		if ctx.Err() != nil {
+			// Checks cut short by stopping say nothing about the cluster.
+			return
+		}
+
+		switch result.Status {
+		case StatusError:
+			current[check.Name()] = Event{Check: check.Name(), Detail: result.Message}
+			// The check couldn't see its objects, so assume they are as they were.
+			m.mu.Lock()
+			for key, event := range m.unhealthy {
+				if event.Check == check.Name() && event.Object != "" {
+					current[key] = event
+				}
+			}
+			m.mu.Unlock()
+		case StatusFailed:
+			for _, object := range result.Objects {
+				key := objectKey(object)
+				current[check.Name()+" "+key] = Event{Check: check.Name(), Object: key, Detail: object}
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.timeline.Samples++
+
+	var events []Event
+	for key, event := range current {
+		if _, ok := m.unhealthy[key]; !ok {
+			event.Time = now
+			events = append(events, event)
+		}
+	}
+	for key, event := range m.unhealthy {
+		if _, ok := current[key]; !ok {
+			events = append(events, Event{Time: now, Check: event.Check, Object: event.Object, Healthy: true})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Check != events[j].Check {
+			return events[i].Check < events[j].Check
+		}
+		return events[i].Object < events[j].Object
+	})
+
+	for _, event := range events {
+		state := "unhealthy"
+		if event.Healthy {
+			state = "healthy"
+		}
+		log.Printf("Health monitor (%s): %s is %s %s", m.timeline.Phase, strings.TrimSpace(event.Check+" "+event.Object), state, event.Detail)
+	}
+
+	m.timeline.Events = append(m.timeline.Events, events...)
+	m.unhealthy = current
+}
+
+// objectKey identifies an offending object across samples: the object and, if the check reported one,
+// the condition, e.g. "clusteroperator/dns Degraded=True" out of "clusteroperator/dns: Degraded=True <message>".
+func objectKey(object string) string {
+	name, detail, found := strings.Cut(object, ": ")
+	if !found {
+		return object
+	}
+	if condition := strings.Fields(detail); len(condition) > 0 && strings.Contains(condition[0], "=") {
+		return name + " " + condition[0]
+	}
+	return name
+}
+
+// ReadTimeline reads the timeline in reportDir, which is empty if nothing has been monitored yet.
+func ReadTimeline(reportDir string) (*Timeline, error) {
+	timeline := &Timeline{}
+
+	data, err := os.ReadFile(filepath.Join(reportDir, TimelineFile))
+	if os.IsNotExist(err) {
+		return timeline, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading health timeline: %v", err)
+	}
+
+	if err := json.Unmarshal(data, timeline); err != nil {
+		return nil, fmt.Errorf("error parsing health timeline: %v", err)
+	}
+	return timeline, nil
+}
+
+// WriteTimeline adds a phase to the timeline in reportDir.
+func WriteTimeline(reportDir string, phase PhaseTimeline) error {
+	timeline, err := ReadTimeline(reportDir)
+	if err != nil {
+		return err
+	}
+
+	timeline.Phases = append(timeline.Phases, phase)
+	data, err := json.MarshalIndent(timeline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding health timeline: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(reportDir, TimelineFile), data, os.FileMode(0o644)); err != nil {
+		return fmt.Errorf("error writing health timeline: %v", err)
+	}
+	return nil
+}
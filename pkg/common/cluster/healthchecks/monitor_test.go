@@ -0,0 +1,113 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// scriptedCheck returns the next set of objects or error on each run, repeating the last.
+func scriptedCheck(name string, samples ...interface{}) Check {
+	i := 0
+	return NewCheck(name, func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		sample := samples[i]
+		if i < len(samples)-1 {
+			i++
+		}
+		switch sample := sample.(type) {
+		case error:
+			return nil, sample
+		case []string:
+			return sample, nil
+		}
+		return nil, nil
+	})
+}
+
+func TestMonitorTransitions(t *testing.T) {
+	checks := []Check{
+		scriptedCheck("operator",
+			nil,
+			[]string{"clusteroperator/dns: Degraded=True rolling out"},
+			[]string{"clusteroperator/dns: Degraded=True still rolling out", "clusteroperator/dns: Available=False down"},
+			nil,
+		),
+		scriptedCheck("node",
+			[]string{"node/a: Ready=False kubelet stopped"},
+			errors.New("connection refused"),
+			[]string{"node/a: Ready=False kubelet stopped"},
+			[]string{"node/a: Ready=False kubelet stopped"},
+		),
+	}
+
+	type transition struct {
+		Check   string
+		Object  string
+		Healthy bool
+	}
+	expected := [][]transition{
+		{{"node", "node/a Ready=False", false}},
+		{{"node", "", false}, {"operator", "clusteroperator/dns Degraded=True", false}},
+		{{"node", "", true}, {"operator", "clusteroperator/dns Available=False", false}},
+		{{"operator", "clusteroperator/dns Available=False", true}, {"operator", "clusteroperator/dns Degraded=True", true}},
+	}
+
+	monitor := NewMonitor("install", checks, &Clients{}, time.Minute)
+	for i, want := range expected {
+		before := len(monitor.timeline.Events)
+		monitor.sample(context.Background())
+
+		got := []transition{}
+		for _, event := range monitor.timeline.Events[before:] {
+			got = append(got, transition{event.Check, event.Object, event.Healthy})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("sample %d: expected transitions %v, got %v", i, want, got)
+		}
+	}
+
+	timeline := monitor.Stop()
+	if timeline.Samples != len(expected) {
+		t.Errorf("expected %d samples, got %d", len(expected), timeline.Samples)
+	}
+	if want := []string{"node node/a Ready=False"}; !reflect.DeepEqual(timeline.Unhealthy, want) {
+		t.Errorf("expected %v to still be unhealthy, got %v", want, timeline.Unhealthy)
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	tests := map[string]string{
+		"node/a: Ready=False kubelet stopped":          "node/a Ready=False",
+		"daemonset/ns/x: 1 out of 3 replicas ready":    "daemonset/ns/x",
+		"cluster version operator has not finished":    "cluster version operator has not finished",
+		"clusteroperator/dns: Degraded=True":           "clusteroperator/dns Degraded=True",
+		"poddisruptionbudget/ns/x: 0 disruptions left": "poddisruptionbudget/ns/x",
+	}
+	for object, expected := range tests {
+		if key := objectKey(object); key != expected {
+			t.Errorf("%q: expected key %q, got %q", object, expected, key)
+		}
+	}
+}
+
+func TestWriteTimeline(t *testing.T) {
+	reportDir := t.TempDir()
+	for _, phase := range []string{"install", "upgrade"} {
+		monitor := NewMonitor(phase, []Check{scriptedCheck("node", nil)}, &Clients{}, time.Hour)
+		monitor.Start(context.Background())
+		if err := WriteTimeline(reportDir, monitor.Stop()); err != nil {
+			t.Fatalf("%s: unexpected error writing timeline: %v", phase, err)
+		}
+	}
+
+	timeline, err := ReadTimeline(reportDir)
+	if err != nil {
+		t.Fatalf("unexpected error reading timeline: %v", err)
+	}
+	if len(timeline.Phases) != 2 || timeline.Phases[0].Phase != "install" || timeline.Phases[1].Phase != "upgrade" {
+		t.Errorf("expected install and upgrade phases, got %+v", timeline.Phases)
+	}
+}
@@ -6,57 +6,64 @@ import (
 	"log"
 
 	"github.com/openshift/osde2e/pkg/common/logging"
-	"github.com/openshift/osde2e/pkg/common/metadata"
+	kubev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+func init() {
+	Register(NewCheck("node", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return nodeProblems(ctx, clients.Kube.CoreV1(), logger)
+	}))
+}
+
 // CheckNodeHealth attempts to look at the state of all operator and returns true if things are healthy.
 func CheckNodeHealth(nodeClient v1.CoreV1Interface, logger *log.Logger) (bool, error) {
+	problems, err := nodeProblems(context.TODO(), nodeClient, logger)
+	return err == nil && len(problems) == 0, err
+}
+
+// nodeProblems returns the nodes that aren't ready or schedulable, once per problem.
+func nodeProblems(ctx context.Context, nodeClient v1.CoreV1Interface, logger *log.Logger) ([]string, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
-	success := true
 	logger.Print("Checking that all Nodes are running or completed...")
 
 	listOpts := metav1.ListOptions{}
-	list, err := nodeClient.Nodes().List(context.TODO(), listOpts)
+	list, err := nodeClient.Nodes().List(ctx, listOpts)
 	if err != nil {
-		return false, fmt.Errorf("error getting node list: %v", err)
+		return nil, fmt.Errorf("error getting node list: %v", err)
 	}
 
-	if len(list.Items) == 0 {
-		return false, fmt.Errorf("no nodes found")
+	return unhealthyNodes(list.Items, logger)
+}
+
+// unhealthyNodes returns the nodes that aren't ready or schedulable, once per problem.
+func unhealthyNodes(nodes []kubev1.Node, logger *log.Logger) ([]string, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found")
 	}
 
-	var metadataState []string
+	var problems []string
 
-	for _, node := range list.Items {
+	for _, node := range nodes {
 		for _, ns := range node.Status.Conditions {
 			if ns.Type != "Ready" && ns.Status == "True" {
-				metadataState = append(metadataState, fmt.Sprintf("%v", node))
+				problems = append(problems, fmt.Sprintf("node/%s: %v=%v %v", node.Name, ns.Type, ns.Status, ns.Message))
 				logger.Printf("Node (%v) issue: %v=%v %v\n", node.ObjectMeta.Name, ns.Type, ns.Status, ns.Message)
-				success = false
 			} else if ns.Type == "Ready" && ns.Status != "True" {
-				metadataState = append(metadataState, fmt.Sprintf("%v", node))
+				problems = append(problems, fmt.Sprintf("node/%s: %v=%v %v", node.Name, ns.Type, ns.Status, ns.Message))
 				logger.Printf("Node (%v) not ready: %v=%v %v\n", node.ObjectMeta.Name, ns.Type, ns.Status, ns.Message)
-				success = false
 			}
 		}
 		// Check taints to ensure node is schedulable
 		for _, nt := range node.Spec.Taints {
 			if nt.Effect == "NoSchedule" && nt.Key == "node.kubernetes.io/unschedulable" {
-				metadataState = append(metadataState, fmt.Sprintf("%v", node))
+				problems = append(problems, fmt.Sprintf("node/%s: tainted %v=%v", node.Name, nt.Key, nt.Effect))
 				logger.Printf("Node (%v) not ready taint: %v=%v\n", node.ObjectMeta.Name, nt.Key, nt.Effect)
-				success = false
 			}
 		}
 	}
 
-	if len(metadataState) > 0 {
-		metadata.Instance.SetHealthcheckValue("nodes", metadataState)
-	} else {
-		metadata.Instance.ClearHealthcheckValue("nodes")
-	}
-
-	return success, nil
+	return problems, nil
 }
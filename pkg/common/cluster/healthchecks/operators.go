@@ -6,29 +6,45 @@ import (
 	"log"
 	"strings"
 
+	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/logging"
-	"github.com/openshift/osde2e/pkg/common/metadata"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func init() {
+	Register(NewOpenShiftCheck("operator", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return operatorProblems(ctx, clients.Config.ConfigV1(), logger)
+	}))
+}
+
 // CheckOperatorReadiness attempts to look at the state of all operator and returns true if things are healthy.
 func CheckOperatorReadiness(configClient configclient.ConfigV1Interface, logger *log.Logger) (bool, error) {
+	problems, err := operatorProblems(context.TODO(), configClient, logger)
+	return err == nil && len(problems) == 0, err
+}
+
+// operatorProblems returns the ClusterOperators that are unavailable, progressing or degraded, once per condition.
+func operatorProblems(ctx context.Context, configClient configclient.ConfigV1Interface, logger *log.Logger) ([]string, error) {
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 
-	success := true
 	logger.Print("Checking that all Operators are running or completed...")
 
 	listOpts := metav1.ListOptions{}
-	list, err := configClient.ClusterOperators().List(context.TODO(), listOpts)
+	list, err := configClient.ClusterOperators().List(ctx, listOpts)
 	if err != nil {
-		return false, fmt.Errorf("error getting cluster operator list: %v", err)
+		return nil, fmt.Errorf("error getting cluster operator list: %v", err)
 	}
 
-	if len(list.Items) == 0 {
-		return false, fmt.Errorf("no operators were found")
+	return unhealthyOperators(list.Items, logger)
+}
+
+// unhealthyOperators returns the ClusterOperators that are unavailable, progressing or degraded, once per condition.
+func unhealthyOperators(operators []configv1.ClusterOperator, logger *log.Logger) ([]string, error) {
+	if len(operators) == 0 {
+		return nil, fmt.Errorf("no operators were found")
 	}
 
 	// Load the list of operators we want to ignore and skip.
@@ -41,37 +57,28 @@ func CheckOperatorReadiness(configClient configclient.ConfigV1Interface, logger
 		}
 	}
 
-	var metadataState []string
+	var problems []string
 
-	for _, co := range list.Items {
+	for _, co := range operators {
 		if _, ok := operatorSkipList[co.GetName()]; !ok {
 			for _, cos := range co.Status.Conditions {
 				if cos.Type == "Available" && cos.Status == "False" {
-					metadataState = append(metadataState, fmt.Sprintf("%v", co))
+					problems = append(problems, fmt.Sprintf("clusteroperator/%s: %v=%v %v", co.Name, cos.Type, cos.Status, cos.Message))
 					logger.Printf("Operator %v not available: Condition %v has status %v: %v", co.ObjectMeta.Name, cos.Type, cos.Status, cos.Message)
-					success = false
 				}
 
 				if cos.Type == "Progressing" && cos.Status == "True" {
-					metadataState = append(metadataState, fmt.Sprintf("%v", co))
+					problems = append(problems, fmt.Sprintf("clusteroperator/%s: %v=%v %v", co.Name, cos.Type, cos.Status, cos.Message))
 					logger.Printf("Operator %v is progressing: Condition %v has status %v: %v", co.ObjectMeta.Name, cos.Type, cos.Status, cos.Message)
-					success = false
 				}
 
 				if cos.Type == "Degraded" && cos.Status == "True" {
-					metadataState = append(metadataState, fmt.Sprintf("%v", co))
+					problems = append(problems, fmt.Sprintf("clusteroperator/%s: %v=%v %v", co.Name, cos.Type, cos.Status, cos.Message))
 					logger.Printf("Operator %v is degraded: Condition %v has status %v: %v", co.ObjectMeta.Name, cos.Type, cos.Status, cos.Message)
-					success = false
 				}
 			}
 		}
 	}
 
-	if len(metadataState) > 0 {
-		metadata.Instance.SetHealthcheckValue("operators", metadataState)
-	} else {
-		metadata.Instance.ClearHealthcheckValue("operators")
-	}
-
-	return success, nil
+	return problems, nil
 }
@@ -0,0 +1,97 @@
+package healthchecks
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	kubev1 "k8s.io/api/core/v1"
+)
+
+// allowlistDateFormat is the format of an allowlist entry's expiry date.
+const allowlistDateFormat = "2006-01-02"
+
+// AllowedPod excuses known-flaky pods from pod health checks until it expires.
+type AllowedPod struct {
+	// Namespace is a regular expression the pod's namespace must match. Empty matches any namespace.
+	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// Name is a regular expression the pod's name must match. Empty matches any name.
+	Name string `json:"name" yaml:"name"`
+
+	// Expires is the last day, as YYYY-MM-DD, on which the entry applies.
+	Expires string `json:"expires" yaml:"expires"`
+
+	// Reason explains why the pods are allowed to fail, e.g. a link to the bug.
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+func (a AllowedPod) String() string {
+	return fmt.Sprintf("%s/%s until %s (%s)", a.Namespace, a.Name, a.Expires, a.Reason)
+}
+
+type allowedPod struct {
+	AllowedPod
+	namespace *regexp.Regexp
+	name      *regexp.Regexp
+}
+
+// PodAllowlist is the unexpired allowlist entries.
+type PodAllowlist []allowedPod
+
+// LoadPodAllowlist reads the allowlist from the config. Expired entries are left out, and logged so that
+// they get cleaned up.
+func LoadPodAllowlist(logger *log.Logger) (PodAllowlist, error) {
+	var entries []AllowedPod
+	if err := viper.UnmarshalKey(config.HealthChecks.PodAllowlist, &entries); err != nil {
+		return nil, fmt.Errorf("error reading pod allowlist: %v", err)
+	}
+	return newPodAllowlist(entries, time.Now(), logger)
+}
+
+func newPodAllowlist(entries []AllowedPod, now time.Time, logger *log.Logger) (PodAllowlist, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	allowlist := PodAllowlist{}
+	for _, entry := range entries {
+		expires, err := time.Parse(allowlistDateFormat, entry.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("pod allowlist entry %s has an invalid expiry date: %v", entry, err)
+		}
+		if !now.Before(expires.AddDate(0, 0, 1)) {
+			logger.Printf("Ignoring expired pod allowlist entry %s", entry)
+			continue
+		}
+
+		allowed := allowedPod{AllowedPod: entry}
+		if allowed.namespace, err = regexp.Compile("^(?:" + entry.Namespace + ")$"); err != nil {
+			return nil, fmt.Errorf("pod allowlist entry %s has an invalid namespace: %v", entry, err)
+		}
+		if allowed.name, err = regexp.Compile("^(?:" + entry.Name + ")$"); err != nil {
+			return nil, fmt.Errorf("pod allowlist entry %s has an invalid name: %v", entry, err)
+		}
+		allowlist = append(allowlist, allowed)
+	}
+	return allowlist, nil
+}
+
+// Allows returns the entry that excuses the pod, if any.
+func (a PodAllowlist) Allows(pod kubev1.Pod) (AllowedPod, bool) {
+	for _, allowed := range a {
+		if (allowed.Namespace == "" || allowed.namespace.MatchString(pod.Namespace)) &&
+			(allowed.Name == "" || allowed.name.MatchString(pod.Name)) {
+			return allowed.AllowedPod, true
+		}
+	}
+	return AllowedPod{}, false
+}
+
+// IsNotAllowed is a PodPredicate matching pods the allowlist doesn't excuse.
+func (a PodAllowlist) IsNotAllowed(pod kubev1.Pod) bool {
+	_, ok := a.Allows(pod)
+	return !ok
+}
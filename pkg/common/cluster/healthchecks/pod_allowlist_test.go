@@ -0,0 +1,79 @@
+package healthchecks
+
+import (
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodAllowlist(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	entries := []AllowedPod{
+		{Namespace: "openshift-monitoring", Name: "prometheus-k8s-.*", Expires: "2026-10-16", Reason: "expires today"},
+		{Namespace: "openshift-.*-operator", Expires: "2026-12-31", Reason: "any pod"},
+		{Name: "flaky", Expires: "2026-10-15", Reason: "expired"},
+	}
+
+	allowlist, err := newPodAllowlist(entries, now, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating allowlist: %v", err)
+	}
+
+	tests := []struct {
+		namespace string
+		name      string
+		allowed   string
+	}{
+		{"openshift-monitoring", "prometheus-k8s-0", "expires today"},
+		{"openshift-monitoring", "alertmanager-main-0", ""},
+		{"openshift-dns-operator", "dns-operator-abc", "any pod"},
+		{"openshift-dns", "dns-default-abc", ""},
+		{"default", "flaky", ""},
+		// Expressions must match the whole namespace and name.
+		{"xopenshift-monitoring", "prometheus-k8s-0", ""},
+	}
+	for _, test := range tests {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: test.namespace, Name: test.name}}
+		entry, ok := allowlist.Allows(pod)
+		if ok != (test.allowed != "") || entry.Reason != test.allowed {
+			t.Errorf("%s/%s: expected to be allowed by %q, got %q (%v)", test.namespace, test.name, test.allowed, entry.Reason, ok)
+		}
+		if allowlist.IsNotAllowed(pod) == ok {
+			t.Errorf("%s/%s: IsNotAllowed disagrees with Allows", test.namespace, test.name)
+		}
+	}
+}
+
+func TestPodAllowlistInvalid(t *testing.T) {
+	for _, entry := range []AllowedPod{
+		{Name: "pod", Expires: "next week"},
+		{Name: "pod", Expires: ""},
+		{Namespace: "(", Expires: "2099-01-01"},
+		{Name: "[", Expires: "2099-01-01"},
+	} {
+		if _, err := newPodAllowlist([]AllowedPod{entry}, time.Now(), nil); err == nil {
+			t.Errorf("%s: expected an error", entry)
+		}
+	}
+}
+
+func TestLoadPodAllowlist(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set(config.HealthChecks.PodAllowlist, []map[string]interface{}{
+		{"namespace": "openshift-logging", "name": "collector-.*", "expires": "2099-01-01", "reason": "OHSS-1"},
+	})
+
+	allowlist, err := LoadPodAllowlist(nil)
+	if err != nil {
+		t.Fatalf("unexpected error loading allowlist: %v", err)
+	}
+	if len(allowlist) != 1 || allowlist[0].Reason != "OHSS-1" {
+		t.Errorf("expected the configured entry, got %+v", allowlist)
+	}
+}
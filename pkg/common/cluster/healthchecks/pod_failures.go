@@ -0,0 +1,175 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	kubev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodFailureReason classifies why a pod isn't running or completed.
+type PodFailureReason string
+
+const (
+	// PodImagePullBackOff pods can't pull one of their images.
+	PodImagePullBackOff PodFailureReason = "ImagePullBackOff"
+
+	// PodCrashLoopBackOff pods have a container that keeps exiting.
+	PodCrashLoopBackOff PodFailureReason = "CrashLoopBackOff"
+
+	// PodUnschedulable pods can't be placed on any node.
+	PodUnschedulable PodFailureReason = "Unschedulable"
+
+	// PodOOMKilled pods have a container that ran out of memory.
+	PodOOMKilled PodFailureReason = "OOMKilled"
+
+	// PodPending pods haven't started for any other reason.
+	PodPending PodFailureReason = "Pending"
+
+	// PodFailed pods have failed for any other reason.
+	PodFailed PodFailureReason = "Failed"
+)
+
+// PodFailure is a pod that isn't running or completed, and why.
+type PodFailure struct {
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Phase     kubev1.PodPhase  `json:"phase"`
+	Reason    PodFailureReason `json:"reason"`
+	Message   string           `json:"message,omitempty"`
+
+	// AllowedBy is the allowlist entry that excuses the failure, if any.
+	AllowedBy *AllowedPod `json:"allowedBy,omitempty"`
+}
+
+// String describes the failure the way pod check results report it.
+func (f PodFailure) String() string {
+	return strings.TrimSpace(fmt.Sprintf("pod/%s/%s: %s %s", f.Namespace, f.Name, f.Reason, f.Message))
+}
+
+// ClassifyPod returns why a pod isn't running or completed, or an empty reason if it is.
+func ClassifyPod(pod kubev1.Pod) (PodFailureReason, string) {
+	if pod.Status.Phase == kubev1.PodSucceeded {
+		return "", ""
+	}
+
+	statuses := append(append([]kubev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason == string(PodOOMKilled) {
+			return PodOOMKilled, fmt.Sprintf("container %s was OOM killed", status.Name)
+		}
+		waiting := status.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		switch waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull":
+			return PodImagePullBackOff, waiting.Message
+		case "CrashLoopBackOff":
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == string(PodOOMKilled) {
+				return PodOOMKilled, fmt.Sprintf("container %s was OOM killed", status.Name)
+			}
+			return PodCrashLoopBackOff, waiting.Message
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == kubev1.PodScheduled && condition.Status == kubev1.ConditionFalse && condition.Reason == kubev1.PodReasonUnschedulable {
+			return PodUnschedulable, condition.Message
+		}
+	}
+
+	switch pod.Status.Phase {
+	case kubev1.PodRunning:
+		return "", ""
+	case kubev1.PodFailed:
+		return PodFailed, strings.TrimSpace(pod.Status.Reason + " " + pod.Status.Message)
+	case kubev1.PodPending:
+		return PodPending, strings.TrimSpace(pod.Status.Reason + " " + pod.Status.Message)
+	}
+	return PodFailureReason(pod.Status.Phase), pod.Status.Message
+}
+
+// IsUnhealthy matches pods that ClassifyPod finds a failure reason for, including running pods with a
+// crash looping container.
+func IsUnhealthy(pod kubev1.Pod) bool {
+	reason, _ := ClassifyPod(pod)
+	return reason != ""
+}
+
+// FindPodFailures returns the classified failures of the pods matching the supplied predicates. Pods the
+// allowlist excuses are returned too, marked with the entry that allows them.
+func FindPodFailures(pods []kubev1.Pod, allowlist PodAllowlist, filters ...PodPredicate) []PodFailure {
+	filters = append(filters, IsUnhealthy)
+
+	failures := []PodFailure{}
+	for _, pod := range filterPods(&kubev1.PodList{Items: pods}, filters...).Items {
+		reason, message := ClassifyPod(pod)
+		failure := PodFailure{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Phase:     pod.Status.Phase,
+			Reason:    reason,
+			Message:   message,
+		}
+		if allowed, ok := allowlist.Allows(pod); ok {
+			failure.AllowedBy = &allowed
+		}
+		failures = append(failures, failure)
+	}
+	return failures
+}
+
+func init() {
+	Register(podCheck{})
+}
+
+// podCheck reports the cluster's pods that have been failing for a while, other than those that are allowed to.
+type podCheck struct{}
+
+func (podCheck) Name() string {
+	return "pod"
+}
+
+func (podCheck) Run(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that cluster Pods are running or completed...")
+
+	allowlist, err := LoadPodAllowlist(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := clients.Kube.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pod list: %v", err)
+	}
+
+	var problems []string
+	for _, failure := range FindPodFailures(list.Items, allowlist,
+		IsClusterPod,
+		IsOlderThan(5*time.Minute),
+		IsNotReadinessPod,
+		IsNotControlledByJob,
+	) {
+		if failure.AllowedBy != nil {
+			logger.Printf("Ignoring allowed pod failure %s: %s", failure, failure.AllowedBy)
+			continue
+		}
+		problems = append(problems, failure.String())
+	}
+	return problems, nil
+}
+
+// Classify returns the failure reason of an object reported by the check.
+func (podCheck) Classify(object string) string {
+	_, detail, _ := strings.Cut(object, ": ")
+	reason, _, _ := strings.Cut(detail, " ")
+	return reason
+}
@@ -0,0 +1,94 @@
+package healthchecks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	v1 "k8s.io/api/core/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func podWithStatus(name, namespace string, status v1.PodStatus) *v1.Pod {
+	p := pod(name, namespace, map[string]string{}, status.Phase)
+	p.Status = status
+	return p
+}
+
+func waiting(reason string) v1.ContainerStatus {
+	return v1.ContainerStatus{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}}
+}
+
+var (
+	imagePullPod = podWithStatus("image", ns1, v1.PodStatus{
+		Phase:             v1.PodPending,
+		ContainerStatuses: []v1.ContainerStatus{waiting("ImagePullBackOff")},
+	})
+	crashLoopPod = podWithStatus("crash", ns1, v1.PodStatus{
+		Phase:             v1.PodRunning,
+		ContainerStatuses: []v1.ContainerStatus{waiting("CrashLoopBackOff")},
+	})
+	oomKilledPod = podWithStatus("oom", ns1, v1.PodStatus{
+		Phase: v1.PodRunning,
+		ContainerStatuses: []v1.ContainerStatus{{
+			Name:                 "app",
+			State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"}},
+		}},
+	})
+	unschedulablePod = podWithStatus("unschedulable", ns1, v1.PodStatus{
+		Phase:      v1.PodPending,
+		Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable}},
+	})
+)
+
+func TestClassifyPod(t *testing.T) {
+	tests := []struct {
+		pod      *v1.Pod
+		expected PodFailureReason
+	}{
+		{imagePullPod, PodImagePullBackOff},
+		{crashLoopPod, PodCrashLoopBackOff},
+		{oomKilledPod, PodOOMKilled},
+		{unschedulablePod, PodUnschedulable},
+		{pod("pending", ns1, nil, v1.PodPending), PodPending},
+		{pod("failed", ns1, nil, v1.PodFailed), PodFailed},
+		{pod("running", ns1, nil, v1.PodRunning), ""},
+		{pod("succeeded", ns1, nil, v1.PodSucceeded), ""},
+	}
+
+	for _, test := range tests {
+		if reason, _ := ClassifyPod(*test.pod); reason != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.pod.Name, test.expected, reason)
+		}
+	}
+}
+
+func TestPodCheck(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.HealthChecks.PodAllowlist, []map[string]interface{}{
+		{"namespace": ns1, "name": "unschedulable", "expires": "2099-01-01", "reason": "known"},
+	})
+
+	clients := &Clients{Kube: kubernetes.NewSimpleClientset(
+		imagePullPod, crashLoopPod, oomKilledPod, unschedulablePod,
+		pod("running", ns1, nil, v1.PodRunning),
+		pod("user-workload", "default", nil, v1.PodFailed),
+	)}
+
+	result := runCheck(context.Background(), podCheck{}, clients, nil)
+	if result.Status != StatusFailed {
+		t.Fatalf("expected the check to fail, got %+v", result)
+	}
+
+	expected := map[string]int{"ImagePullBackOff": 1, "CrashLoopBackOff": 1, "OOMKilled": 1}
+	if !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("expected reasons %v, got %v", expected, result.Reasons)
+	}
+	if result.Message != "3 unhealthy: 1 CrashLoopBackOff, 1 ImagePullBackOff, 1 OOMKilled" {
+		t.Errorf("unexpected message %q", result.Message)
+	}
+}
@@ -0,0 +1,49 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/openshift/osde2e/pkg/common/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1 "k8s.io/client-go/kubernetes/typed/policy/v1"
+)
+
+func init() {
+	Register(NewCheck("pdb", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return podDisruptionBudgetProblems(ctx, clients.Kube.PolicyV1(), logger)
+	}))
+}
+
+// podDisruptionBudgetProblems returns the OSD PodDisruptionBudgets that would block a node drain, as
+// they cover pods but allow none of them to be disrupted.
+func podDisruptionBudgetProblems(ctx context.Context, pdbClient policyv1.PolicyV1Interface, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	logger.Print("Checking that PodDisruptionBudgets allow drains...")
+
+	list, err := pdbClient.PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting poddisruptionbudget list: %v", err)
+	}
+
+	var problems []string
+	for _, pdb := range list.Items {
+		// Ignore budgets not managed by OSD, customers may deliberately block drains of their own pods
+		if !strings.HasPrefix(pdb.Namespace, "openshift-") {
+			continue
+		}
+		if pdb.Status.ExpectedPods == 0 || pdb.Status.DisruptionsAllowed > 0 {
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf("poddisruptionbudget/%s/%s: 0 disruptions allowed with %d of %d pods healthy",
+			pdb.Namespace, pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods))
+		logger.Printf("PodDisruptionBudget %s/%s blocks drains: %d of %d pods healthy, %d required", pdb.Namespace, pdb.Name,
+			pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods, pdb.Status.DesiredHealthy)
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,53 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func pdb(name, namespace string, expected, healthy, allowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			ExpectedPods:       expected,
+			CurrentHealthy:     healthy,
+			DisruptionsAllowed: allowed,
+		},
+	}
+}
+
+func TestPodDisruptionBudgetProblems(t *testing.T) {
+	const (
+		ns = "openshift-test-ns"
+	)
+	tests := []struct {
+		description      string
+		expectedProblems int
+		objs             []runtime.Object
+	}{
+		{"no pdbs", 0, nil},
+		{"disruptions allowed", 0, []runtime.Object{pdb("pdb1", ns, 3, 3, 1)}},
+		{"no pods covered", 0, []runtime.Object{pdb("pdb1", ns, 0, 0, 0)}},
+		{"blocks drains", 1, []runtime.Object{pdb("pdb1", ns, 3, 3, 1), pdb("pdb2", ns, 1, 1, 0)}},
+		{"outside openshift namespaces", 0, []runtime.Object{pdb("pdb1", "default", 1, 1, 0)}},
+		{"unhealthy pods", 1, []runtime.Object{pdb("pdb1", ns, 3, 2, 0)}},
+	}
+
+	for _, test := range tests {
+		kubeClient := kubernetes.NewSimpleClientset(test.objs...)
+		problems, err := podDisruptionBudgetProblems(context.TODO(), kubeClient.PolicyV1(), nil)
+
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.description, err)
+		}
+
+		if len(problems) != test.expectedProblems {
+			t.Errorf("%v: expected %d problems, got %v", test.description, test.expectedProblems, problems)
+		}
+	}
+}
@@ -18,25 +18,39 @@ import (
 type PodErrorTracker struct {
 	Counts                  map[string]int
 	MaxPendingPodsThreshold int
+
+	// Allowlist excuses known-flaky pods from the threshold.
+	Allowlist PodAllowlist
 }
 
 // NewPodErrorTracker initializes the PodErrorTracker structure with a given pending pod threshold and a new pod counter
 func (p *PodErrorTracker) NewPodErrorTracker(threshold int) *PodErrorTracker {
 	p.Counts = make(map[string]int)
 	p.MaxPendingPodsThreshold = threshold
+
+	var err error
+	if p.Allowlist, err = LoadPodAllowlist(nil); err != nil {
+		log.Printf("Not allowing any pods to stay pending: %v", err)
+	}
 	return p
 }
 
 // CheckPodHealth attempts to look at the state of all pods and returns true if things are healthy.
+// Pods excused by the configured allowlist are ignored, and the error describes why a pod failed.
 func CheckPodHealth(podClient v1.CoreV1Interface, logger *log.Logger, ns string, podPrefixes ...string) (bool, error) {
+	allowlist, err := LoadPodAllowlist(logger)
+	if err != nil {
+		return false, err
+	}
+
 	filters := []PodPredicate{
 		IsOlderThan(1 * time.Minute),
 		MatchesNamespace(ns),
 		MatchesNames(podPrefixes...),
 		IsNotReadinessPod,
-		IsNotRunning,
-		IsNotCompleted,
+		IsUnhealthy,
 		IsNotControlledByJob,
+		allowlist.IsNotAllowed,
 	}
 	podlist, err := checkPods(podClient, logger, filters...)
 	if err != nil {
@@ -56,11 +70,17 @@ func checkPods(podClient v1.CoreV1Interface, logger *log.Logger, filters ...PodP
 		return nil, fmt.Errorf("error getting pod list: %v", err)
 	}
 
-	if len(list.Items) == 0 {
+	return findPendingPods(list.Items, logger, filters...)
+}
+
+// findPendingPods returns the pods matching the supplied predicates that are still pending, and errors if any
+// has failed outright.
+func findPendingPods(podList []kubev1.Pod, logger *log.Logger, filters ...PodPredicate) ([]kubev1.Pod, error) {
+	if len(podList) == 0 {
 		return nil, fmt.Errorf("pod list is empty. this should NOT happen")
 	}
 
-	pods := filterPods(list, filters...)
+	pods := filterPods(&kubev1.PodList{Items: podList}, filters...)
 
 	// Keep track of all pending pods that are not associated with a job
 	// and store all pods associated with a job for further analysis
@@ -74,9 +94,11 @@ func checkPods(podClient v1.CoreV1Interface, logger *log.Logger, filters ...PodP
 			}
 
 			if pod.Status.Phase != kubev1.PodPending {
-				return nil, fmt.Errorf("pod %s/%s in unexpected phase %s: reason: %s message: %s", pod.Namespace, pod.Name, pod.Status.Phase, pod.Status.Reason, pod.Status.Message)
+				reason, message := ClassifyPod(pod)
+				return nil, fmt.Errorf("pod %s/%s in phase %s failed with %s: %s", pod.Namespace, pod.Name, pod.Status.Phase, reason, message)
 			}
-			logger.Printf("pod %s/%s is not ready. Phase: %s, Reason: %s, Message: %s", pod.Namespace, pod.Name, pod.Status.Phase, pod.Status.Reason, pod.Status.Message)
+			reason, message := ClassifyPod(pod)
+			logger.Printf("pod %s/%s is not ready. Phase: %s, Reason: %s, Message: %s", pod.Namespace, pod.Name, pod.Status.Phase, reason, message)
 			pendingPods = append(pendingPods, pod)
 		} else {
 			jobPods = append(jobPods, pod)
@@ -185,9 +207,13 @@ func filterPods(podList *kubev1.PodList, predicates ...PodPredicate) *kubev1.Pod
 func (p *PodErrorTracker) CheckPendingPods(podlist []kubev1.Pod) error {
 	tempTracker := make(map[string]int)
 	for _, pod := range podlist {
+		if _, ok := p.Allowlist.Allows(pod); ok {
+			continue
+		}
 		tempTracker[string(pod.UID)]++
 		if tempTracker[string(pod.UID)] >= p.MaxPendingPodsThreshold {
-			return fmt.Errorf("pod %s/%s is pending beyond normal threshold: %s - %s", pod.Namespace, pod.Name, pod.Status.Reason, pod.Status.Message)
+			reason, message := ClassifyPod(pod)
+			return fmt.Errorf("pod %s/%s is pending beyond normal threshold: %s - %s", pod.Namespace, pod.Name, reason, message)
 		}
 	}
 	p.Counts = tempTracker
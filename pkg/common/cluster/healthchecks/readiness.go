@@ -0,0 +1,224 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configlisters "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	kubev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// readinessRecheckInterval is how often the caches are re-evaluated without any changes, since pods only become
+// problems once they have been pending for a while.
+const readinessRecheckInterval = 30 * time.Second
+
+// readiness evaluates nodes, ClusterOperators, machines and pods from shared informer caches, which are kept
+// current by watches instead of being re-listed on every check.
+type readiness struct {
+	// logger is handed to the checks' rules, which are too chatty to log on every change.
+	logger *log.Logger
+
+	nodes     corelisters.NodeLister
+	pods      corelisters.PodLister
+	operators configlisters.ClusterOperatorLister
+	machines  cache.GenericLister
+
+	allowlist PodAllowlist
+
+	// changed is signalled whenever an informer sees an object change.
+	changed chan struct{}
+}
+
+// WaitForReadiness blocks until nodes, ClusterOperators, machines and pods have all been healthy for the settle
+// duration, or ctx is done. onChange is called with the unhealthy objects, by the check that reports them,
+// whenever they change. The objects are judged by the same rules as the node, operator and machine checks
+// and CheckPodHealth.
+func WaitForReadiness(ctx context.Context, clients *Clients, settle time.Duration, onChange func(problems map[string][]string), logger *log.Logger) error {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	allowlist, err := LoadPodAllowlist(logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := &readiness{
+		allowlist: allowlist,
+		logger:    log.New(io.Discard, "", 0),
+		changed:   make(chan struct{}, 1),
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { r.notify() },
+		UpdateFunc: func(interface{}, interface{}) { r.notify() },
+		DeleteFunc: func(interface{}) { r.notify() },
+	}
+
+	kubeFactory := kubeinformers.NewSharedInformerFactory(clients.Kube, 0)
+	kubeFactory.Core().V1().Nodes().Informer().AddEventHandler(handler)
+	kubeFactory.Core().V1().Pods().Informer().AddEventHandler(handler)
+	r.nodes = kubeFactory.Core().V1().Nodes().Lister()
+	r.pods = kubeFactory.Core().V1().Pods().Lister()
+	kubeFactory.Start(ctx.Done())
+	synced := kubeFactory.WaitForCacheSync(ctx.Done())
+
+	if clients.OpenShift {
+		configFactory := configinformers.NewSharedInformerFactory(clients.Config, 0)
+		configFactory.Config().V1().ClusterOperators().Informer().AddEventHandler(handler)
+		r.operators = configFactory.Config().V1().ClusterOperators().Lister()
+		configFactory.Start(ctx.Done())
+		for informer, ok := range configFactory.WaitForCacheSync(ctx.Done()) {
+			synced[informer] = ok
+		}
+
+		// Hosted control planes have no machines to watch.
+		if _, err := clients.Kube.Discovery().ServerResourcesForGroupVersion(machineResource.GroupVersion().String()); err == nil {
+			dynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(clients.Dynamic, 0, machinesNamespace, nil)
+			dynamicFactory.ForResource(machineResource).Informer().AddEventHandler(handler)
+			r.machines = dynamicFactory.ForResource(machineResource).Lister()
+			dynamicFactory.Start(ctx.Done())
+			for resource, ok := range dynamicFactory.WaitForCacheSync(ctx.Done()) {
+				if !ok {
+					return fmt.Errorf("failed to sync %s cache", resource.Resource)
+				}
+			}
+		}
+	}
+
+	for informer, ok := range synced {
+		if !ok {
+			return fmt.Errorf("failed to sync %v cache", informer)
+		}
+	}
+
+	recheck := time.NewTicker(readinessRecheckInterval)
+	defer recheck.Stop()
+
+	var last map[string][]string
+	var settled <-chan time.Time
+	for {
+		problems := r.problems()
+		if !reflect.DeepEqual(problems, last) {
+			if len(problems) == 0 {
+				logger.Printf("Cluster is ready, waiting %s for it to settle...", settle)
+				settled = time.After(settle)
+			} else {
+				logger.Printf("Cluster is not ready: %v", problems)
+				settled = nil
+			}
+			onChange(problems)
+			last = problems
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cluster did not become ready: %w, still waiting on %v", ctx.Err(), last)
+		case <-settled:
+			return nil
+		case <-r.changed:
+		case <-recheck.C:
+		}
+	}
+}
+
+func (r *readiness) notify() {
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
+
+// problems returns the unhealthy objects in the caches by the name of the check that reports them.
+func (r *readiness) problems() map[string][]string {
+	problems := map[string][]string{}
+	add := func(check string, objects []string, err error) {
+		if err != nil {
+			objects = append(objects, err.Error())
+		}
+		if len(objects) > 0 {
+			sort.Strings(objects)
+			problems[check] = objects
+		}
+	}
+
+	nodes, err := r.nodes.List(labels.Everything())
+	if err == nil {
+		items := make([]kubev1.Node, 0, len(nodes))
+		for _, node := range nodes {
+			items = append(items, *node)
+		}
+		objects, err := unhealthyNodes(items, r.logger)
+		add("node", objects, err)
+	} else {
+		add("node", nil, err)
+	}
+
+	if r.operators != nil {
+		operators, err := r.operators.List(labels.Everything())
+		if err == nil {
+			items := make([]configv1.ClusterOperator, 0, len(operators))
+			for _, operator := range operators {
+				items = append(items, *operator)
+			}
+			objects, err := unhealthyOperators(items, r.logger)
+			add("operator", objects, err)
+		} else {
+			add("operator", nil, err)
+		}
+	}
+
+	if r.machines != nil {
+		machines, err := r.machines.List(labels.Everything())
+		if err == nil {
+			items := make([]unstructured.Unstructured, 0, len(machines))
+			for _, machine := range machines {
+				items = append(items, *machine.(*unstructured.Unstructured))
+			}
+			objects, err := unhealthyMachines(items, r.logger)
+			add("machine", objects, err)
+		} else {
+			add("machine", nil, err)
+		}
+	}
+
+	pods, err := r.pods.List(labels.Everything())
+	if err == nil {
+		items := make([]kubev1.Pod, 0, len(pods))
+		for _, pod := range pods {
+			items = append(items, *pod)
+		}
+		pending, err := findPendingPods(items, r.logger,
+			IsOlderThan(1*time.Minute),
+			IsClusterPod,
+			IsNotReadinessPod,
+			IsNotRunning,
+			IsNotCompleted,
+			r.allowlist.IsNotAllowed,
+		)
+		var objects []string
+		for _, pod := range pending {
+			reason, message := ClassifyPod(pod)
+			objects = append(objects, PodFailure{Namespace: pod.Namespace, Name: pod.Name, Reason: reason, Message: message}.String())
+		}
+		add("pod", objects, err)
+	} else {
+		add("pod", nil, err)
+	}
+
+	return problems
+}
@@ -0,0 +1,84 @@
+package healthchecks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fakeConfig "github.com/openshift/client-go/config/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func runningPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	kubeClient := kubernetes.NewSimpleClientset(
+		node("a", []v1.NodeCondition{{Type: "Ready", Status: "False"}}),
+		runningPod("openshift-dns", "dns-default"),
+	)
+	configClient := fakeConfig.NewSimpleClientset(unavailableClusterOperator("dns"))
+	clients := &Clients{Kube: kubeClient, Config: configClient, OpenShift: true}
+
+	changes := make(chan map[string][]string, 10)
+	done := make(chan error)
+	go func() {
+		done <- WaitForReadiness(context.Background(), clients, 100*time.Millisecond, func(problems map[string][]string) {
+			changes <- problems
+		}, nil)
+	}()
+
+	select {
+	case problems := <-changes:
+		if len(problems["node"]) != 1 || len(problems["operator"]) != 2 {
+			t.Errorf("expected an unready node and a degraded, unavailable operator, got %v", problems)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first readiness evaluation")
+	}
+
+	ctx := context.Background()
+	if _, err := kubeClient.CoreV1().Nodes().Update(ctx, node("a", []v1.NodeCondition{{Type: "Ready", Status: "True"}}), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update node: %v", err)
+	}
+	if _, err := configClient.ConfigV1().ClusterOperators().Update(ctx, clusterOperator("dns"), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unable to update operator: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error waiting for readiness: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the cluster to settle")
+	}
+}
+
+func TestWaitForReadinessTimeout(t *testing.T) {
+	clients := &Clients{Kube: kubernetes.NewSimpleClientset(
+		node("a", []v1.NodeCondition{{Type: "Ready", Status: "True"}}),
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-dns", Name: "dns-default", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+	)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var last map[string][]string
+	err := WaitForReadiness(ctx, clients, 100*time.Millisecond, func(problems map[string][]string) { last = problems }, nil)
+	if err == nil {
+		t.Fatal("expected an error waiting on a pending pod")
+	}
+	if len(last["pod"]) != 1 || len(last) != 1 {
+		t.Errorf("expected only the pending pod to be a problem, got %v", last)
+	}
+}
@@ -2,6 +2,7 @@ package healthchecks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,74 +14,99 @@ import (
 	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 )
 
+func init() {
+	Register(NewCheck("daemonset", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return daemonSetProblems(ctx, clients.Kube.AppsV1(), logger)
+	}))
+	Register(NewCheck("replicaset", func(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+		return replicaSetProblems(ctx, clients.Kube.AppsV1(), logger)
+	}))
+}
+
 // CheckReplicaCountForDaemonSets checks if all the daemonsets running on the cluster have expected replicas
 func CheckReplicaCountForDaemonSets(dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
-	allErrors := &multierror.Error{}
+	return problemsAsError(daemonSetProblems(context.TODO(), dsClient, logger))
+}
+
+// daemonSetProblems returns the OSD daemonsets without all of their replicas ready.
+func daemonSetProblems(ctx context.Context, dsClient appsv1.AppsV1Interface, logger *log.Logger) ([]string, error) {
 	helper := helper.NewOutsideGinkgo()
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	logger.Print("Checking that all Daemonsets are running with expected replicas...")
 
-	dsList, err := dsClient.DaemonSets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	dsList, err := dsClient.DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return false, multierror.Append(allErrors, err)
+		return nil, err
+	}
+
+	if len(dsList.Items) == 0 {
+		return nil, fmt.Errorf("there are no daemonsets running on the cluster, the cluster is not running well")
 	}
 
-	dsTotalCount := len(dsList.Items)
-	if dsTotalCount != 0 {
-		for _, ds := range dsList.Items {
-			// Ignore daemonsets in the OSDE2E project
-			if helper != nil && ds.Namespace == helper.CurrentProject() {
-				continue
-			}
-			// Ignore daemonsets not managed by OSD
-			if !strings.HasPrefix(ds.Namespace, "openshift-") {
-				continue
-			}
-			if ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
-				err = fmt.Errorf("daemonset %s has %d out of %d replicas ready", ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
-				allErrors = multierror.Append(allErrors, err)
-			}
+	var problems []string
+	for _, ds := range dsList.Items {
+		// Ignore daemonsets in the OSDE2E project
+		if helper != nil && ds.Namespace == helper.CurrentProject() {
+			continue
+		}
+		// Ignore daemonsets not managed by OSD
+		if !strings.HasPrefix(ds.Namespace, "openshift-") {
+			continue
+		}
+		if ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
+			problems = append(problems, fmt.Sprintf("daemonset/%s/%s: %d out of %d replicas ready", ds.Namespace, ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled))
 		}
-	} else {
-		err = fmt.Errorf("there are no daemonsets running on the cluster, the cluster is not running well")
-		return false, multierror.Append(allErrors, err)
 	}
 
-	return allErrors.ErrorOrNil() == nil, allErrors.ErrorOrNil()
+	return problems, nil
 }
 
 // CheckReplicaCountForReplicaSets checks if all the replicasets running on the cluster have expected replicas
 func CheckReplicaCountForReplicaSets(dsClient appsv1.AppsV1Interface, logger *log.Logger) (bool, error) {
+	return problemsAsError(replicaSetProblems(context.TODO(), dsClient, logger))
+}
+
+// replicaSetProblems returns the OSD replicasets without all of their replicas ready.
+func replicaSetProblems(ctx context.Context, dsClient appsv1.AppsV1Interface, logger *log.Logger) ([]string, error) {
 	helper := helper.NewOutsideGinkgo()
-	allErrors := &multierror.Error{}
 	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
 	logger.Print("Checking that all Replicasets are running with expected replicas...")
 
-	rsList, err := dsClient.ReplicaSets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	rsList, err := dsClient.ReplicaSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return false, multierror.Append(allErrors, err)
+		return nil, err
+	}
+
+	if len(rsList.Items) == 0 {
+		return nil, fmt.Errorf("there are no replicasets running on the cluster, the cluster is not running well")
 	}
 
-	rsTotalCount := len(rsList.Items)
-	if rsTotalCount != 0 {
-		for _, rs := range rsList.Items {
-			// Ignore replicasets in the OSDE2E project
-			if helper != nil && rs.Namespace == helper.CurrentProject() {
-				continue
-			}
-			// Ignore replicasets not managed by OSD
-			if !strings.HasPrefix(rs.Namespace, "openshift-") {
-				continue
-			}
-			if rs.Status.ReadyReplicas != rs.Status.Replicas {
-				err = fmt.Errorf("replicaset %s has %d out of %d replicas ready", rs.Name, rs.Status.ReadyReplicas, rs.Status.Replicas)
-				allErrors = multierror.Append(allErrors, err)
-			}
+	var problems []string
+	for _, rs := range rsList.Items {
+		// Ignore replicasets in the OSDE2E project
+		if helper != nil && rs.Namespace == helper.CurrentProject() {
+			continue
+		}
+		// Ignore replicasets not managed by OSD
+		if !strings.HasPrefix(rs.Namespace, "openshift-") {
+			continue
+		}
+		if rs.Status.ReadyReplicas != rs.Status.Replicas {
+			problems = append(problems, fmt.Sprintf("replicaset/%s/%s: %d out of %d replicas ready", rs.Namespace, rs.Name, rs.Status.ReadyReplicas, rs.Status.Replicas))
 		}
-	} else {
-		err = fmt.Errorf("there are no replicasets running on the cluster, the cluster is not running well")
-		return false, multierror.Append(allErrors, err)
 	}
 
+	return problems, nil
+}
+
+// problemsAsError reports each problem as an error, as the replica checks always have.
+func problemsAsError(problems []string, err error) (bool, error) {
+	allErrors := &multierror.Error{}
+	if err != nil {
+		return false, multierror.Append(allErrors, err)
+	}
+	for _, problem := range problems {
+		allErrors = multierror.Append(allErrors, errors.New(problem))
+	}
 	return allErrors.ErrorOrNil() == nil, allErrors.ErrorOrNil()
 }
@@ -0,0 +1,389 @@
+package healthchecks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	machineapi "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	kubev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	machineRoleLabel    = "machine.openshift.io/cluster-api-machine-role"
+	machineClusterLabel = "machine.openshift.io/cluster-api-cluster"
+	workerNodeLabel     = "node-role.kubernetes.io/worker"
+	infraNodeLabel      = "node-role.kubernetes.io/infra"
+	nodeZoneLabel       = "topology.kubernetes.io/zone"
+
+	nodeMachineAnnotation = "machine.openshift.io/machine"
+)
+
+var machineSetResource = schema.GroupVersionResource{Group: "machine.openshift.io", Resource: "machinesets", Version: "v1beta1"}
+
+// ScaleExpectation is the compute topology a cluster should converge to after it was scaled.
+type ScaleExpectation struct {
+	// Nodes is the total number of compute nodes.
+	Nodes int
+
+	// Zones are the availability zones the compute nodes are spread across. When empty, the zones of the
+	// default machine pool's MachineSets are used.
+	Zones []string
+}
+
+// ZoneCounts returns the fewest compute nodes each zone should have when they are spread evenly across the zones.
+// Any remainder may be in any zone.
+func (e ScaleExpectation) ZoneCounts(zones []string) map[string]int {
+	counts := map[string]int{}
+	for _, zone := range zones {
+		counts[zone] = e.Nodes / len(zones)
+	}
+	return counts
+}
+
+// MachineSetScale is the observed size of a MachineSet in the default machine pool.
+type MachineSetScale struct {
+	Name      string
+	Zone      string
+	Replicas  int
+	Ready     int
+	Available int
+}
+
+// ScaleResult is the compute topology observed while waiting for a cluster to scale.
+type ScaleResult struct {
+	MachineSets []MachineSetScale
+
+	// Expected is the fewest compute nodes expected in each zone and Nodes the default machine pool's ready
+	// compute nodes in each zone.
+	Expected map[string]int
+	Nodes    map[string]int
+
+	// ScaleUp is how long after scaling started each new node became ready, and ScaleDown how long after
+	// scaling started each removed node was deleted.
+	ScaleUp   map[string]time.Duration
+	ScaleDown map[string]time.Duration
+}
+
+// scaleWatch keeps the latencies seen by the node informer's handlers.
+type scaleWatch struct {
+	started time.Time
+
+	mutex     sync.Mutex
+	scaleUp   map[string]time.Duration
+	scaleDown map[string]time.Duration
+
+	// changed is signalled whenever an informer sees an object change.
+	changed chan struct{}
+}
+
+// WaitForScale blocks until the default machine pool's MachineSets and compute nodes match the expected topology,
+// or ctx is done. Nodes created after started count as scaling up, and nodes deleted while waiting as scaling
+// down. The default machine pool must have exactly the expected replicas and nodes, so scaling down is only done
+// once the removed nodes are deleted. Nodes of other machine pools are left out.
+//
+// ErrNotApplicable is returned for clusters without the machine API or a default machine pool, such as those with
+// hosted control planes, as there is nothing to compare against.
+func WaitForScale(ctx context.Context, clients *Clients, expected ScaleExpectation, started time.Time, logger *log.Logger) (ScaleResult, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	if _, err := clients.Kube.Discovery().ServerResourcesForGroupVersion(machineSetResource.GroupVersion().String()); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ScaleResult{}, ErrNotApplicable
+		}
+		return ScaleResult{}, fmt.Errorf("error discovering the machine API: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &scaleWatch{
+		started:   started,
+		scaleUp:   map[string]time.Duration{},
+		scaleDown: map[string]time.Duration{},
+		changed:   make(chan struct{}, 1),
+	}
+
+	kubeFactory := kubeinformers.NewSharedInformerFactory(clients.Kube, 0)
+	kubeFactory.Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { w.nodeChanged(obj) },
+		UpdateFunc: func(_, obj interface{}) { w.nodeChanged(obj) },
+		DeleteFunc: func(obj interface{}) { w.nodeDeleted(obj) },
+	})
+	nodes := kubeFactory.Core().V1().Nodes().Lister()
+	kubeFactory.Start(ctx.Done())
+	for informer, ok := range kubeFactory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return ScaleResult{}, fmt.Errorf("failed to sync %v cache", informer)
+		}
+	}
+
+	dynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(clients.Dynamic, 0, machinesNamespace, nil)
+	dynamicFactory.ForResource(machineSetResource).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.notify() },
+		UpdateFunc: func(interface{}, interface{}) { w.notify() },
+		DeleteFunc: func(interface{}) { w.notify() },
+	})
+	machineSets := dynamicFactory.ForResource(machineSetResource).Lister()
+	dynamicFactory.Start(ctx.Done())
+	for resource, ok := range dynamicFactory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return ScaleResult{}, fmt.Errorf("failed to sync %s cache", resource.Resource)
+		}
+	}
+
+	var result ScaleResult
+	var last []string
+	for {
+		var problems []string
+		var err error
+		result, problems, err = w.evaluate(nodes, machineSets, expected)
+		if err != nil {
+			return result, err
+		}
+		if len(problems) == 0 {
+			logger.Printf("Cluster scaled to %d compute nodes: %v", expected.Nodes, result.Nodes)
+			return result, nil
+		}
+		if !reflect.DeepEqual(problems, last) {
+			logger.Printf("Waiting for cluster to scale: %v", problems)
+			last = problems
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("cluster did not scale to %d compute nodes: %w, still waiting on %v", expected.Nodes, ctx.Err(), last)
+		case <-w.changed:
+		}
+	}
+}
+
+func (w *scaleWatch) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (w *scaleWatch) nodeChanged(obj interface{}) {
+	defer w.notify()
+
+	node, ok := obj.(*kubev1.Node)
+	if !ok || !isComputeNode(*node) || node.CreationTimestamp.Time.Before(w.started) {
+		return
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != kubev1.NodeReady || condition.Status != kubev1.ConditionTrue {
+			continue
+		}
+		w.mutex.Lock()
+		if _, ok := w.scaleUp[node.Name]; !ok {
+			w.scaleUp[node.Name] = condition.LastTransitionTime.Sub(w.started)
+		}
+		w.mutex.Unlock()
+	}
+}
+
+func (w *scaleWatch) nodeDeleted(obj interface{}) {
+	defer w.notify()
+
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	node, ok := obj.(*kubev1.Node)
+	if !ok || !isComputeNode(*node) {
+		return
+	}
+	w.mutex.Lock()
+	w.scaleDown[node.Name] = time.Since(w.started)
+	w.mutex.Unlock()
+}
+
+// evaluate compares the cached nodes and MachineSets against the expected topology.
+func (w *scaleWatch) evaluate(nodeLister corelisters.NodeLister, machineSetLister cache.GenericLister, expected ScaleExpectation) (ScaleResult, []string, error) {
+	nodes, err := nodeLister.List(labels.Everything())
+	if err != nil {
+		return ScaleResult{}, nil, err
+	}
+	nodeItems := make([]kubev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		nodeItems = append(nodeItems, *node)
+	}
+
+	machineSets, err := machineSetLister.List(labels.Everything())
+	if err != nil {
+		return ScaleResult{}, nil, err
+	}
+	machineSetItems := make([]unstructured.Unstructured, 0, len(machineSets))
+	for _, machineSet := range machineSets {
+		machineSetItems = append(machineSetItems, *machineSet.(*unstructured.Unstructured))
+	}
+
+	result, problems, err := scaleProblems(machineSetItems, nodeItems, expected)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	result.ScaleUp = make(map[string]time.Duration, len(w.scaleUp))
+	for name, latency := range w.scaleUp {
+		result.ScaleUp[name] = latency
+	}
+	result.ScaleDown = make(map[string]time.Duration, len(w.scaleDown))
+	for name, latency := range w.scaleDown {
+		result.ScaleDown[name] = latency
+	}
+	return result, problems, err
+}
+
+// scaleProblems returns the observed topology and how the default machine pool's MachineSets and compute nodes
+// fall short of the expected one.
+func scaleProblems(machineSets []unstructured.Unstructured, nodes []kubev1.Node, expected ScaleExpectation) (ScaleResult, []string, error) {
+	workers, err := defaultPoolMachineSets(machineSets)
+	if err != nil {
+		return ScaleResult{}, nil, err
+	}
+	if len(workers) == 0 {
+		return ScaleResult{}, nil, ErrNotApplicable
+	}
+
+	zones := expected.Zones
+	if len(zones) == 0 {
+		seen := map[string]bool{}
+		for _, machineSet := range workers {
+			if !seen[machineSet.Zone] {
+				seen[machineSet.Zone] = true
+				zones = append(zones, machineSet.Zone)
+			}
+		}
+		sort.Strings(zones)
+	}
+
+	result := ScaleResult{Expected: expected.ZoneCounts(zones), Nodes: map[string]int{}}
+	var problems []string
+
+	// The default machine pool must have settled on exactly the expected replicas, however they are spread
+	// between its MachineSets.
+	replicas := 0
+	for _, machineSet := range workers {
+		if _, ok := result.Expected[machineSet.Zone]; !ok {
+			problems = append(problems, fmt.Sprintf("machineset/%s: zone %s is not an expected zone", machineSet.Name, machineSet.Zone))
+		}
+		if machineSet.Ready != machineSet.Replicas || machineSet.Available != machineSet.Replicas {
+			problems = append(problems, fmt.Sprintf("machineset/%s: replicas %d, ready %d, available %d",
+				machineSet.Name, machineSet.Replicas, machineSet.Ready, machineSet.Available))
+		}
+		replicas += machineSet.Replicas
+	}
+	if replicas != expected.Nodes {
+		problems = append(problems, fmt.Sprintf("machinepool/worker: expected %d replicas, found %d", expected.Nodes, replicas))
+	}
+	result.MachineSets = workers
+
+	// Nodes removed by scaling down still count until they are deleted, so the wait covers draining them.
+	present, ready := 0, 0
+	for _, node := range nodes {
+		if !isComputeNode(node) || !inMachineSets(node, workers) {
+			continue
+		}
+		present++
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == kubev1.NodeReady && condition.Status == kubev1.ConditionTrue {
+				result.Nodes[node.Labels[nodeZoneLabel]]++
+				ready++
+			}
+		}
+	}
+	if present != expected.Nodes {
+		problems = append(problems, fmt.Sprintf("expected %d compute nodes, found %d", expected.Nodes, present))
+	}
+	if ready != expected.Nodes {
+		problems = append(problems, fmt.Sprintf("expected %d ready compute nodes, found %d", expected.Nodes, ready))
+	}
+	for zone, count := range result.Expected {
+		if result.Nodes[zone] < count {
+			problems = append(problems, fmt.Sprintf("zone/%s: expected at least %d ready nodes, found %d", zone, count, result.Nodes[zone]))
+		}
+	}
+
+	sort.Strings(problems)
+	return result, problems, nil
+}
+
+// inMachineSets returns true if the node's machine was created by one of the MachineSets, which name their
+// machines <MachineSet>-<suffix>. Nodes without a machine annotation can't be told apart and are all included.
+func inMachineSets(node kubev1.Node, machineSets []MachineSetScale) bool {
+	machine, ok := node.Annotations[nodeMachineAnnotation]
+	if !ok {
+		return true
+	}
+	machine = machine[strings.Index(machine, "/")+1:]
+	for _, machineSet := range machineSets {
+		if strings.HasPrefix(machine, machineSet.Name+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPoolMachineSets returns the MachineSets of the default worker machine pool by name. Those of other machine
+// pools, named <infra ID>-<pool>-<zone> rather than <infra ID>-worker-<zone>, are left out along with infra and
+// master ones. MachineSets without an infra ID label can't be told apart and are all taken as the default pool.
+func defaultPoolMachineSets(machineSets []unstructured.Unstructured) ([]MachineSetScale, error) {
+	var workers []MachineSetScale
+	for _, item := range machineSets {
+		var machineSet machineapi.MachineSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), &machineSet); err != nil {
+			return nil, fmt.Errorf("Error casting object: %s", err.Error())
+		}
+		if machineSet.Spec.Template.ObjectMeta.Labels[machineRoleLabel] != "worker" {
+			continue
+		}
+		if infraID, ok := machineSet.Labels[machineClusterLabel]; ok && !strings.HasPrefix(machineSet.Name, infraID+"-worker-") {
+			continue
+		}
+
+		replicas := 1
+		if machineSet.Spec.Replicas != nil {
+			replicas = int(*machineSet.Spec.Replicas)
+		}
+		workers = append(workers, MachineSetScale{
+			Name:      machineSet.Name,
+			Zone:      machineSetZone(item),
+			Replicas:  replicas,
+			Ready:     int(machineSet.Status.ReadyReplicas),
+			Available: int(machineSet.Status.AvailableReplicas),
+		})
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+	return workers, nil
+}
+
+// machineSetZone returns the availability zone from an AWS or GCP MachineSet's provider spec.
+func machineSetZone(machineSet unstructured.Unstructured) string {
+	providerSpec := []string{"spec", "template", "spec", "providerSpec", "value"}
+	if zone, _, _ := unstructured.NestedString(machineSet.Object, append(providerSpec, "placement", "availabilityZone")...); zone != "" {
+		return zone
+	}
+	zone, _, _ := unstructured.NestedString(machineSet.Object, append(providerSpec, "zone")...)
+	return zone
+}
+
+// isComputeNode returns true for worker nodes that aren't infra nodes.
+func isComputeNode(node kubev1.Node) bool {
+	_, worker := node.Labels[workerNodeLabel]
+	_, infra := node.Labels[infraNodeLabel]
+	return worker && !infra
+}
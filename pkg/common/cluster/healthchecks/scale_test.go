@@ -0,0 +1,314 @@
+package healthchecks
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+// machineSet returns a MachineSet of a cluster with the infra ID "test", named test-<pool>-<zone>.
+func machineSet(pool, role, zone string, replicas, ready int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "machine.openshift.io/v1beta1",
+		"kind":       "MachineSet",
+		"metadata": map[string]interface{}{
+			"name":      "test-" + pool + "-" + zone,
+			"namespace": machinesNamespace,
+			"labels":    map[string]interface{}{machineClusterLabel: "test"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{machineRoleLabel: role}},
+				"spec": map[string]interface{}{"providerSpec": map[string]interface{}{"value": map[string]interface{}{
+					"placement": map[string]interface{}{"availabilityZone": zone},
+				}}},
+			},
+		},
+		"status": map[string]interface{}{"replicas": replicas, "readyReplicas": ready, "availableReplicas": ready},
+	}}
+}
+
+// computeNode returns a node of the default machine pool's MachineSet in zone.
+func computeNode(name, zone string, ready bool, created time.Time) *v1.Node {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            map[string]string{workerNodeLabel: "", nodeZoneLabel: zone},
+			Annotations:       map[string]string{nodeMachineAnnotation: machinesNamespace + "/test-worker-" + zone + "-" + name},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeReady, Status: status, LastTransitionTime: metav1.NewTime(created.Add(time.Minute))},
+		}},
+	}
+}
+
+// gpuNode returns a node of another machine pool's MachineSet in zone a.
+func gpuNode(name string, ready bool, created time.Time) *v1.Node {
+	node := computeNode(name, "a", ready, created)
+	node.Annotations[nodeMachineAnnotation] = machinesNamespace + "/test-gpu-a-" + name
+	return node
+}
+
+func TestZoneCounts(t *testing.T) {
+	tests := []struct {
+		nodes    int
+		zones    []string
+		expected map[string]int
+	}{
+		{4, []string{"a"}, map[string]int{"a": 4}},
+		{9, []string{"a", "b", "c"}, map[string]int{"a": 3, "b": 3, "c": 3}},
+		{4, []string{"a", "b", "c"}, map[string]int{"a": 1, "b": 1, "c": 1}},
+		{4, nil, map[string]int{}},
+	}
+
+	for _, test := range tests {
+		counts := ScaleExpectation{Nodes: test.nodes}.ZoneCounts(test.zones)
+		if !reflect.DeepEqual(counts, test.expected) {
+			t.Errorf("%d nodes in %v: expected %v, got %v", test.nodes, test.zones, test.expected, counts)
+		}
+	}
+}
+
+func TestScaleProblems(t *testing.T) {
+	created := time.Now()
+	tests := []struct {
+		description string
+		expected    ScaleExpectation
+		machineSets []unstructured.Unstructured
+		nodes       []v1.Node
+		problems    []string
+	}{
+		{
+			description: "converged across zones",
+			expected:    ScaleExpectation{Nodes: 3, Zones: []string{"a", "b", "c"}},
+			machineSets: []unstructured.Unstructured{
+				*machineSet("worker", "worker", "a", 1, 1),
+				*machineSet("worker", "worker", "b", 1, 1),
+				*machineSet("worker", "worker", "c", 1, 1),
+				*machineSet("infra", "infra", "a", 1, 1),
+			},
+			nodes: []v1.Node{
+				*computeNode("a1", "a", true, created),
+				*computeNode("b1", "b", true, created),
+				*computeNode("c1", "c", true, created),
+			},
+		},
+		{
+			description: "zones from machinesets",
+			expected:    ScaleExpectation{Nodes: 2},
+			machineSets: []unstructured.Unstructured{*machineSet("worker", "worker", "a", 2, 2)},
+			nodes:       []v1.Node{*computeNode("a1", "a", true, created), *computeNode("a2", "a", true, created)},
+		},
+		{
+			description: "scaling up",
+			expected:    ScaleExpectation{Nodes: 4, Zones: []string{"a"}},
+			machineSets: []unstructured.Unstructured{*machineSet("worker", "worker", "a", 4, 3)},
+			nodes: []v1.Node{
+				*computeNode("a1", "a", true, created),
+				*computeNode("a2", "a", true, created),
+				*computeNode("a3", "a", true, created),
+				*computeNode("a4", "a", false, created),
+			},
+			problems: []string{
+				"expected 4 ready compute nodes, found 3",
+				"machineset/test-worker-a: replicas 4, ready 3, available 3",
+				"zone/a: expected at least 4 ready nodes, found 3",
+			},
+		},
+		{
+			description: "scale not yet applied to the machine pool",
+			expected:    ScaleExpectation{Nodes: 6, Zones: []string{"a", "b", "c"}},
+			machineSets: []unstructured.Unstructured{
+				*machineSet("worker", "worker", "a", 1, 1),
+				*machineSet("worker", "worker", "b", 1, 1),
+				*machineSet("worker", "worker", "c", 1, 1),
+			},
+			nodes: []v1.Node{
+				*computeNode("a1", "a", true, created),
+				*computeNode("b1", "b", true, created),
+				*computeNode("c1", "c", true, created),
+			},
+			problems: []string{
+				"expected 6 compute nodes, found 3",
+				"expected 6 ready compute nodes, found 3",
+				"machinepool/worker: expected 6 replicas, found 3",
+				"zone/a: expected at least 2 ready nodes, found 1",
+				"zone/b: expected at least 2 ready nodes, found 1",
+				"zone/c: expected at least 2 ready nodes, found 1",
+			},
+		},
+		{
+			description: "scaling down",
+			expected:    ScaleExpectation{Nodes: 3, Zones: []string{"a", "b", "c"}},
+			machineSets: []unstructured.Unstructured{
+				*machineSet("worker", "worker", "a", 1, 1),
+				*machineSet("worker", "worker", "b", 1, 1),
+				*machineSet("worker", "worker", "c", 1, 1),
+			},
+			nodes: []v1.Node{
+				*computeNode("a1", "a", true, created),
+				*computeNode("a2", "a", false, created),
+				*computeNode("b1", "b", true, created),
+				*computeNode("c1", "c", true, created),
+			},
+			problems: []string{
+				"expected 3 compute nodes, found 4",
+			},
+		},
+		{
+			description: "scale not yet applied when scaling down",
+			expected:    ScaleExpectation{Nodes: 1, Zones: []string{"a"}},
+			machineSets: []unstructured.Unstructured{*machineSet("worker", "worker", "a", 2, 2)},
+			nodes:       []v1.Node{*computeNode("a1", "a", true, created), *computeNode("a2", "a", true, created)},
+			problems: []string{
+				"expected 1 compute nodes, found 2",
+				"expected 1 ready compute nodes, found 2",
+				"machinepool/worker: expected 1 replicas, found 2",
+			},
+		},
+		{
+			description: "with another machine pool",
+			expected:    ScaleExpectation{Nodes: 3, Zones: []string{"a", "b", "c"}},
+			machineSets: []unstructured.Unstructured{
+				*machineSet("worker", "worker", "a", 1, 1),
+				*machineSet("worker", "worker", "b", 1, 1),
+				*machineSet("worker", "worker", "c", 1, 1),
+				*machineSet("gpu", "worker", "a", 2, 1),
+			},
+			nodes: []v1.Node{
+				*computeNode("a1", "a", true, created),
+				*computeNode("b1", "b", true, created),
+				*computeNode("c1", "c", true, created),
+				*gpuNode("a2", true, created),
+				*gpuNode("a3", false, created),
+			},
+		},
+		{
+			description: "unexpected zone",
+			expected:    ScaleExpectation{Nodes: 1, Zones: []string{"a"}},
+			machineSets: []unstructured.Unstructured{
+				*machineSet("worker", "worker", "a", 1, 1),
+				*machineSet("worker", "worker", "b", 1, 1),
+			},
+			nodes: []v1.Node{*computeNode("a1", "a", true, created), *computeNode("b1", "b", true, created)},
+			problems: []string{
+				"expected 1 compute nodes, found 2",
+				"expected 1 ready compute nodes, found 2",
+				"machinepool/worker: expected 1 replicas, found 2",
+				"machineset/test-worker-b: zone b is not an expected zone",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		_, problems, err := scaleProblems(test.machineSets, test.nodes, test.expected)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		}
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: expected problems %v, got %v", test.description, test.problems, problems)
+		}
+	}
+
+	if _, _, err := scaleProblems([]unstructured.Unstructured{*machineSet("infra", "infra", "a", 1, 1)}, nil, ScaleExpectation{Nodes: 1}); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected the check not to apply without worker machinesets, got %v", err)
+	}
+}
+
+// machineAPIResources makes the machine API discoverable through a fake clientset.
+var machineAPIResources = []*metav1.APIResourceList{{GroupVersion: machineSetResource.GroupVersion().String()}}
+
+func TestWaitForScale(t *testing.T) {
+	started := time.Now().Add(-10 * time.Minute)
+	kubeClient := kubernetes.NewSimpleClientset(
+		computeNode("old", "a", true, started.Add(-time.Hour)),
+		computeNode("broken", "a", false, started.Add(-time.Hour)),
+	)
+	kubeClient.Resources = machineAPIResources
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{machineSetResource: "MachineSetList"},
+		machineSet("worker", "worker", "a", 2, 2))
+	clients := &Clients{Kube: kubeClient, Dynamic: dynamicClient}
+
+	done := make(chan error)
+	var result ScaleResult
+	go func() {
+		var err error
+		result, err = WaitForScale(context.Background(), clients, ScaleExpectation{Nodes: 2, Zones: []string{"a"}}, started, nil)
+		done <- err
+	}()
+
+	// The broken node is replaced by a new one.
+	time.Sleep(100 * time.Millisecond)
+	if err := kubeClient.CoreV1().Nodes().Delete(context.Background(), "broken", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unable to delete node: %v", err)
+	}
+	if _, err := kubeClient.CoreV1().Nodes().Create(context.Background(), computeNode("new", "a", true, started), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error waiting for scale: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the cluster to scale")
+	}
+
+	if result.ScaleUp["new"] != time.Minute {
+		t.Errorf("expected the new node to be ready a minute after scaling started, got %v", result.ScaleUp)
+	}
+	if _, ok := result.ScaleUp["old"]; ok {
+		t.Errorf("expected no scale up latency for the old node, got %v", result.ScaleUp)
+	}
+	if result.ScaleDown["broken"] < 10*time.Minute {
+		t.Errorf("expected a scale down latency for the broken node, got %v", result.ScaleDown)
+	}
+}
+
+func TestWaitForScaleTimeout(t *testing.T) {
+	kubeClient := kubernetes.NewSimpleClientset()
+	kubeClient.Resources = machineAPIResources
+	clients := &Clients{
+		Kube: kubeClient,
+		Dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{machineSetResource: "MachineSetList"},
+			machineSet("worker", "worker", "a", 2, 1)),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result, err := WaitForScale(ctx, clients, ScaleExpectation{Nodes: 2}, time.Now(), nil)
+	if err == nil {
+		t.Fatal("expected an error waiting on an unscaled machineset")
+	}
+	if len(result.MachineSets) != 1 || result.MachineSets[0].Ready != 1 || result.Expected["a"] != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestWaitForScaleWithoutMachineAPI(t *testing.T) {
+	clients := &Clients{Kube: kubernetes.NewSimpleClientset(computeNode("a1", "a", true, time.Now()))}
+
+	if _, err := WaitForScale(context.Background(), clients, ScaleExpectation{Nodes: 1}, time.Now(), nil); !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected the check not to apply without the machine API, got %v", err)
+	}
+}
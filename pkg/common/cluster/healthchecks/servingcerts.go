@@ -0,0 +1,243 @@
+package healthchecks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/logging"
+	"github.com/openshift/osde2e/pkg/common/metadata"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Why a serving certificate was found unhealthy.
+const (
+	CertUntrustedChain   = "UntrustedChain"
+	CertHostnameMismatch = "HostnameMismatch"
+	CertOutsideDomain    = "OutsideDomain"
+	CertExpiring         = "Expiring"
+)
+
+var routeResource = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// servingCertRoutes are the routes whose hosts are the ingress and OAuth endpoints.
+var servingCertRoutes = []struct {
+	endpoint, namespace, name string
+}{
+	{"ingress", "openshift-console", "console"},
+	{"oauth", "openshift-authentication", "oauth-openshift"},
+}
+
+func init() {
+	Register(&servingCertCheck{})
+}
+
+// servingCertCheck reports API, ingress and OAuth serving certificates that aren't trusted, don't cover their
+// endpoint's domain, or are about to expire. Endpoints the cluster doesn't have or that can't be reached, such as
+// those of private clusters, are skipped.
+type servingCertCheck struct {
+	// roots verifies the certificate chains, or the system roots if nil.
+	roots *x509.CertPool
+
+	// fetch returns the certificate chain served at an address, or fetchCertificates if nil.
+	fetch func(ctx context.Context, address string) ([]*x509.Certificate, error)
+
+	mu           sync.Mutex
+	certificates map[string]metadata.Certificate
+}
+
+func (c *servingCertCheck) Name() string {
+	return "servingcert"
+}
+
+func (c *servingCertCheck) Run(ctx context.Context, clients *Clients, logger *log.Logger) ([]string, error) {
+	logger = logging.CreateNewStdLoggerOrUseExistingLogger(logger)
+
+	if !clients.OpenShift {
+		return nil, ErrNotApplicable
+	}
+
+	logger.Print("Checking the API, ingress and OAuth serving certificates...")
+
+	threshold, err := time.ParseDuration(viper.GetString(config.HealthChecks.CertExpiryThreshold))
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing certificate expiry threshold: %v", err)
+	}
+
+	endpoints, err := servingCertEndpoints(ctx, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	fetch := c.fetch
+	if fetch == nil {
+		fetch = fetchCertificates
+	}
+
+	var problems []string
+	certificates := map[string]metadata.Certificate{}
+	now := time.Now()
+	for _, name := range []string{"api", "ingress", "oauth"} {
+		address, ok := endpoints[name]
+		if !ok {
+			logger.Printf("Skipping the %s serving certificate, the cluster has no %s endpoint", name, name)
+			continue
+		}
+		chain, err := fetch(ctx, address)
+		if err != nil {
+			logger.Printf("Skipping the %s serving certificate, %s is unreachable: %v", name, address, err)
+			continue
+		}
+
+		certificate, certProblems := validateServingCert(chain, address, c.roots, now, threshold)
+		certificates[name] = certificate
+		for _, problem := range certProblems {
+			problems = append(problems, fmt.Sprintf("certificate/%s %s: %s", name, address, problem))
+		}
+	}
+
+	c.mu.Lock()
+	c.certificates = certificates
+	c.mu.Unlock()
+
+	if len(certificates) == 0 {
+		return nil, ErrNotApplicable
+	}
+	return problems, nil
+}
+
+func (c *servingCertCheck) Classify(object string) string {
+	_, detail, _ := strings.Cut(object, ": ")
+	reason, _, _ := strings.Cut(detail, " ")
+	return reason
+}
+
+// Record adds the certificates seen by the last run to the metadata.
+func (c *servingCertCheck) Record() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, certificate := range c.certificates {
+		metadata.Instance.SetCertificate(name, certificate)
+	}
+}
+
+// servingCertEndpoints returns the addresses of the cluster's API endpoint and of the hosts of its console and OAuth
+// routes. Routes that don't exist are left out, as the console may be disabled and clusters with hosted control
+// planes serve OAuth outside of the ingress.
+func servingCertEndpoints(ctx context.Context, clients *Clients) (map[string]string, error) {
+	infrastructure, err := clients.Config.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster infrastructure: %v", err)
+	}
+
+	apiURL, err := url.Parse(infrastructure.Status.APIServerURL)
+	if err != nil || apiURL.Host == "" {
+		return nil, fmt.Errorf("invalid API server URL %q", infrastructure.Status.APIServerURL)
+	}
+	apiAddress := apiURL.Host
+	if apiURL.Port() == "" {
+		apiAddress = net.JoinHostPort(apiURL.Hostname(), "443")
+	}
+	endpoints := map[string]string{"api": apiAddress}
+
+	for _, route := range servingCertRoutes {
+		obj, err := clients.Dynamic.Resource(routeResource).Namespace(route.namespace).Get(ctx, route.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting route %s/%s: %v", route.namespace, route.name, err)
+		}
+		if host, _, _ := unstructured.NestedString(obj.Object, "spec", "host"); host != "" {
+			endpoints[route.endpoint] = net.JoinHostPort(host, "443")
+		}
+	}
+
+	return endpoints, nil
+}
+
+// fetchCertificates returns the certificate chain served at an address without verifying it.
+func fetchCertificates(ctx context.Context, address string) ([]*x509.Certificate, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 30 * time.Second},
+		// The chain is verified separately, so that untrusted certificates can still be reported on.
+		Config: &tls.Config{ServerName: host, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates, nil
+}
+
+// validateServingCert returns the details of the leaf certificate of a chain served at an address, and how it
+// fails to be trusted for that address for at least the threshold. Every name the certificate covers must be in the
+// domain of the address's host, the host without its first label.
+func validateServingCert(chain []*x509.Certificate, address string, roots *x509.CertPool, now time.Time, threshold time.Duration) (metadata.Certificate, []string) {
+	if len(chain) == 0 {
+		return metadata.Certificate{}, []string{CertUntrustedChain + " no certificate served"}
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	_, domain, _ := strings.Cut(host, ".")
+
+	leaf := chain[0]
+	dnsNames := append([]string{}, leaf.DNSNames...)
+	sort.Strings(dnsNames)
+	certificate := metadata.Certificate{
+		Host:          host,
+		Subject:       leaf.Subject.String(),
+		Issuer:        leaf.Issuer.String(),
+		DNSNames:      dnsNames,
+		NotBefore:     leaf.NotBefore.UTC(),
+		NotAfter:      leaf.NotAfter.UTC(),
+		DaysRemaining: leaf.NotAfter.Sub(now).Hours() / 24,
+	}
+
+	var problems []string
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now}); err != nil {
+		problems = append(problems, fmt.Sprintf("%s %v", CertUntrustedChain, err))
+	}
+
+	if err := leaf.VerifyHostname(host); err != nil {
+		problems = append(problems, fmt.Sprintf("%s %v", CertHostnameMismatch, err))
+	}
+	for _, name := range dnsNames {
+		if name != domain && !strings.HasSuffix(name, "."+domain) {
+			problems = append(problems, fmt.Sprintf("%s %s is not in %s", CertOutsideDomain, name, domain))
+		}
+	}
+
+	if remaining := leaf.NotAfter.Sub(now); remaining < threshold {
+		problems = append(problems, fmt.Sprintf("%s %s left, expires %s", CertExpiring, remaining.Round(time.Hour), certificate.NotAfter.Format(time.RFC3339)))
+	}
+
+	return certificate, problems
+}
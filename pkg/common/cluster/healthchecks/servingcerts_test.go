@@ -0,0 +1,182 @@
+package healthchecks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeConfig "github.com/openshift/client-go/config/clientset/versioned/fake"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/metadata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca testCA) issue(t *testing.T, validFor time.Duration, dnsNames ...string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func TestValidateServingCert(t *testing.T) {
+	ca := newTestCA(t)
+	untrusted := newTestCA(t)
+	month := 30 * 24 * time.Hour
+
+	tests := []struct {
+		description string
+		chain       []*x509.Certificate
+		address     string
+		expected    []string
+	}{
+		{"valid", []*x509.Certificate{ca.issue(t, month, "api.c.example.com")}, "api.c.example.com:6443", nil},
+		{"wildcard", []*x509.Certificate{ca.issue(t, month, "*.apps.c.example.com")}, "oauth-openshift.apps.c.example.com:443", nil},
+		{"untrusted", []*x509.Certificate{untrusted.issue(t, month, "api.c.example.com")}, "api.c.example.com:6443", []string{CertUntrustedChain}},
+		{"wrong host", []*x509.Certificate{ca.issue(t, month, "api.c.example.com")}, "api.d.example.com:6443", []string{CertHostnameMismatch, CertOutsideDomain}},
+		{"outside domain", []*x509.Certificate{ca.issue(t, month, "api.c.example.com", "api.other.com")}, "api.c.example.com:6443", []string{CertOutsideDomain}},
+		{"outside ingress domain", []*x509.Certificate{ca.issue(t, month, "*.apps.c.example.com", "*.c.example.com")}, "console.apps.c.example.com:443", []string{CertOutsideDomain}},
+		{"expiring", []*x509.Certificate{ca.issue(t, 24*time.Hour, "api.c.example.com")}, "api.c.example.com:6443", []string{CertExpiring}},
+		{"no certificate", nil, "api.c.example.com:6443", []string{CertUntrustedChain}},
+	}
+
+	for _, test := range tests {
+		_, problems := validateServingCert(test.chain, test.address, ca.pool, time.Now(), 7*24*time.Hour)
+		var reasons []string
+		for _, problem := range problems {
+			reason, _, _ := strings.Cut(problem, " ")
+			reasons = append(reasons, reason)
+		}
+		if !reflect.DeepEqual(reasons, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.description, test.expected, problems)
+		}
+	}
+}
+
+func route(namespace, name, host string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"host": host},
+	}}
+}
+
+func TestServingCertCheck(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.HealthChecks.CertExpiryThreshold, "168h")
+
+	ca := newTestCA(t)
+	chains := map[string][]*x509.Certificate{
+		"api.c.example.com:6443":                           {ca.issue(t, 90*24*time.Hour, "api.c.example.com")},
+		"console-openshift-console.apps.c.example.com:443": {ca.issue(t, 24*time.Hour, "*.apps.c.example.com")},
+	}
+	reachable := true
+	check := &servingCertCheck{
+		roots: ca.pool,
+		fetch: func(ctx context.Context, address string) ([]*x509.Certificate, error) {
+			if chain, ok := chains[address]; ok && reachable {
+				return chain, nil
+			}
+			return nil, context.DeadlineExceeded
+		},
+	}
+	// The OAuth route's host can't be reached.
+	clients := &Clients{
+		OpenShift: true,
+		Config: fakeConfig.NewSimpleClientset(
+			&configv1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Status: configv1.InfrastructureStatus{APIServerURL: "https://api.c.example.com:6443"}},
+		),
+		Dynamic: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+			route("openshift-console", "console", "console-openshift-console.apps.c.example.com"),
+			route("openshift-authentication", "oauth-openshift", "oauth-openshift.apps.c.example.com"),
+		),
+	}
+
+	metadata.Instance.Certificates = map[string]metadata.Certificate{}
+	result := Run(context.Background(), []Check{check}, clients, nil)[0]
+
+	expected := map[string]int{CertExpiring: 1}
+	if result.Status != StatusFailed || !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("expected an expiring ingress certificate, got %+v", result)
+	}
+
+	api := metadata.Instance.Certificates["api"]
+	if api.Host != "api.c.example.com" || api.DaysRemaining < 89 || !reflect.DeepEqual(api.DNSNames, []string{"api.c.example.com"}) {
+		t.Errorf("unexpected API certificate details %+v", api)
+	}
+	if _, ok := metadata.Instance.Certificates["oauth"]; ok {
+		t.Error("expected no details for the unreachable oauth certificate")
+	}
+
+	clients.Dynamic = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	if problems, err := check.Run(context.Background(), clients, nil); err != nil || len(problems) != 0 {
+		t.Errorf("expected the missing console and oauth routes to be skipped, got %v: %v", problems, err)
+	}
+
+	reachable = false
+	if _, err := check.Run(context.Background(), clients, nil); err != ErrNotApplicable {
+		t.Errorf("expected the check not to apply when no endpoint can be reached, got %v", err)
+	}
+
+	if _, err := check.Run(context.Background(), &Clients{}, nil); err != ErrNotApplicable {
+		t.Errorf("expected the check not to apply to plain Kubernetes clusters, got %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package clusterproperties
 
+import "time"
+
 // Common cluster properties
 const (
 	// MadeByOSDe2e property to attach to clusters.
@@ -25,4 +27,42 @@ const (
 
 	// ProvisionShardID is the shard ID that is set to provision a shard for the cluster.
 	ProvisionShardID = "provision_shard_id"
+
+	// PoolKey is the warm pool the cluster belongs to.
+	PoolKey = "PoolKey"
+
+	// PoolLease is the lease held on a pool cluster by the job using it.
+	PoolLease = "PoolLease"
+
+	// StatusUpdated is when the Status or JobID property was last set, in RFC 3339 format.
+	StatusUpdated = "StatusUpdated"
 )
+
+// Properties lists every property osde2e sets on clusters.
+var Properties = []string{
+	MadeByOSDe2e,
+	OwnedBy,
+	InstalledVersion,
+	UpgradeVersion,
+	Status,
+	JobName,
+	JobID,
+	ProvisionShardID,
+	PoolKey,
+	PoolLease,
+	StatusUpdated,
+}
+
+// Set sets a property, and also StatusUpdated to now if it is the Status or JobID property.
+func Set(properties map[string]string, tag, value string, now time.Time) {
+	properties[tag] = value
+	if tag == Status || tag == JobID {
+		properties[StatusUpdated] = now.UTC().Format(time.RFC3339)
+	}
+}
+
+// StatusUpdatedAt returns when the Status or JobID property was last set, if it is known.
+func StatusUpdatedAt(properties map[string]string) (time.Time, bool) {
+	updated, err := time.Parse(time.RFC3339, properties[StatusUpdated])
+	return updated, err == nil
+}
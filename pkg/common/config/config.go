@@ -88,6 +88,11 @@ const (
 
 	// Hypershift enables the use of hypershift for cluster creation.
 	Hypershift = "Hypershift"
+
+	// JobTimeout is an overall deadline for the test run, formatted for use with time.ParseDuration.
+	// Provider calls and cluster readiness waits are cancelled once it passes. Empty means no deadline.
+	// Env: JOB_TIMEOUT
+	JobTimeout = "jobTimeout"
 )
 
 // This is a config key to secret file mapping. We will attempt to read in from secret files before loading anything else.
@@ -320,6 +325,7 @@ var Cluster = struct {
 	InstallSpecificNightly string
 
 	// CleanCheckRuns lets us set the number of osd-verify checks we want to run before deeming a cluster "healthy"
+	// Deprecated: readiness is now watched for, and must hold for HealthChecks.Settle instead.
 	// Env: CLEAN_CHECK_RUNS
 	CleanCheckRuns string
 
@@ -584,6 +590,160 @@ var Proxy = struct {
 	UserCABundle: "proxy.user_ca_bundle",
 }
 
+// Pool config keys.
+var Pool = struct {
+	// Enabled will lease a warm cluster from the pool before provisioning a new one.
+	// Env: USE_CLUSTER_POOL
+	Enabled string
+
+	// Size is how many unleased clusters the pool keeps for each version, cloud provider and product.
+	// Env: CLUSTER_POOL_SIZE
+	Size string
+
+	// LeaseTTL is how long a lease lasts before it must be renewed. Clusters whose lease lapses are deleted.
+	// Env: CLUSTER_POOL_LEASE_TTL
+	LeaseTTL string
+
+	// Product is the product the pool's clusters are provisioned as.
+	// Env: CLUSTER_POOL_PRODUCT
+	Product string
+}{
+	Enabled:  "pool.enabled",
+	Size:     "pool.size",
+	LeaseTTL: "pool.leaseTTL",
+	Product:  "pool.product",
+}
+
+// HealthChecks config keys.
+var HealthChecks = struct {
+	// Checks is a comma separated list of the health checks to run, replacing the provider's checks.
+	// Env: HEALTH_CHECKS
+	Checks string
+
+	// Skip is a comma separated list of health checks not to run.
+	// Env: HEALTH_CHECKS_SKIP
+	Skip string
+
+	// MonitorInterval is how often the health checks are run in the background while tests and upgrades run.
+	// A zero duration disables the monitor.
+	// Env: HEALTH_MONITOR_INTERVAL
+	MonitorInterval string
+
+	// Settle is how long nodes, operators, machines and pods must stay healthy before a cluster is considered ready.
+	// Env: HEALTH_CHECKS_SETTLE
+	Settle string
+
+	// PodAllowlist is a list of pods, by namespace and name regular expressions, whose failures are ignored until
+	// the entry's expiry date. It can only be set in a config file.
+	PodAllowlist string
+
+	// CertExpiryThreshold is how long the API, ingress and OAuth serving certificates must remain valid for.
+	// Env: HEALTH_CHECKS_CERT_EXPIRY_THRESHOLD
+	CertExpiryThreshold string
+}{
+	Checks:              "healthChecks.checks",
+	Skip:                "healthChecks.skip",
+	MonitorInterval:     "healthChecks.monitorInterval",
+	Settle:              "healthChecks.settle",
+	PodAllowlist:        "healthChecks.podAllowlist",
+	CertExpiryThreshold: "healthChecks.certExpiryThreshold",
+}
+
+// Cleanup config keys.
+var Cleanup = struct {
+	// Policy is a YAML file of age, status and orphan thresholds and include/exclude rules, optionally per environment.
+	// Env: CLEANUP_POLICY
+	Policy string
+
+	// DryRun reports which clusters cleanup would delete without deleting them.
+	// Env: CLEANUP_DRY_RUN
+	DryRun string
+}{
+	Policy: "cleanup.policy",
+	DryRun: "cleanup.dryRun",
+}
+
+// Runner config keys.
+var Runner = struct {
+	// StreamLogs follows the logs of runner pods into the build log and a file in the report directory while they run.
+	// Env: RUNNER_STREAM_LOGS
+	StreamLogs string
+
+	// StreamLogsRate is the most lines per second of each runner container that are written to the build log.
+	// The report directory file always gets every line.
+	// Env: RUNNER_STREAM_LOGS_RATE
+	StreamLogsRate string
+
+	// ConformanceShards is the number of runner pods the OpenShift conformance suite is split across.
+	// Env: RUNNER_CONFORMANCE_SHARDS
+	ConformanceShards string
+
+	// Pod holds the pod options of every runner, unless overridden for its kind below.
+	// Env: RUNNER_REQUESTS, RUNNER_LIMITS, RUNNER_INFRA_NODES, RUNNER_TOLERATIONS, RUNNER_PRIORITY_CLASS, RUNNER_RESTRICTED
+	Pod RunnerPodOptions
+
+	// MustGather holds the pod options of must-gather and inspect runners.
+	// Env: RUNNER_MUST_GATHER_REQUESTS, RUNNER_MUST_GATHER_LIMITS, RUNNER_MUST_GATHER_INFRA_NODES, ...
+	MustGather RunnerPodOptions
+
+	// Addons holds the pod options of addon test harness runners.
+	// Env: RUNNER_ADDONS_REQUESTS, RUNNER_ADDONS_LIMITS, RUNNER_ADDONS_INFRA_NODES, ...
+	Addons RunnerPodOptions
+
+	// Scale holds the pod options of scale test runners.
+	// Env: RUNNER_SCALE_REQUESTS, RUNNER_SCALE_LIMITS, RUNNER_SCALE_INFRA_NODES, ...
+	Scale RunnerPodOptions
+}{
+	StreamLogs:        "runner.streamLogs",
+	StreamLogsRate:    "runner.streamLogsRate",
+	ConformanceShards: "runner.conformanceShards",
+	Pod:               runnerPodOptions("runner"),
+	MustGather:        runnerPodOptions("runner.mustGather"),
+	Addons:            runnerPodOptions("runner.addons"),
+	Scale:             runnerPodOptions("runner.scale"),
+}
+
+// RunnerPodOptions are the config keys for the pod options of a kind of runner.
+type RunnerPodOptions struct {
+	// Requests are the resources requested for the runner container as comma separated name=quantity pairs. ex. "cpu=500m,memory=1Gi"
+	Requests string
+
+	// Limits are the resource limits of the runner container as comma separated name=quantity pairs.
+	Limits string
+
+	// InfraNodes schedules the pod on infra nodes.
+	InfraNodes string
+
+	// Tolerations are the comma separated taints the pod tolerates, each as key[=value]:effect. ex. "dedicated=e2e:NoSchedule"
+	Tolerations string
+
+	// PriorityClass is the name of the pod's priority class.
+	PriorityClass string
+
+	// Restricted runs the pod with a security context admitted by the restricted SCC.
+	Restricted string
+}
+
+func runnerPodOptions(prefix string) RunnerPodOptions {
+	return RunnerPodOptions{
+		Requests:      prefix + ".requests",
+		Limits:        prefix + ".limits",
+		InfraNodes:    prefix + ".infraNodes",
+		Tolerations:   prefix + ".tolerations",
+		PriorityClass: prefix + ".priorityClass",
+		Restricted:    prefix + ".restricted",
+	}
+}
+
+func bindRunnerPodOptions(options RunnerPodOptions, envPrefix string) {
+	viper.BindEnv(options.Requests, envPrefix+"_REQUESTS")
+	viper.BindEnv(options.Limits, envPrefix+"_LIMITS")
+	viper.BindEnv(options.InfraNodes, envPrefix+"_INFRA_NODES")
+	viper.BindEnv(options.Tolerations, envPrefix+"_TOLERATIONS")
+	viper.BindEnv(options.PriorityClass, envPrefix+"_PRIORITY_CLASS")
+	viper.BindEnv(options.Restricted, envPrefix+"_RESTRICTED")
+}
+
 func InitOSDe2eViper() {
 	// Here's where we bind environment variables to config options and set defaults
 
@@ -621,6 +781,8 @@ func InitOSDe2eViper() {
 	viper.SetDefault(MustGather, true)
 	viper.BindEnv(MustGather, "MUST_GATHER")
 
+	viper.BindEnv(JobTimeout, "JOB_TIMEOUT")
+
 	viper.BindEnv(CanaryChance, "CANARY_CHANCE")
 
 	// ----- Upgrade -----
@@ -862,6 +1024,57 @@ func InitOSDe2eViper() {
 	viper.BindEnv(Database.DatabaseName, "PG_DATABASE")
 	RegisterSecret(Database.DatabaseName, "rds-database")
 
+	// ----- Pool -----
+	viper.SetDefault(Pool.Enabled, false)
+	viper.BindEnv(Pool.Enabled, "USE_CLUSTER_POOL")
+
+	viper.SetDefault(Pool.Size, 2)
+	viper.BindEnv(Pool.Size, "CLUSTER_POOL_SIZE")
+
+	viper.SetDefault(Pool.LeaseTTL, "6h")
+	viper.BindEnv(Pool.LeaseTTL, "CLUSTER_POOL_LEASE_TTL")
+
+	viper.SetDefault(Pool.Product, "osd")
+	viper.BindEnv(Pool.Product, "CLUSTER_POOL_PRODUCT")
+
+	// ----- Health Checks -----
+	viper.BindEnv(HealthChecks.Checks, "HEALTH_CHECKS")
+
+	viper.BindEnv(HealthChecks.Skip, "HEALTH_CHECKS_SKIP")
+
+	viper.SetDefault(HealthChecks.MonitorInterval, "1m")
+	viper.BindEnv(HealthChecks.MonitorInterval, "HEALTH_MONITOR_INTERVAL")
+
+	viper.SetDefault(HealthChecks.Settle, "2m")
+	viper.BindEnv(HealthChecks.Settle, "HEALTH_CHECKS_SETTLE")
+
+	viper.SetDefault(HealthChecks.CertExpiryThreshold, "168h")
+	viper.BindEnv(HealthChecks.CertExpiryThreshold, "HEALTH_CHECKS_CERT_EXPIRY_THRESHOLD")
+
+	// ----- Cleanup -----
+	viper.BindEnv(Cleanup.Policy, "CLEANUP_POLICY")
+
+	viper.SetDefault(Cleanup.DryRun, false)
+	viper.BindEnv(Cleanup.DryRun, "CLEANUP_DRY_RUN")
+
+	// ----- Runner -----
+	viper.SetDefault(Runner.StreamLogs, false)
+	viper.BindEnv(Runner.StreamLogs, "RUNNER_STREAM_LOGS")
+
+	viper.SetDefault(Runner.StreamLogsRate, 50)
+	viper.BindEnv(Runner.StreamLogsRate, "RUNNER_STREAM_LOGS_RATE")
+
+	viper.SetDefault(Runner.ConformanceShards, 1)
+	viper.BindEnv(Runner.ConformanceShards, "RUNNER_CONFORMANCE_SHARDS")
+
+	// Only the defaults of all runners are set, so that the options of a kind of runner fall back to them.
+	viper.SetDefault(Runner.Pod.InfraNodes, false)
+	viper.SetDefault(Runner.Pod.Restricted, false)
+	bindRunnerPodOptions(Runner.Pod, "RUNNER")
+	bindRunnerPodOptions(Runner.MustGather, "RUNNER_MUST_GATHER")
+	bindRunnerPodOptions(Runner.Addons, "RUNNER_ADDONS")
+	bindRunnerPodOptions(Runner.Scale, "RUNNER_SCALE")
+
 	// ----- Proxy ------
 	viper.BindEnv(Proxy.HttpProxy, "TEST_HTTP_PROXY")
 	RegisterSecret(Proxy.HttpProxy, "test-http-proxy")
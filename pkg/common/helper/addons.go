@@ -7,6 +7,7 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/runner"
 	"github.com/openshift/osde2e/pkg/common/templates"
 	"github.com/openshift/osde2e/pkg/common/util"
@@ -69,6 +70,7 @@ func (h *H) RunAddonTests(ctx context.Context, name string, timeout int, harness
 		Expect(err).NotTo(HaveOccurred())
 
 		r.Name = "addon-tests"
+		h.SetRunnerPodOptions(r, config.Runner.Addons)
 		r.Cmd = addonTestCommand
 
 		// run tests
@@ -76,11 +78,8 @@ func (h *H) RunAddonTests(ctx context.Context, name string, timeout int, harness
 		err = r.Run(timeout, stopCh)
 		Expect(err).NotTo(HaveOccurred())
 
-		// get results
-		results, err := r.RetrieveTestResults()
-
-		// write results
-		h.WriteResults(results)
+		// download results into the report directory
+		_, err = r.DownloadTestResults(h.ResultsDir())
 
 		// evaluate results
 		Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// SkipUnlessCapable skips the current spec if the configured cluster provider lacks the given capability.
+func SkipUnlessCapable(capability spi.Capability) {
+	provider, err := providers.ClusterProvider()
+	Expect(err).NotTo(HaveOccurred(), "could not get the cluster provider")
+
+	if !provider.Capabilities().Supports(capability) {
+		ginkgo.Skip(fmt.Sprintf("provider %s does not support %s", provider.Type(), capability))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/runner"
 )
 
@@ -13,6 +14,7 @@ func (h *H) InspectOLM(ctx context.Context) error {
 	h.SetServiceAccount(ctx, "system:serviceaccount:%s:cluster-admin")
 	r := h.Runner(fmt.Sprintf("oc adm inspect --dest-dir=%v -A olm", runner.DefaultRunner.OutputDir))
 	r.Name = "olm-inspect"
+	h.SetRunnerPodOptions(r, config.Runner.MustGather)
 	r.Tarball = true
 	stopCh := make(chan struct{})
 
@@ -21,11 +23,8 @@ func (h *H) InspectOLM(ctx context.Context) error {
 		return fmt.Errorf("Error running OLM inspection: %s", err.Error())
 	}
 
-	gatherResults, err := r.RetrieveResults()
-	if err != nil {
+	if _, err = r.DownloadResults(h.ResultsDir()); err != nil {
 		return fmt.Errorf("Error retrieving OLM inspection results: %s", err.Error())
 	}
-
-	h.WriteResults(gatherResults)
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/runner"
 
 	projectv1 "github.com/openshift/api/project/v1"
@@ -65,6 +66,7 @@ func (h *H) inspect(ctx context.Context, projects []string) error {
 	projectsArg := strings.Join(inspectProjects, " ")
 	r := h.Runner(fmt.Sprintf("oc adm inspect %v --dest-dir=%v", projectsArg, runner.DefaultRunner.OutputDir))
 	r.Name = "must-gather-additional-projects"
+	h.SetRunnerPodOptions(r, config.Runner.MustGather)
 	r.Tarball = true
 	stopCh := make(chan struct{})
 
@@ -73,11 +75,8 @@ func (h *H) inspect(ctx context.Context, projects []string) error {
 		return fmt.Errorf("error running project inspection: %s", err.Error())
 	}
 
-	gatherResults, err := r.RetrieveResults()
-	if err != nil {
+	if _, err = r.DownloadResults(h.ResultsDir()); err != nil {
 		return fmt.Errorf("error retrieving project inspection results: %s", err.Error())
 	}
-
-	h.WriteResults(gatherResults)
 	return nil
 }
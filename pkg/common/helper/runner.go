@@ -22,9 +22,17 @@ func (h *H) RunnerWithNoCommand() *runner.Runner {
 	// setup tests
 	r.Namespace = h.CurrentProject()
 	r.PodSpec.ServiceAccountName = h.GetNamespacedServiceAccount()
+	h.SetRunnerPodOptions(r, config.Runner.Pod)
 	return r
 }
 
+// SetRunnerPodOptions configures the Pod of a runner with the options for its kind of runner.
+func (h *H) SetRunnerPodOptions(r *runner.Runner, keys config.RunnerPodOptions) {
+	options, err := runner.PodOptionsFromConfig(keys)
+	Expect(err).NotTo(HaveOccurred())
+	r.PodOptions = options
+}
+
 // Runner creates an extended test suite runner and configure RBAC for it and runs cmd in it.
 func (h *H) Runner(cmd string) *runner.Runner {
 	r := h.RunnerWithNoCommand()
@@ -33,10 +41,15 @@ func (h *H) Runner(cmd string) *runner.Runner {
 	return r
 }
 
+// ResultsDir is the directory in the ReportDir that runner results are written to.
+func (h *H) ResultsDir() string {
+	return filepath.Join(viper.GetString(config.ReportDir), viper.GetString(config.Phase))
+}
+
 // WriteResults dumps runner results into the ReportDir.
 func (h *H) WriteResults(results map[string][]byte) {
 	for filename, data := range results {
-		dst := filepath.Join(viper.GetString(config.ReportDir), viper.GetString(config.Phase), filename)
+		dst := filepath.Join(h.ResultsDir(), filename)
 		err := os.MkdirAll(filepath.Dir(dst), os.FileMode(0o755))
 		Expect(err).NotTo(HaveOccurred())
 		err = os.WriteFile(dst, data, os.ModePerm)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/openshift/osde2e/pkg/common/phase"
 )
@@ -46,13 +47,51 @@ type Metadata struct {
 	RouteThroughputs            map[string]float64 `json:"route-throughputs"`
 	RouteAvailabilities         map[string]float64 `json:"route-availabilities"`
 
+	// Certificates are the serving certificates presented by the cluster's API, ingress and OAuth endpoints.
+	Certificates map[string]Certificate `json:"certificates"`
+
+	// ScaleUpLatencies and ScaleDownLatencies are how long after scaling started each compute node became ready
+	// or was removed, in seconds.
+	ScaleUpLatencies   map[string]float64 `json:"scale-up-latencies"`
+	ScaleDownLatencies map[string]float64 `json:"scale-down-latencies"`
+
+	// Provisioning is the time spent in each cluster state, in seconds, and counts of other provisioning events.
+	Provisioning map[string]float64 `json:"provisioning"`
+
 	// Real Time Data
-	HealthChecks         map[string][]string `json:"healthchecks"`
-	HealthCheckIteration float64             `json:"healthcheckIteration"`
-	Status               string              `json:"status"`
+	HealthChecks         map[string]HealthCheck `json:"healthchecks"`
+	HealthCheckIteration float64                `json:"healthcheckIteration"`
+	Status               string                 `json:"status"`
 
 	// Internal variables
 	ReportDir string `json:"-"`
+
+	// ProvisioningTimeline is written to its own file rather than with the metadata.
+	ProvisioningTimeline ProvisioningTimeline `json:"-"`
+}
+
+// HealthCheck is the latest outcome of a health check that found the cluster unhealthy.
+type HealthCheck struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message,omitempty"`
+	Duration float64  `json:"duration"`
+	Objects  []string `json:"objects,omitempty"`
+
+	// Reasons counts the unhealthy objects by why they are unhealthy, for checks that can tell.
+	Reasons map[string]int `json:"reasons,omitempty"`
+}
+
+// Certificate is a serving certificate presented by one of the cluster's endpoints.
+type Certificate struct {
+	Host      string    `json:"host"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns-names"`
+	NotBefore time.Time `json:"not-before"`
+	NotAfter  time.Time `json:"not-after"`
+
+	// DaysRemaining is how many days were left before the certificate expires when it was checked.
+	DaysRemaining float64 `json:"days-remaining"`
 }
 
 // Instance is the global metadata instance
@@ -67,7 +106,13 @@ func init() {
 	Instance.RouteLatencies = make(map[string]float64)
 	Instance.RouteThroughputs = make(map[string]float64)
 	Instance.RouteAvailabilities = make(map[string]float64)
-	Instance.HealthChecks = make(map[string][]string)
+	Instance.Certificates = make(map[string]Certificate)
+	Instance.ScaleUpLatencies = make(map[string]float64)
+	Instance.ScaleDownLatencies = make(map[string]float64)
+	Instance.Provisioning = make(map[string]float64)
+	Instance.ProvisioningTimeline.Events = []ProvisioningEvent{}
+	Instance.ProvisioningTimeline.Durations = make(map[string]float64)
+	Instance.HealthChecks = make(map[string]HealthCheck)
 }
 
 // Next are a bunch of setter functions that allow us
@@ -151,8 +196,14 @@ func (m *Metadata) SetTimeToCertificateIssued(timeToCertificateIssued float64) {
 	m.WriteToJSON(m.ReportDir)
 }
 
-// SetHealthcheckValue sets an arbitrary string value to a healthcheck
-func (m *Metadata) SetHealthcheckValue(key string, value []string) {
+// SetCertificate sets the serving certificate presented by the given endpoint
+func (m *Metadata) SetCertificate(endpoint string, certificate Certificate) {
+	m.Certificates[endpoint] = certificate
+	m.WriteToJSON(m.ReportDir)
+}
+
+// SetHealthcheckValue sets the outcome of an unhealthy healthcheck
+func (m *Metadata) SetHealthcheckValue(key string, value HealthCheck) {
 	if !reflect.DeepEqual(m.HealthChecks[key], value) {
 		m.HealthChecks[key] = value
 		m.WriteToJSON(m.ReportDir)
@@ -256,6 +307,20 @@ func (m *Metadata) SetRouteAvailability(route string, availability float64) {
 	m.WriteToJSON(m.ReportDir)
 }
 
+// SetScaleUpLatency sets how long the given node took to become ready after scaling started
+// (measured in seconds)
+func (m *Metadata) SetScaleUpLatency(node string, latency float64) {
+	m.ScaleUpLatencies[node] = latency
+	m.WriteToJSON(m.ReportDir)
+}
+
+// SetScaleDownLatency sets how long the given node took to be removed after scaling started
+// (measured in seconds)
+func (m *Metadata) SetScaleDownLatency(node string, latency float64) {
+	m.ScaleDownLatencies[node] = latency
+	m.WriteToJSON(m.ReportDir)
+}
+
 // WriteToJSON will marshall the metadata struct and write it into the given file.
 func (m *Metadata) WriteToJSON(reportDir string) (err error) {
 	var data []byte
@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProvisioningTimelineFile is the name of the provisioning timeline written to the report directory.
+const ProvisioningTimelineFile string = "provisioning-timeline.json"
+
+// Kinds of provisioning events.
+const (
+	ProvisioningEventState           = "state"
+	ProvisioningEventExpiryExtension = "expiry-extension"
+	ProvisioningEventKubeconfig      = "kubeconfig"
+	ProvisioningEventHealthcheckJob  = "healthcheck-job"
+)
+
+// ProvisioningEvent is something that happened while a cluster was provisioned.
+type ProvisioningEvent struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	State  string    `json:"state,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ProvisioningTimeline is every provisioning event of a run, in order.
+type ProvisioningTimeline struct {
+	Events []ProvisioningEvent `json:"events"`
+
+	// Durations is how long the cluster was observed in each state, in seconds.
+	Durations map[string]float64 `json:"durations"`
+
+	state      string
+	stateSince time.Time
+}
+
+// RecordClusterState records the cluster's state as last observed. Only changes are recorded, and the time
+// spent in the previous state is added to its duration and the provisioning metrics.
+func (m *Metadata) RecordClusterState(state string) {
+	timeline := &m.ProvisioningTimeline
+	if state == timeline.state {
+		return
+	}
+
+	now := time.Now().UTC()
+	if timeline.state != "" {
+		elapsed := now.Sub(timeline.stateSince).Seconds()
+		timeline.Durations[timeline.state] += elapsed
+		m.Provisioning["time-in-"+timeline.state] += elapsed
+	}
+	timeline.state = state
+	timeline.stateSince = now
+
+	m.recordProvisioningEvent(ProvisioningEvent{Time: now, Kind: ProvisioningEventState, State: state})
+}
+
+// RecordExpiryExtension records an attempt to extend the cluster's expiration.
+func (m *Metadata) RecordExpiryExtension(extension time.Duration, err error) {
+	m.Provisioning["expiry-extensions"]++
+	m.recordProvisioningEvent(ProvisioningEvent{
+		Time:   time.Now().UTC(),
+		Kind:   ProvisioningEventExpiryExtension,
+		Detail: extension.String(),
+		Error:  errorString(err),
+	})
+}
+
+// RecordKubeconfigAttempt records an attempt to retrieve the cluster's kubeconfig.
+func (m *Metadata) RecordKubeconfigAttempt(err error) {
+	m.Provisioning["kubeconfig-attempts"]++
+	if err != nil {
+		m.Provisioning["kubeconfig-failures"]++
+	}
+	m.recordProvisioningEvent(ProvisioningEvent{
+		Time:  time.Now().UTC(),
+		Kind:  ProvisioningEventKubeconfig,
+		Error: errorString(err),
+	})
+}
+
+// RecordHealthcheckJob records how long the cluster's healthcheck job took to pass, or to give up.
+func (m *Metadata) RecordHealthcheckJob(duration time.Duration, err error) {
+	m.Provisioning["healthcheck-job"] = duration.Seconds()
+	m.recordProvisioningEvent(ProvisioningEvent{
+		Time:   time.Now().UTC(),
+		Kind:   ProvisioningEventHealthcheckJob,
+		Detail: duration.Round(time.Second).String(),
+		Error:  errorString(err),
+	})
+}
+
+func (m *Metadata) recordProvisioningEvent(event ProvisioningEvent) {
+	m.ProvisioningTimeline.Events = append(m.ProvisioningTimeline.Events, event)
+	if m.ReportDir != "" {
+		m.WriteToJSON(m.ReportDir)
+		m.WriteProvisioningTimeline(m.ReportDir)
+	}
+}
+
+// WriteProvisioningTimeline writes the provisioning timeline into the given directory.
+func (m *Metadata) WriteProvisioningTimeline(reportDir string) error {
+	data, err := json.MarshalIndent(m.ProvisioningTimeline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(reportDir, ProvisioningTimelineFile), data, os.FileMode(0o644))
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
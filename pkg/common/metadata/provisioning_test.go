@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProvisioningTimeline(t *testing.T) {
+	m := &Metadata{
+		ReportDir:    t.TempDir(),
+		Provisioning: map[string]float64{},
+		ProvisioningTimeline: ProvisioningTimeline{
+			Events:    []ProvisioningEvent{},
+			Durations: map[string]float64{},
+		},
+	}
+
+	for _, state := range []string{"pending_account", "pending_account", "pending", "installing", "installing", "ready"} {
+		m.RecordClusterState(state)
+	}
+	m.RecordHealthcheckJob(90*time.Second, nil)
+	m.RecordKubeconfigAttempt(errors.New("not yet"))
+	m.RecordKubeconfigAttempt(nil)
+	m.RecordExpiryExtension(6*time.Hour, nil)
+
+	kinds := []string{}
+	for _, event := range m.ProvisioningTimeline.Events {
+		kinds = append(kinds, event.Kind+":"+event.State)
+	}
+	expected := []string{
+		"state:pending_account", "state:pending", "state:installing", "state:ready",
+		"healthcheck-job:", "kubeconfig:", "kubeconfig:", "expiry-extension:",
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected events %v, got %v", expected, kinds)
+	}
+
+	for _, state := range []string{"pending_account", "pending", "installing"} {
+		if _, ok := m.ProvisioningTimeline.Durations[state]; !ok {
+			t.Errorf("expected a duration for %s", state)
+		}
+		if _, ok := m.Provisioning["time-in-"+state]; !ok {
+			t.Errorf("expected a metric for %s", state)
+		}
+	}
+	if _, ok := m.ProvisioningTimeline.Durations["ready"]; ok {
+		t.Error("expected no duration for the current state")
+	}
+	if m.Provisioning["kubeconfig-attempts"] != 2 || m.Provisioning["kubeconfig-failures"] != 1 ||
+		m.Provisioning["expiry-extensions"] != 1 || m.Provisioning["healthcheck-job"] != 90 {
+		t.Errorf("unexpected provisioning metrics %v", m.Provisioning)
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.ReportDir, ProvisioningTimelineFile))
+	if err != nil {
+		t.Fatalf("expected a provisioning timeline: %v", err)
+	}
+	timeline := ProvisioningTimeline{}
+	if err := json.Unmarshal(data, &timeline); err != nil {
+		t.Fatalf("unable to parse provisioning timeline: %v", err)
+	}
+	if len(timeline.Events) != len(expected) || timeline.Events[5].Error != "not yet" {
+		t.Errorf("unexpected provisioning timeline %+v", timeline)
+	}
+}
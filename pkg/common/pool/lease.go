@@ -0,0 +1,189 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+// errClaimLost is returned when another claim on the same cluster won.
+var errClaimLost = errors.New("cluster was claimed by someone else")
+
+// Lease is exclusive use of a pooled cluster until it expires.
+//
+// Leases are stored in the cluster's PoolLease property as "<token>@<expiry>". Properties can only
+// be overwritten, so a claim writes its token, waits for the settle time and reads the property
+// back: of several claims that saw the cluster free, only the last writer sees its own token. A
+// claim whose write took longer than half the settle time could have landed after a competing claim
+// had already checked, so it never uses the cluster and lets its lease lapse instead.
+type Lease struct {
+	ClusterID string
+
+	// Resumed is true if the cluster was hibernating when it was leased.
+	Resumed bool
+
+	pool  *Pool
+	token string
+
+	mu      sync.Mutex
+	expires time.Time
+}
+
+// Expires returns when the lease runs out unless renewed.
+func (l *Lease) Expires() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.expires
+}
+
+// Renew extends the lease by the pool's TTL. It fails if the lease has already lapsed or has been
+// taken over, in which case the cluster must no longer be used.
+func (l *Lease) Renew(ctx context.Context) error {
+	cluster, err := l.verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	expires := time.Now().Add(l.pool.ttl)
+	if err := l.pool.provider.AddPropertyContext(ctx, cluster, clusterproperties.PoolLease, formatLease(l.token, expires)); err != nil {
+		return fmt.Errorf("error renewing lease on cluster %s: %v", l.ClusterID, err)
+	}
+
+	l.mu.Lock()
+	l.expires = expires
+	l.mu.Unlock()
+	return nil
+}
+
+// Release gives the cluster back to the pool. Only release clusters that haven't been used, as the
+// next job to lease it will expect it to be untouched.
+func (l *Lease) Release(ctx context.Context) error {
+	cluster, err := l.verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := l.pool.provider.AddPropertyContext(ctx, cluster, clusterproperties.PoolLease, ""); err != nil {
+		return fmt.Errorf("error releasing lease on cluster %s: %v", l.ClusterID, err)
+	}
+	return nil
+}
+
+// End gives up the lease on a cluster that has been used. The pool then replaces the cluster as it
+// would once an abandoned lease lapses, rather than leaving it leased until the TTL runs out.
+func (l *Lease) End(ctx context.Context) error {
+	cluster, err := l.verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	expires := time.Now()
+	if err := l.pool.provider.AddPropertyContext(ctx, cluster, clusterproperties.PoolLease, formatLease(l.token, expires)); err != nil {
+		return fmt.Errorf("error ending lease on cluster %s: %v", l.ClusterID, err)
+	}
+
+	l.mu.Lock()
+	l.expires = expires
+	l.mu.Unlock()
+	return nil
+}
+
+// KeepAlive renews the lease at a third of the pool's TTL until ctx is done or a renewal fails.
+func (l *Lease) KeepAlive(ctx context.Context) error {
+	ticker := time.NewTicker(l.pool.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := l.Renew(ctx); err != nil {
+				log.Printf("Lost lease on pooled cluster %s: %v", l.ClusterID, err)
+				return err
+			}
+		}
+	}
+}
+
+// verify returns the cluster if the lease is still held.
+func (l *Lease) verify(ctx context.Context) (*spi.Cluster, error) {
+	cluster, err := l.pool.provider.GetClusterContext(ctx, l.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving leased cluster %s: %v", l.ClusterID, err)
+	}
+
+	token, expires, err := parseLease(cluster.Properties()[clusterproperties.PoolLease])
+	if err != nil || token != l.token {
+		return nil, fmt.Errorf("lease on cluster %s is no longer held", l.ClusterID)
+	}
+	if time.Now().After(expires) {
+		return nil, fmt.Errorf("lease on cluster %s expired at %s", l.ClusterID, expires.Format(time.RFC3339))
+	}
+	return cluster, nil
+}
+
+// claim leases a cluster that was seen to be available, returning the lease and the cluster as it was
+// once the claim settled.
+func (p *Pool) claim(ctx context.Context, clusterID string) (*Lease, *spi.Cluster, error) {
+	start := time.Now()
+
+	cluster, err := p.provider.GetClusterContext(ctx, clusterID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error retrieving pooled cluster %s: %v", clusterID, err)
+	}
+	if p.classify(cluster, start) != stateAvailable {
+		return nil, nil, errClaimLost
+	}
+
+	lease := &Lease{
+		ClusterID: clusterID,
+		pool:      p,
+		token:     fmt.Sprintf("%s-%s", p.owner, util.RandomStr(8)),
+		expires:   time.Now().Add(p.ttl),
+	}
+	if err := p.provider.AddPropertyContext(ctx, cluster, clusterproperties.PoolLease, formatLease(lease.token, lease.expires)); err != nil {
+		return nil, nil, fmt.Errorf("error claiming pooled cluster %s: %v", clusterID, err)
+	}
+	if time.Since(start) > p.settle/2 {
+		return nil, nil, errClaimLost
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-time.After(p.settle):
+	}
+
+	cluster, err = lease.verify(ctx)
+	if err != nil {
+		return nil, nil, errClaimLost
+	}
+	return lease, cluster, nil
+}
+
+func formatLease(token string, expires time.Time) string {
+	return fmt.Sprintf("%s@%s", token, expires.UTC().Format(time.RFC3339))
+}
+
+func parseLease(lease string) (string, time.Time, error) {
+	separator := strings.LastIndex(lease, "@")
+	if separator < 0 {
+		return "", time.Time{}, fmt.Errorf("malformed lease %q", lease)
+	}
+
+	expires, err := time.Parse(time.RFC3339, lease[separator+1:])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("malformed lease %q: %v", lease, err)
+	}
+	return lease[:separator], expires, nil
+}
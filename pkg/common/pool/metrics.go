@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+var (
+	// acquisitions counts Acquire calls by whether they leased a cluster, giving the pool's hit rate.
+	acquisitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cicd_pool_acquisitions",
+			Help: "Attempts to lease a pooled cluster, by result (hit or miss).",
+		},
+		[]string{"pool", "result"},
+	)
+
+	// poolClusters is the number of pooled clusters by state as of the last reconcile.
+	poolClusters = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cicd_pool_clusters",
+			Help: "Pooled clusters by state (available, provisioning or leased).",
+		},
+		[]string{"pool", "state"},
+	)
+)
+
+// Collectors returns the pool's metrics so they can be registered alongside other osde2e metrics.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{acquisitions, poolClusters}
+}
+
+// WritePrometheusFile writes the pool's metrics to filename in the Prometheus text format.
+func WritePrometheusFile(filename string) error {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(Collectors()...)
+
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("error gathering pool metrics: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(buf, expfmt.FmtText)
+	for _, metricFamily := range metricFamilies {
+		if err := encoder.Encode(metricFamily); err != nil {
+			return fmt.Errorf("error encoding pool metrics: %v", err)
+		}
+	}
+
+	return os.WriteFile(filename, buf.Bytes(), os.FileMode(0o644))
+}
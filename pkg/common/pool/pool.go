@@ -0,0 +1,353 @@
+// Package pool keeps hibernated clusters warm so that test jobs can lease one instead of waiting
+// for a new cluster to be provisioned.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+// claimSettleTime is how long a claim waits for competing claims to land before checking whether it won.
+const claimSettleTime = 10 * time.Second
+
+// ErrPoolEmpty is returned by Acquire when no cluster is available.
+var ErrPoolEmpty = errors.New("no pooled cluster is available")
+
+// Key identifies a set of interchangeable clusters.
+type Key struct {
+	Version       string
+	CloudProvider string
+	Product       string
+}
+
+// KeyFromConfig returns the key of the clusters the current configuration would provision.
+func KeyFromConfig() Key {
+	return Key{
+		Version:       viper.GetString(config.Cluster.Version),
+		CloudProvider: viper.GetString(config.CloudProvider.CloudProviderID),
+		Product:       viper.GetString(config.Pool.Product),
+	}
+}
+
+// String returns the key as stored in the PoolKey cluster property.
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%s", k.Version, k.CloudProvider, k.Product)
+}
+
+// Pool keeps clusters for a single key.
+type Pool struct {
+	provider spi.Provider
+	key      Key
+	size     int
+	ttl      time.Duration
+	owner    string
+
+	// settle is how long claims wait to detect competing claims.
+	settle time.Duration
+
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+// New creates a pool of size clusters for key whose leases last ttl. Leases are taken on behalf of owner,
+// usually the job ID.
+func New(provider spi.Provider, key Key, size int, ttl time.Duration, owner string) *Pool {
+	return &Pool{
+		provider: provider,
+		key:      key,
+		size:     size,
+		ttl:      ttl,
+		owner:    owner,
+		settle:   claimSettleTime,
+	}
+}
+
+// NewFromConfig creates the pool for the current configuration.
+func NewFromConfig(provider spi.Provider) *Pool {
+	return New(provider, KeyFromConfig(), viper.GetInt(config.Pool.Size), viper.GetDuration(config.Pool.LeaseTTL), viper.GetString(config.JobID))
+}
+
+// Key returns the key of the pool's clusters.
+func (p *Pool) Key() Key {
+	return p.key
+}
+
+// HitRate returns the fraction of Acquire calls on this pool that leased a cluster.
+func (p *Pool) HitRate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hits+p.misses == 0 {
+		return 0
+	}
+	return float64(p.hits) / float64(p.hits+p.misses)
+}
+
+// Acquire leases an available cluster, resuming it if it is hibernating. It returns ErrPoolEmpty if
+// every cluster is leased or still provisioning.
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	members, err := p.members(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*spi.Cluster{}
+	for _, cluster := range members {
+		if p.classify(cluster, time.Now()) == stateAvailable {
+			candidates = append(candidates, cluster)
+		}
+	}
+
+	// Jobs starting together would otherwise all contend for the same cluster.
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	for _, candidate := range candidates {
+		lease, cluster, err := p.claim(ctx, candidate.ID())
+		if errors.Is(err, errClaimLost) {
+			log.Printf("Lost the claim on pooled cluster %s, trying another", candidate.ID())
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if cluster.State() == spi.ClusterStateHibernating {
+			if !p.provider.ResumeContext(ctx, cluster.ID()) {
+				log.Printf("Unable to resume pooled cluster %s, deleting it", cluster.ID())
+				if err := p.provider.DeleteClusterContext(ctx, cluster.ID()); err != nil {
+					log.Printf("Error deleting pooled cluster %s: %v", cluster.ID(), err)
+				}
+				continue
+			}
+			lease.Resumed = true
+		}
+
+		p.record(true)
+		log.Printf("Leased pooled cluster %s until %s", lease.ClusterID, lease.Expires().Format(time.RFC3339))
+		return lease, nil
+	}
+
+	p.record(false)
+	return nil, ErrPoolEmpty
+}
+
+func (p *Pool) record(hit bool) {
+	p.mu.Lock()
+	if hit {
+		p.hits++
+	} else {
+		p.misses++
+	}
+	p.mu.Unlock()
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	acquisitions.WithLabelValues(p.key.String(), result).Inc()
+}
+
+// Status summarizes the pool's clusters and what Reconcile did about them.
+type Status struct {
+	Key          string `json:"key"`
+	Available    int    `json:"available"`
+	Provisioning int    `json:"provisioning"`
+	Leased       int    `json:"leased"`
+	Launched     int    `json:"launched"`
+	Hibernated   int    `json:"hibernated"`
+	Extended     int    `json:"extended"`
+	Deleted      int    `json:"deleted"`
+}
+
+// Reconcile brings the pool to its size. It deletes clusters that errored or whose lease lapsed,
+// extends clusters that would expire before a lease could end, launches clusters to make up any
+// shortfall, deletes any surplus and hibernates the rest.
+//
+// Idle clusters are claimed like any other lease before they are deleted or hibernated, so that a
+// job can't lease a cluster out from under the reconciler.
+func (p *Pool) Reconcile(ctx context.Context) (*Status, error) {
+	members, err := p.members(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Key: p.key.String()}
+	capabilities := p.provider.Capabilities()
+	available := []*spi.Cluster{}
+	now := time.Now()
+
+	for _, cluster := range members {
+		state := p.classify(cluster, now)
+		switch state {
+		case stateLeased:
+			status.Leased++
+		case stateProvisioning:
+			status.Provisioning++
+		case stateAbandoned, stateBroken:
+			log.Printf("Deleting pooled cluster %s (%s)", cluster.ID(), cluster.State())
+			if err := p.provider.DeleteClusterContext(ctx, cluster.ID()); err != nil {
+				return status, fmt.Errorf("error deleting pooled cluster %s: %v", cluster.ID(), err)
+			}
+			status.Deleted++
+		case stateAvailable, stateExpiring:
+			if capabilities.ExtendExpiry && !cluster.ExpirationTimestamp().IsZero() && cluster.ExpirationTimestamp().Before(now.Add(2*p.ttl)) {
+				if err := p.provider.ExtendExpiryContext(ctx, cluster.ID(), 0, uint64((3 * p.ttl).Minutes()), 0); err != nil {
+					return status, fmt.Errorf("error extending pooled cluster %s: %v", cluster.ID(), err)
+				}
+				status.Extended++
+			} else if state == stateExpiring {
+				// It can't outlive a lease, so leave it to be cleaned up once it expires.
+				continue
+			}
+			available = append(available, cluster)
+		}
+	}
+
+	warm := len(available) + status.Provisioning
+	for ; warm < p.size; warm++ {
+		if err := p.launch(ctx); err != nil {
+			return status, err
+		}
+		status.Launched++
+		status.Provisioning++
+	}
+
+	// Delete the newest surplus clusters, so that those closest to expiry are used first.
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].CreationTimestamp().After(available[j].CreationTimestamp())
+	})
+	for _, cluster := range available {
+		if warm <= p.size && !(capabilities.Hibernation && cluster.State() == spi.ClusterStateReady) {
+			status.Available++
+			continue
+		}
+
+		lease, claimed, err := p.claim(ctx, cluster.ID())
+		if errors.Is(err, errClaimLost) {
+			status.Leased++
+			warm--
+			continue
+		} else if err != nil {
+			return status, err
+		}
+
+		if warm > p.size {
+			log.Printf("Deleting surplus pooled cluster %s", claimed.ID())
+			if err := p.provider.DeleteClusterContext(ctx, claimed.ID()); err != nil {
+				return status, fmt.Errorf("error deleting pooled cluster %s: %v", claimed.ID(), err)
+			}
+			status.Deleted++
+			warm--
+			continue
+		}
+
+		if claimed.State() == spi.ClusterStateReady {
+			if !p.provider.HibernateContext(ctx, claimed.ID()) {
+				return status, fmt.Errorf("unable to hibernate pooled cluster %s", claimed.ID())
+			}
+			status.Hibernated++
+		}
+		if err := lease.Release(ctx); err != nil {
+			return status, err
+		}
+		status.Available++
+	}
+
+	poolClusters.WithLabelValues(status.Key, "available").Set(float64(status.Available))
+	poolClusters.WithLabelValues(status.Key, "provisioning").Set(float64(status.Provisioning))
+	poolClusters.WithLabelValues(status.Key, "leased").Set(float64(status.Leased))
+
+	return status, nil
+}
+
+// launch provisions a cluster with the current configuration and adds it to the pool.
+func (p *Pool) launch(ctx context.Context) error {
+	clusterID, err := p.provider.LaunchClusterContext(ctx, "pool-"+util.RandomStr(5))
+	if err != nil {
+		return fmt.Errorf("error launching pooled cluster: %v", err)
+	}
+
+	cluster, err := p.provider.GetClusterContext(ctx, clusterID)
+	if err != nil {
+		return fmt.Errorf("error retrieving launched cluster %s: %v", clusterID, err)
+	}
+
+	if err := p.provider.AddPropertyContext(ctx, cluster, clusterproperties.PoolKey, p.key.String()); err != nil {
+		return fmt.Errorf("error adding launched cluster %s to the pool: %v", clusterID, err)
+	}
+
+	log.Printf("Launched pooled cluster %s", clusterID)
+	return nil
+}
+
+// members lists the clusters in the pool.
+func (p *Pool) members(ctx context.Context) ([]*spi.Cluster, error) {
+	clusters, err := p.provider.ListClustersContext(ctx, fmt.Sprintf("properties.%s='%s'", clusterproperties.PoolKey, p.key))
+	if err != nil {
+		return nil, fmt.Errorf("error listing pooled clusters: %v", err)
+	}
+	return clusters, nil
+}
+
+// memberState is what the pool can do with one of its clusters.
+type memberState int
+
+const (
+	// stateAvailable clusters can be leased.
+	stateAvailable memberState = iota
+
+	// stateExpiring clusters are idle but would expire before a lease could end.
+	stateExpiring
+
+	// stateProvisioning clusters are on their way to being available.
+	stateProvisioning
+
+	// stateLeased clusters are in use.
+	stateLeased
+
+	// stateAbandoned clusters were leased but the lease lapsed, so they may be in any condition.
+	stateAbandoned
+
+	// stateBroken clusters will never become available.
+	stateBroken
+
+	// stateUninstalling clusters are already on their way out.
+	stateUninstalling
+)
+
+func (p *Pool) classify(cluster *spi.Cluster, now time.Time) memberState {
+	if lease := cluster.Properties()[clusterproperties.PoolLease]; lease != "" {
+		if _, expires, err := parseLease(lease); err == nil && now.Before(expires) {
+			return stateLeased
+		}
+		return stateAbandoned
+	}
+
+	switch cluster.State() {
+	case spi.ClusterStateReady, spi.ClusterStateHibernating:
+		if expires := cluster.ExpirationTimestamp(); !expires.IsZero() && expires.Before(now.Add(p.ttl)) {
+			return stateExpiring
+		}
+		return stateAvailable
+	case spi.ClusterStateUninstalling:
+		return stateUninstalling
+	case spi.ClusterStateError, spi.ClusterStateUnknown:
+		return stateBroken
+	default:
+		return stateProvisioning
+	}
+}
@@ -0,0 +1,255 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/providers/mock"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+var testKey = Key{Version: "openshift-v4.11.12", CloudProvider: mock.MockCloudProvider, Product: mock.MockProduct}
+
+func newTestPool(t *testing.T, size int, capabilities ...spi.Capability) (*Pool, *mock.MockProvider) {
+	viper.Reset()
+	viper.Set(config.Cluster.Version, testKey.Version)
+
+	provider, err := mock.New()
+	if err != nil {
+		t.Fatalf("unable to create mock provider: %v", err)
+	}
+	if err := provider.SetScenario(&mock.MockScenario{Capabilities: capabilities}); err != nil {
+		t.Fatalf("unable to set scenario: %v", err)
+	}
+
+	pool := New(provider, testKey, size, time.Hour, "job")
+	pool.settle = 20 * time.Millisecond
+	return pool, provider
+}
+
+func reconcile(t *testing.T, pool *Pool) *Status {
+	status, err := pool.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error reconciling: %v", err)
+	}
+	return status
+}
+
+func TestReconcile(t *testing.T) {
+	pool, provider := newTestPool(t, 3, spi.HibernationCapability, spi.ExtendExpiryCapability)
+
+	steps := []struct {
+		Name     string
+		Expected Status
+	}{
+		{
+			Name:     "empty pool",
+			Expected: Status{Launched: 3, Provisioning: 3},
+		},
+		{
+			Name:     "launched clusters",
+			Expected: Status{Available: 3, Hibernated: 3, Extended: 3},
+		},
+		{
+			Name:     "steady state",
+			Expected: Status{Available: 3},
+		},
+	}
+
+	for _, step := range steps {
+		status := reconcile(t, pool)
+		step.Expected.Key = testKey.String()
+		if *status != step.Expected {
+			t.Errorf("%s: expected %+v, got %+v", step.Name, step.Expected, *status)
+		}
+	}
+
+	clusters, err := provider.ListClusters("")
+	if err != nil {
+		t.Fatalf("unexpected error listing clusters: %v", err)
+	}
+	for _, cluster := range clusters {
+		if cluster.State() != spi.ClusterStateHibernating || cluster.Properties()[clusterproperties.PoolLease] != "" {
+			t.Errorf("expected cluster %s to be hibernating and unleased, got %s and %q", cluster.ID(), cluster.State(), cluster.Properties()[clusterproperties.PoolLease])
+		}
+	}
+
+	pool.size = 1
+	if status := reconcile(t, pool); status.Deleted != 2 || status.Available != 1 {
+		t.Errorf("expected the surplus to be deleted, got %+v", *status)
+	}
+}
+
+func TestReconcileWithoutExtension(t *testing.T) {
+	// Mock clusters expire as soon as they are launched, so they can never be leased without extension.
+	pool, _ := newTestPool(t, 2)
+
+	reconcile(t, pool)
+	if status := reconcile(t, pool); status.Available != 0 || status.Launched != 2 {
+		t.Errorf("expected expiring clusters to be replaced, got %+v", *status)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	pool, provider := newTestPool(t, 2, spi.HibernationCapability, spi.ExtendExpiryCapability)
+
+	if _, err := pool.Acquire(context.Background()); !errors.Is(err, ErrPoolEmpty) {
+		t.Errorf("expected an empty pool, got %v", err)
+	}
+
+	reconcile(t, pool)
+	reconcile(t, pool)
+
+	lease, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error acquiring a cluster: %v", err)
+	}
+	cluster, err := provider.GetCluster(lease.ClusterID)
+	if err != nil {
+		t.Fatalf("unexpected error getting leased cluster: %v", err)
+	}
+	if cluster.State() != spi.ClusterStateResuming || !lease.Resumed {
+		t.Errorf("expected the leased cluster to be resuming, got %s", cluster.State())
+	}
+
+	if status := reconcile(t, pool); status.Leased != 1 || status.Available != 1 || status.Launched != 1 {
+		t.Errorf("expected the leased cluster to be replaced, got %+v", *status)
+	}
+
+	before := lease.Expires()
+	time.Sleep(time.Second)
+	if err := lease.Renew(context.Background()); err != nil {
+		t.Errorf("unexpected error renewing lease: %v", err)
+	} else if !lease.Expires().After(before) {
+		t.Errorf("expected renewal to extend the lease past %v, got %v", before, lease.Expires())
+	}
+
+	if err := lease.Release(context.Background()); err != nil {
+		t.Fatalf("unexpected error releasing lease: %v", err)
+	}
+	if err := lease.Renew(context.Background()); err == nil {
+		t.Errorf("expected renewing a released lease to fail")
+	}
+
+	if rate := pool.HitRate(); rate != 0.5 {
+		t.Errorf("expected a hit rate of 0.5, got %v", rate)
+	}
+}
+
+func TestAbandonedLease(t *testing.T) {
+	pool, provider := newTestPool(t, 1, spi.ExtendExpiryCapability)
+
+	reconcile(t, pool)
+	reconcile(t, pool)
+
+	lease, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error acquiring a cluster: %v", err)
+	}
+
+	cluster, _ := provider.GetCluster(lease.ClusterID)
+	if err := provider.AddProperty(cluster, clusterproperties.PoolLease, formatLease(lease.token, time.Now().Add(-time.Minute))); err != nil {
+		t.Fatalf("unexpected error backdating lease: %v", err)
+	}
+
+	if err := lease.Renew(context.Background()); err == nil {
+		t.Errorf("expected renewing a lapsed lease to fail")
+	}
+	if status := reconcile(t, pool); status.Deleted != 1 || status.Launched != 1 {
+		t.Errorf("expected the abandoned cluster to be replaced, got %+v", *status)
+	}
+	if _, err := provider.GetCluster(lease.ClusterID); err == nil {
+		t.Errorf("expected the abandoned cluster to be deleted")
+	}
+}
+
+func TestEndLease(t *testing.T) {
+	pool, provider := newTestPool(t, 1, spi.ExtendExpiryCapability)
+
+	reconcile(t, pool)
+	reconcile(t, pool)
+
+	lease, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error acquiring a cluster: %v", err)
+	}
+	if err := lease.End(context.Background()); err != nil {
+		t.Fatalf("unexpected error ending lease: %v", err)
+	}
+
+	if err := lease.Renew(context.Background()); err == nil {
+		t.Errorf("expected renewing an ended lease to fail")
+	}
+	if _, err := pool.Acquire(context.Background()); !errors.Is(err, ErrPoolEmpty) {
+		t.Errorf("expected a used cluster not to be leased again, got %v", err)
+	}
+	if status := reconcile(t, pool); status.Deleted != 1 || status.Launched != 1 {
+		t.Errorf("expected the used cluster to be replaced, got %+v", *status)
+	}
+	if _, err := provider.GetCluster(lease.ClusterID); err == nil {
+		t.Errorf("expected the used cluster to be deleted")
+	}
+}
+
+func TestConcurrentAcquire(t *testing.T) {
+	pool, _ := newTestPool(t, 3, spi.ExtendExpiryCapability)
+
+	reconcile(t, pool)
+	reconcile(t, pool)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		leased = map[string]int{}
+		empty  int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			lease, err := pool.Acquire(context.Background())
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrPoolEmpty) {
+				empty++
+			} else if err != nil {
+				t.Errorf("unexpected error acquiring a cluster: %v", err)
+			} else {
+				leased[lease.ClusterID]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	for clusterID, count := range leased {
+		if count > 1 {
+			t.Errorf("cluster %s was leased %d times", clusterID, count)
+		}
+	}
+	if len(leased)+empty != 10 || len(leased) == 0 {
+		t.Errorf("expected every caller to lease a distinct cluster or find the pool empty, got %d leased and %d empty", len(leased), empty)
+	}
+}
+
+func TestParseLease(t *testing.T) {
+	expires := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	token, parsed, err := parseLease(formatLease("job@1-abc", expires))
+	if err != nil || token != "job@1-abc" || !parsed.Equal(expires) {
+		t.Errorf("unexpected lease: %q, %v, %v", token, parsed, err)
+	}
+
+	for _, lease := range []string{"", "job", "job@tomorrow"} {
+		if _, _, err := parseLease(lease); err == nil {
+			t.Errorf("expected an error parsing %q", lease)
+		}
+	}
+}
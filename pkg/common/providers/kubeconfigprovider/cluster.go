@@ -0,0 +1,382 @@
+package kubeconfigprovider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
+)
+
+const (
+	// OpenShiftProduct is the product reported for clusters that serve a ClusterVersion.
+	OpenShiftProduct = "openshift"
+
+	// KubernetesProduct is the product reported for any other Kubernetes cluster.
+	KubernetesProduct = "kubernetes"
+)
+
+func unsupported(operation string) error {
+	return fmt.Errorf("%s is not supported by the %s provider", operation, ProviderName)
+}
+
+// describeCluster builds an spi.Cluster from the objects served by the cluster itself.
+//
+// OpenShift clusters are described from their ClusterVersion and Infrastructure objects. Other
+// clusters fall back to the kube-system namespace UID, the server version and node metadata.
+func (k *KubeconfigProvider) describeCluster(ctx context.Context) (*spi.Cluster, error) {
+	nodes, err := k.kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+
+	builder := spi.NewClusterBuilder().
+		State(spi.ClusterStateReady).
+		CloudProvider(nodeCloudProvider(nodes.Items)).
+		Region(nodeRegion(nodes.Items)).
+		NumComputeNodes(countComputeNodes(nodes.Items))
+
+	clusterVersion, err := k.cfg.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return k.describeOpenShift(ctx, builder, clusterVersion)
+	case apierrors.IsNotFound(err):
+		return k.describeKubernetes(ctx, builder)
+	default:
+		return nil, fmt.Errorf("error getting cluster version object: %v", err)
+	}
+}
+
+func (k *KubeconfigProvider) describeOpenShift(ctx context.Context, builder *spi.ClusterBuilder, clusterVersion *configv1.ClusterVersion) (*spi.Cluster, error) {
+	infra, err := k.cfg.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting infrastructure object: %v", err)
+	}
+
+	version := clusterVersion.Status.Desired.Version
+	if parsed, err := semver.NewVersion(version); err == nil {
+		version = util.SemverToOpenshiftVersion(parsed)
+	}
+
+	builder.ID(string(clusterVersion.Spec.ClusterID)).
+		Name(infra.Status.InfrastructureName).
+		Version(version).
+		Product(OpenShiftProduct).
+		CreationTimestamp(clusterVersion.CreationTimestamp.Time)
+
+	if platformStatus := infra.Status.PlatformStatus; platformStatus != nil {
+		if platformStatus.Type != "" && platformStatus.Type != configv1.NonePlatformType {
+			builder.CloudProvider(strings.ToLower(string(platformStatus.Type)))
+		}
+		if region := platformRegion(platformStatus); region != "" {
+			builder.Region(region)
+		}
+	}
+
+	return builder.Build(), nil
+}
+
+func (k *KubeconfigProvider) describeKubernetes(ctx context.Context, builder *spi.ClusterBuilder) (*spi.Cluster, error) {
+	kubeSystem, err := k.kube.CoreV1().Namespaces().Get(ctx, metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s namespace: %v", metav1.NamespaceSystem, err)
+	}
+
+	serverVersion, err := k.kube.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting server version: %v", err)
+	}
+
+	return builder.ID(string(kubeSystem.UID)).
+		Name(k.env).
+		Version(strings.TrimPrefix(serverVersion.GitVersion, "v")).
+		Product(KubernetesProduct).
+		CreationTimestamp(kubeSystem.CreationTimestamp.Time).
+		Build(), nil
+}
+
+// platformRegion returns the region recorded in the Infrastructure platform status, if any.
+func platformRegion(platformStatus *configv1.PlatformStatus) string {
+	switch {
+	case platformStatus.AWS != nil:
+		return platformStatus.AWS.Region
+	case platformStatus.GCP != nil:
+		return platformStatus.GCP.Region
+	case platformStatus.IBMCloud != nil:
+		return platformStatus.IBMCloud.Location
+	case platformStatus.PowerVS != nil:
+		return platformStatus.PowerVS.Region
+	default:
+		return ""
+	}
+}
+
+// nodeCloudProvider returns the scheme of the first node provider ID, e.g. "aws" for "aws:///us-east-1a/i-0123".
+func nodeCloudProvider(nodes []corev1.Node) string {
+	for _, node := range nodes {
+		if scheme, _, found := strings.Cut(node.Spec.ProviderID, "://"); found && scheme != "" {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// nodeRegion returns the first well-known region label found on the nodes.
+func nodeRegion(nodes []corev1.Node) string {
+	for _, node := range nodes {
+		if region := node.Labels[corev1.LabelTopologyRegion]; region != "" {
+			return region
+		}
+	}
+	return ""
+}
+
+// countComputeNodes counts nodes that are not labeled as part of the control plane.
+func countComputeNodes(nodes []corev1.Node) int {
+	count := 0
+	for _, node := range nodes {
+		_, master := node.Labels["node-role.kubernetes.io/master"]
+		_, controlPlane := node.Labels["node-role.kubernetes.io/control-plane"]
+		if !master && !controlPlane {
+			count++
+		}
+	}
+	return count
+}
+
+// IsValidClusterNameContext accepts any name, since no cluster is ever created.
+func (k *KubeconfigProvider) IsValidClusterNameContext(ctx context.Context, clusterName string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LaunchClusterContext attaches to the existing cluster and returns its ID.
+func (k *KubeconfigProvider) LaunchClusterContext(ctx context.Context, clusterName string) (string, error) {
+	cluster, err := k.describeCluster(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Using existing cluster %s from the kubeconfig instead of launching %s", cluster.ID(), clusterName)
+	return cluster.ID(), nil
+}
+
+// DeleteClusterContext is unsupported: the cluster is not owned by osde2e.
+func (k *KubeconfigProvider) DeleteClusterContext(ctx context.Context, clusterID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("DeleteCluster")
+}
+
+// ScaleClusterContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) ScaleClusterContext(ctx context.Context, clusterID string, numComputeNodes int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("ScaleCluster")
+}
+
+// ListClustersContext returns the single cluster behind the kubeconfig if it matches the query.
+func (k *KubeconfigProvider) ListClustersContext(ctx context.Context, query string) ([]*spi.Cluster, error) {
+	clusterQuery, err := spi.ParseClusterQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := k.describeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterQuery.Filter([]*spi.Cluster{cluster}), nil
+}
+
+// GetClusterContext returns the cluster behind the kubeconfig.
+// An empty clusterID matches it; any other ID must be the one reported by the cluster.
+func (k *KubeconfigProvider) GetClusterContext(ctx context.Context, clusterID string) (*spi.Cluster, error) {
+	cluster, err := k.describeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if clusterID != "" && clusterID != cluster.ID() {
+		return nil, fmt.Errorf("cluster %s not found: the kubeconfig points at cluster %s", clusterID, cluster.ID())
+	}
+
+	return cluster, nil
+}
+
+// ClusterKubeconfigContext returns the configured kubeconfig.
+func (k *KubeconfigProvider) ClusterKubeconfigContext(ctx context.Context, clusterID string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return k.kubeconfig, nil
+}
+
+// CheckQuotaContext always passes, since no cluster is provisioned.
+func (k *KubeconfigProvider) CheckQuotaContext(ctx context.Context, sku string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// InstallAddonsContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) InstallAddonsContext(ctx context.Context, clusterID string, addonIDs []spi.AddOnID, params map[spi.AddOnID]spi.AddOnParams) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, unsupported("InstallAddons")
+}
+
+// VersionsContext returns the cluster's current version as the only, default, version.
+func (k *KubeconfigProvider) VersionsContext(ctx context.Context) (*spi.VersionList, error) {
+	cluster, err := k.describeCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := util.OpenshiftVersionToSemver(cluster.Version())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cluster version %q: %v", cluster.Version(), err)
+	}
+
+	return spi.NewVersionListBuilder().
+		AvailableVersions([]*spi.Version{
+			spi.NewVersionBuilder().
+				Version(version).
+				Default(true).
+				Build(),
+		}).
+		Build(), nil
+}
+
+// LogsContext returns no logs, since there is no provider backend to collect them from.
+func (k *KubeconfigProvider) LogsContext(ctx context.Context, clusterID string) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{}, nil
+}
+
+// ExtendExpiryContext is unsupported: existing clusters do not expire.
+func (k *KubeconfigProvider) ExtendExpiryContext(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("ExtendExpiry")
+}
+
+// ExpireContext is unsupported: existing clusters do not expire.
+func (k *KubeconfigProvider) ExpireContext(ctx context.Context, clusterID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("Expire")
+}
+
+// AddPropertyContext is unsupported: there is nowhere to store cluster properties.
+func (k *KubeconfigProvider) AddPropertyContext(ctx context.Context, cluster *spi.Cluster, tag string, value string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("AddProperty")
+}
+
+// UpgradeContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) UpgradeContext(ctx context.Context, clusterID string, version string, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("Upgrade")
+}
+
+// GetUpgradePolicyIDContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) GetUpgradePolicyIDContext(ctx context.Context, clusterID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return "", unsupported("GetUpgradePolicyID")
+}
+
+// UpdateScheduleContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) UpdateScheduleContext(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("UpdateSchedule")
+}
+
+// DetermineMachineTypeContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) DetermineMachineTypeContext(ctx context.Context, cloudProvider string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return "", unsupported("DetermineMachineType")
+}
+
+// HibernateContext is unsupported and always returns false.
+func (k *KubeconfigProvider) HibernateContext(ctx context.Context, id string) bool {
+	log.Printf("Hibernation not supported in the %s provider", ProviderName)
+	return false
+}
+
+// ResumeContext is unsupported and always returns false.
+func (k *KubeconfigProvider) ResumeContext(ctx context.Context, id string) bool {
+	log.Printf("Hibernation not supported in the %s provider", ProviderName)
+	return false
+}
+
+// AddClusterProxyContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) AddClusterProxyContext(ctx context.Context, clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("AddClusterProxy")
+}
+
+// RemoveClusterProxyContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) RemoveClusterProxyContext(ctx context.Context, clusterId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("RemoveClusterProxy")
+}
+
+// RemoveUserCABundleContext is unsupported for existing clusters.
+func (k *KubeconfigProvider) RemoveUserCABundleContext(ctx context.Context, clusterId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return unsupported("RemoveUserCABundle")
+}
@@ -0,0 +1,171 @@
+package kubeconfigprovider
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeConfig "github.com/openshift/client-go/config/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func node(name, providerID string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+	}
+}
+
+func openShiftObjects() []runtime.Object {
+	return []runtime.Object{
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: "1a2b3c"},
+			Status: configv1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.11.12"},
+			},
+		},
+		&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				InfrastructureName: "osde2e-abcde",
+				PlatformStatus: &configv1.PlatformStatus{
+					Type: configv1.AWSPlatformType,
+					AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+				},
+			},
+		},
+	}
+}
+
+func TestDescribeCluster(t *testing.T) {
+	tests := []struct {
+		Name            string
+		KubeObjects     []runtime.Object
+		ConfigObjects   []runtime.Object
+		ServerVersion   string
+		ExpectedID      string
+		ExpectedName    string
+		ExpectedVersion string
+		ExpectedCloud   string
+		ExpectedRegion  string
+		ExpectedProduct string
+		ExpectedCompute int
+	}{
+		{
+			Name: "openshift cluster",
+			KubeObjects: []runtime.Object{
+				node("master-0", "aws:///us-east-1a/i-0", map[string]string{"node-role.kubernetes.io/master": ""}),
+				node("worker-0", "aws:///us-east-1a/i-1", map[string]string{"node-role.kubernetes.io/worker": ""}),
+				node("worker-1", "aws:///us-east-1b/i-2", map[string]string{"node-role.kubernetes.io/worker": ""}),
+			},
+			ConfigObjects:   openShiftObjects(),
+			ExpectedID:      "1a2b3c",
+			ExpectedName:    "osde2e-abcde",
+			ExpectedVersion: "openshift-v4.11.12",
+			ExpectedCloud:   "aws",
+			ExpectedRegion:  "us-east-1",
+			ExpectedProduct: OpenShiftProduct,
+			ExpectedCompute: 2,
+		},
+		{
+			Name: "kubernetes cluster",
+			KubeObjects: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem, UID: types.UID("kube-system-uid")}},
+				node("kind-control-plane", "kind://docker/kind/kind-control-plane", map[string]string{
+					"node-role.kubernetes.io/control-plane": "",
+					corev1.LabelTopologyRegion:              "local",
+				}),
+				node("kind-worker", "kind://docker/kind/kind-worker", nil),
+			},
+			ServerVersion:   "v1.25.3",
+			ExpectedID:      "kube-system-uid",
+			ExpectedName:    "kind-kind",
+			ExpectedVersion: "1.25.3",
+			ExpectedCloud:   "kind",
+			ExpectedRegion:  "local",
+			ExpectedProduct: KubernetesProduct,
+			ExpectedCompute: 1,
+		},
+	}
+
+	for _, test := range tests {
+		kube := kubernetes.NewSimpleClientset(test.KubeObjects...)
+		kube.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: test.ServerVersion}
+		provider := newWithClients([]byte("kubeconfig"), "kind-kind", kube, fakeConfig.NewSimpleClientset(test.ConfigObjects...))
+
+		cluster, err := provider.GetClusterContext(context.Background(), "")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.Name, err)
+			continue
+		}
+
+		if cluster.ID() != test.ExpectedID {
+			t.Errorf("%s: expected ID %s, got %s", test.Name, test.ExpectedID, cluster.ID())
+		}
+		if cluster.Name() != test.ExpectedName {
+			t.Errorf("%s: expected name %s, got %s", test.Name, test.ExpectedName, cluster.Name())
+		}
+		if cluster.Version() != test.ExpectedVersion {
+			t.Errorf("%s: expected version %s, got %s", test.Name, test.ExpectedVersion, cluster.Version())
+		}
+		if cluster.CloudProvider() != test.ExpectedCloud {
+			t.Errorf("%s: expected cloud provider %s, got %s", test.Name, test.ExpectedCloud, cluster.CloudProvider())
+		}
+		if cluster.Region() != test.ExpectedRegion {
+			t.Errorf("%s: expected region %s, got %s", test.Name, test.ExpectedRegion, cluster.Region())
+		}
+		if cluster.Product() != test.ExpectedProduct {
+			t.Errorf("%s: expected product %s, got %s", test.Name, test.ExpectedProduct, cluster.Product())
+		}
+		if cluster.NumComputeNodes() != test.ExpectedCompute {
+			t.Errorf("%s: expected %d compute nodes, got %d", test.Name, test.ExpectedCompute, cluster.NumComputeNodes())
+		}
+
+		queries := []struct {
+			Query    string
+			Expected int
+		}{
+			{Query: "", Expected: 1},
+			{Query: "cloud_provider.id = '" + test.ExpectedCloud + "'", Expected: 1},
+			{Query: "properties.MadeByOSDe2e='true'", Expected: 0},
+		}
+		for _, query := range queries {
+			clusters, err := provider.ListClustersContext(context.Background(), query.Query)
+			if err != nil {
+				t.Errorf("%s: unexpected error listing clusters with %q: %v", test.Name, query.Query, err)
+			} else if len(clusters) != query.Expected {
+				t.Errorf("%s: expected %d clusters for %q, got %d", test.Name, query.Expected, query.Query, len(clusters))
+			}
+		}
+
+		versions, err := provider.VersionsContext(context.Background())
+		if err != nil {
+			t.Errorf("%s: unexpected error getting versions: %v", test.Name, err)
+		} else if len(versions.AvailableVersions()) != 1 || versions.Default() == nil {
+			t.Errorf("%s: expected a single default version, got %v", test.Name, versions.AvailableVersions())
+		}
+	}
+}
+
+func TestGetClusterWrongID(t *testing.T) {
+	provider := newWithClients([]byte("kubeconfig"), "test", kubernetes.NewSimpleClientset(), fakeConfig.NewSimpleClientset(openShiftObjects()...))
+
+	if _, err := provider.GetClusterContext(context.Background(), "someone-else"); err == nil {
+		t.Errorf("expected an error getting a cluster the kubeconfig does not point at")
+	}
+
+	clusterID, err := provider.LaunchClusterContext(context.Background(), "osde2e-new")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+	if clusterID != "1a2b3c" {
+		t.Errorf("expected LaunchCluster to return the existing cluster ID, got %s", clusterID)
+	}
+}
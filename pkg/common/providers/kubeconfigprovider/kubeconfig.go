@@ -0,0 +1,124 @@
+// Package kubeconfigprovider will allow running osde2e against an existing cluster reachable through a kubeconfig.
+package kubeconfigprovider
+
+import (
+	"fmt"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+const (
+	// ProviderName is the name the kubeconfig provider is registered under.
+	ProviderName = "kubeconfig"
+
+	// DefaultEnvironment is reported when the kubeconfig has no current context.
+	DefaultEnvironment = "kubeconfig"
+)
+
+func init() {
+	spi.RegisterProvider(ProviderName, func() (spi.Provider, error) { return New() })
+}
+
+// KubeconfigProvider describes the cluster behind TEST_KUBECONFIG.
+//
+// It never provisions or deletes anything: LaunchCluster attaches to the existing cluster and
+// every cluster-lifecycle operation is reported as unsupported through Capabilities.
+type KubeconfigProvider struct {
+	spi.Background
+
+	kubeconfig []byte
+	env        string
+	kube       kubernetes.Interface
+	cfg        configclient.Interface
+}
+
+// New creates a new KubeconfigProvider from the configured kubeconfig.
+func New() (*KubeconfigProvider, error) {
+	if err := config.LoadKubeconfig(); err != nil {
+		return nil, err
+	}
+
+	kubeconfig := []byte(viper.GetString(config.Kubeconfig.Contents))
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("the %s provider requires TEST_KUBECONFIG to be set", ProviderName)
+	}
+
+	rawConfig, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kubeconfig: %v", err)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building rest config from kubeconfig: %v", err)
+	}
+
+	kube, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating Kube Clientset: %v", err)
+	}
+
+	cfg, err := configclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating OpenShift Clientset: %v", err)
+	}
+
+	env := rawConfig.CurrentContext
+	if env == "" {
+		env = DefaultEnvironment
+	}
+
+	return newWithClients(kubeconfig, env, kube, cfg), nil
+}
+
+func newWithClients(kubeconfig []byte, env string, kube kubernetes.Interface, cfg configclient.Interface) *KubeconfigProvider {
+	k := &KubeconfigProvider{
+		kubeconfig: kubeconfig,
+		env:        env,
+		kube:       kube,
+		cfg:        cfg,
+	}
+	k.Background = spi.NewBackground(k)
+	return k
+}
+
+// Type returns the provisioner type: kubeconfig
+func (k *KubeconfigProvider) Type() string {
+	return ProviderName
+}
+
+// Capabilities reports that none of the optional features are available for an existing cluster.
+func (k *KubeconfigProvider) Capabilities() spi.Capabilities {
+	return spi.Capabilities{}
+}
+
+// Environment returns the name of the kubeconfig's current context.
+func (k *KubeconfigProvider) Environment() string {
+	return k.env
+}
+
+// Metrics is a stub function for now
+func (k *KubeconfigProvider) Metrics(clusterID string) (bool, error) {
+	return true, nil
+}
+
+// UpgradeSource returns the upgrade source, which is unused for existing clusters.
+func (k *KubeconfigProvider) UpgradeSource() spi.UpgradeSource {
+	return spi.CincinnatiSource
+}
+
+// CincinnatiChannel returns the Cincinnati channel, which is unused for existing clusters.
+func (k *KubeconfigProvider) CincinnatiChannel() spi.CincinnatiChannel {
+	return spi.CincinnatiStableChannel
+}
+
+// LoadUserCaBundleData is unsupported for existing clusters.
+func (k *KubeconfigProvider) LoadUserCaBundleData(file string) (string, error) {
+	return "", fmt.Errorf("proxies are not supported by the %s provider", ProviderName)
+}
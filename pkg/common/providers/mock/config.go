@@ -1,6 +1,23 @@
 package mock
 
+import (
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+)
+
 const (
 	// Env is the mock environment.
 	Env = "mock.env"
+
+	// Scenario is the name of a scenario bundled under assets/providers/mock/scenarios, or the path to
+	// a scenario file, that scripts the behaviour of the mock provider.
+	Scenario = "mock.scenario"
+
+	// StateDir is a directory the mock provider keeps its clusters in, so that they are shared between
+	// osde2e invocations. Clusters only live as long as the process if unset.
+	StateDir = "mock.stateDir"
 )
+
+func init() {
+	viper.BindEnv(Scenario, "MOCK_SCENARIO")
+	viper.BindEnv(StateDir, "MOCK_STATE_DIR")
+}
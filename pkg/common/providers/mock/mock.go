@@ -1,20 +1,25 @@
 package mock
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/google/uuid"
 
 	"github.com/openshift/osde2e/assets"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/spi"
+	"github.com/openshift/osde2e/pkg/common/util"
 )
 
 const (
@@ -28,11 +33,28 @@ const (
 	MockProduct = "mock-product"
 )
 
+var errNotFound = errors.New("couldn't find cluster in mock provider")
+
 // MockProvider for unit testing.
 type MockProvider struct {
-	env      string
-	clusters map[string]*spi.Cluster
-	versions *spi.VersionList
+	spi.Background
+
+	env          string
+	clusters     clusterStore
+	versions     *spi.VersionList
+	capabilities spi.Capabilities
+	script       *scriptRunner
+
+	// mu guards upgradePolicies.
+	mu              sync.Mutex
+	upgradePolicies map[string]*upgradePolicy
+}
+
+// upgradePolicy is an upgrade scheduled with the mock provider.
+type upgradePolicy struct {
+	id      string
+	version string
+	nextRun time.Time
 }
 
 func init() {
@@ -63,15 +85,134 @@ func New() (*MockProvider, error) {
 		DefaultVersionOverride(nil).
 		Build()
 
-	return &MockProvider{
-		env:      env,
-		clusters: map[string]*spi.Cluster{},
-		versions: versionList,
-	}, nil
+	var clusters clusterStore = newMemoryStore()
+	if stateDir := viper.GetString(StateDir); stateDir != "" {
+		fileStore, err := newFileStore(stateDir)
+		if err != nil {
+			return nil, err
+		}
+		clusters = fileStore
+	}
+
+	provider := &MockProvider{
+		env:             env,
+		clusters:        clusters,
+		versions:        versionList,
+		script:          newScriptRunner(nil),
+		upgradePolicies: map[string]*upgradePolicy{},
+	}
+	provider.Background = spi.NewBackground(provider)
+
+	if scenarioName := viper.GetString(Scenario); scenarioName != "" {
+		scenario, err := LoadScenario(scenarioName)
+		if err != nil {
+			return nil, err
+		}
+		if err := provider.SetScenario(scenario); err != nil {
+			return nil, err
+		}
+	}
+
+	return provider, nil
+}
+
+// SetScenario scripts the provider's behaviour. The scenario's versions replace the provider's
+// versions and its clusters are added unless clusters with the same IDs already exist.
+func (m *MockProvider) SetScenario(scenario *MockScenario) error {
+	m.script = newScriptRunner(scenario)
+	m.capabilities = scenario.capabilities()
+
+	if len(scenario.Versions) > 0 {
+		m.versions = scenario.versionList()
+	}
+
+	for _, cluster := range scenario.Clusters {
+		if existing, err := m.clusters.get(cluster.ID); err != nil {
+			return err
+		} else if existing != nil {
+			continue
+		}
+
+		state := cluster.State
+		if state == "" {
+			state = spi.ClusterStateReady
+		}
+
+		builder := newClusterBuilder(cluster.ID, cluster.Name, cluster.Version, state).
+			Properties(cluster.Properties)
+		if cluster.ExpiresIn != 0 {
+			builder.ExpirationTimestamp(time.Now().Add(cluster.ExpiresIn))
+		}
+
+		if err := m.clusters.put(builder.Build()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newClusterBuilder starts a mock cluster.
+func newClusterBuilder(id, name, version string, state spi.ClusterState) *spi.ClusterBuilder {
+	return spi.NewClusterBuilder().
+		ID(id).
+		Name(name).
+		Version(version).
+		State(state).
+		CloudProvider(MockCloudProvider).
+		Product(MockProduct).
+		Region(MockRegion).
+		CreationTimestamp(time.Now().Add(-2 * time.Hour)).
+		ExpirationTimestamp(time.Now()).
+		Flavour("osd-4")
+}
+
+// updateCluster rebuilds a stored cluster with the changes made by fn. Clusters are immutable, so
+// this is how they are changed.
+func (m *MockProvider) updateCluster(id string, fn func(*spi.ClusterBuilder)) (*spi.Cluster, error) {
+	cluster, err := m.clusters.update(id, func(cluster *spi.Cluster) *spi.Cluster {
+		builder := copyCluster(cluster)
+		fn(builder)
+		return builder.Build()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, errNotFound
+	}
+	return cluster, nil
+}
+
+// copyCluster starts a builder with everything from an existing cluster.
+func copyCluster(cluster *spi.Cluster) *spi.ClusterBuilder {
+	properties := map[string]string{}
+	for key, value := range cluster.Properties() {
+		properties[key] = value
+	}
+
+	return spi.NewClusterBuilder().
+		ID(cluster.ID()).
+		Name(cluster.Name()).
+		Version(cluster.Version()).
+		State(cluster.State()).
+		CloudProvider(cluster.CloudProvider()).
+		Product(cluster.Product()).
+		Region(cluster.Region()).
+		CreationTimestamp(cluster.CreationTimestamp()).
+		ExpirationTimestamp(cluster.ExpirationTimestamp()).
+		Flavour(cluster.Flavour()).
+		Addons(cluster.Addons()).
+		NumComputeNodes(cluster.NumComputeNodes()).
+		Properties(properties)
 }
 
-// IsValidClusterName mocks a validation of cluster name
-func (m *MockProvider) IsValidClusterName(clusterName string) (bool, error) {
+// IsValidClusterNameContext mocks a validation of cluster name
+func (m *MockProvider) IsValidClusterNameContext(ctx context.Context, clusterName string) (bool, error) {
+	if err := m.script.run(ctx, "IsValidClusterName"); err != nil {
+		return false, err
+	}
+
 	if m.env == "fail" {
 		switch clusterName {
 		case "error":
@@ -83,63 +224,184 @@ func (m *MockProvider) IsValidClusterName(clusterName string) (bool, error) {
 	return true, nil
 }
 
-// LaunchCluster mocks a launch cluster operation.
-func (m *MockProvider) LaunchCluster(clusterName string) (string, error) {
+// LaunchClusterContext mocks a launch cluster operation.
+func (m *MockProvider) LaunchClusterContext(ctx context.Context, clusterName string) (string, error) {
+	if err := m.script.run(ctx, "LaunchCluster"); err != nil {
+		return "", err
+	}
+
+	if m.capabilities.Recycling && viper.GetBool(config.Cluster.UseExistingCluster) && viper.GetString(config.Addons.IDs) == "" {
+		clusterID, err := m.recycleCluster(viper.GetString(config.Cluster.Version))
+		if err != nil {
+			return "", err
+		}
+		if clusterID != "" {
+			return clusterID, nil
+		}
+	}
+
 	clusterID := uuid.New().String()
 	if m.env == "fail" {
 		clusterID = m.env
 	}
 
-	m.clusters[clusterID] = spi.NewClusterBuilder().
-		ID(clusterID).
-		Name(clusterName).
-		Version(viper.GetString(config.Cluster.Version)).
-		State(spi.ClusterStateReady).
-		CloudProvider(MockCloudProvider).
-		Product(MockProduct).
-		Region(MockRegion).
-		CreationTimestamp(time.Now().Add(-2 * time.Hour)).
-		ExpirationTimestamp(time.Now()).
-		Flavour("osd-4").
+	state := m.script.scenario.InitialState
+	if state == "" {
+		state = spi.ClusterStateReady
+	}
+
+	cluster := newClusterBuilder(clusterID, clusterName, viper.GetString(config.Cluster.Version), state).
+		Properties(map[string]string{
+			clusterproperties.MadeByOSDe2e:     "true",
+			clusterproperties.InstalledVersion: viper.GetString(config.Cluster.Version),
+			clusterproperties.Status:           clusterproperties.StatusProvisioning,
+		}).
 		Build()
+	if err := m.clusters.put(cluster); err != nil {
+		return "", err
+	}
 
 	return clusterID, nil
 }
 
-// DeleteCluster mocks a delete cluster operation.
-func (m *MockProvider) DeleteCluster(clusterID string) error {
+// recycleCluster claims a finished cluster of the given version for this job, as the OCM provider does
+// when existing clusters may be used. Clusters that expire within 4 hours are expired instead of
+// claimed and hibernating clusters are resumed. It returns "" if no cluster can be reused.
+func (m *MockProvider) recycleCluster(version string) (string, error) {
+	query := fmt.Sprintf("properties.JobName='' and properties.JobID='' and properties.Status like '%s%%' and version.id='%s'",
+		"completed-", version)
+
+	clusters, err := m.clusters.list()
+	if err != nil {
+		return "", err
+	}
+	candidates, err := spi.FilterClusters(query, clusters)
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.ExpirationTimestamp().Before(time.Now().Add(4 * time.Hour)) {
+			if _, err := m.updateCluster(candidate.ID(), func(builder *spi.ClusterBuilder) {
+				properties := builder.Build().Properties()
+				properties[clusterproperties.JobName] = "expiring"
+				builder.Properties(properties).ExpirationTimestamp(time.Now())
+			}); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		// Claim the cluster only if no one else has in the meantime.
+		claimed := false
+		if _, err := m.updateCluster(candidate.ID(), func(builder *spi.ClusterBuilder) {
+			cluster := builder.Build()
+			properties := cluster.Properties()
+			if properties[clusterproperties.JobID] != "" {
+				return
+			}
+
+			switch {
+			case cluster.State() == spi.ClusterStateReady:
+				clusterproperties.Set(properties, clusterproperties.Status, clusterproperties.StatusHealthy, time.Now())
+			case cluster.State() == spi.ClusterStateHibernating && m.capabilities.Hibernation:
+				clusterproperties.Set(properties, clusterproperties.Status, clusterproperties.StatusResuming, time.Now())
+				builder.State(spi.ClusterStateResuming)
+			default:
+				return
+			}
+			clusterproperties.Set(properties, clusterproperties.JobID, viper.GetString(config.JobID), time.Now())
+			properties[clusterproperties.JobName] = viper.GetString(config.JobName)
+			builder.Properties(properties)
+			claimed = true
+		}); err != nil {
+			return "", err
+		}
+
+		if claimed {
+			log.Printf("Recycling mock cluster %s", candidate.ID())
+			viper.Set(config.Cluster.Reused, true)
+			return candidate.ID(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// DeleteClusterContext mocks a delete cluster operation.
+func (m *MockProvider) DeleteClusterContext(ctx context.Context, clusterID string) error {
+	if err := m.script.run(ctx, "DeleteCluster"); err != nil {
+		return err
+	}
+
 	if clusterID == "fail" {
 		return fmt.Errorf("fake error deleting cluster")
 	}
 
-	delete(m.clusters, clusterID)
-	return nil
+	return m.clusters.delete(clusterID)
 }
 
-// ListClusters mocks a list cluster operation.
-func (m *MockProvider) ListClusters(query string) ([]*spi.Cluster, error) {
-	return nil, nil
+// ListClustersContext mocks a list cluster operation, returning the stored clusters that match the query.
+func (m *MockProvider) ListClustersContext(ctx context.Context, query string) ([]*spi.Cluster, error) {
+	if err := m.script.run(ctx, "ListClusters"); err != nil {
+		return nil, err
+	}
+
+	clusterQuery, err := spi.ParseClusterQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := m.clusters.list()
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterQuery.Filter(clusters), nil
 }
 
-// GetCluster mocks a get cluster operation.
-func (m *MockProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
+// GetClusterContext mocks a get cluster operation.
+func (m *MockProvider) GetClusterContext(ctx context.Context, clusterID string) (*spi.Cluster, error) {
+	if err := m.script.run(ctx, "GetCluster"); err != nil {
+		return nil, err
+	}
+
 	if clusterID == "fail" {
 		return nil, fmt.Errorf("failed to get versions: Some fake error")
 	}
 
-	if cluster, ok := m.clusters[clusterID]; ok {
-		return cluster, nil
+	cluster, err := m.clusters.get(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, errNotFound
 	}
-	return nil, fmt.Errorf("couldn't find cluster in mock provider")
+
+	if next, ok := m.script.observe(clusterID, cluster.State()); ok {
+		return m.updateCluster(clusterID, func(builder *spi.ClusterBuilder) {
+			builder.State(next)
+		})
+	}
+
+	return cluster, nil
 }
 
-// ScaleCluster mocks a scale cluster operation.
-func (m *MockProvider) ScaleCluster(clusterID string, numComputeNodes int) error {
+// ScaleClusterContext mocks a scale cluster operation.
+func (m *MockProvider) ScaleClusterContext(ctx context.Context, clusterID string, numComputeNodes int) error {
+	if err := m.script.run(ctx, "ScaleCluster"); err != nil {
+		return err
+	}
+
 	return fmt.Errorf("scale cluster is currently unsupported by the mock provider")
 }
 
-// ClusterKubeconfig mocks a cluster kubeconfig operation.
-func (m *MockProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+// ClusterKubeconfigContext mocks a cluster kubeconfig operation.
+func (m *MockProvider) ClusterKubeconfigContext(ctx context.Context, clusterID string) ([]byte, error) {
+	if err := m.script.run(ctx, "ClusterKubeconfig"); err != nil {
+		return nil, err
+	}
+
 	var (
 		fileReader fs.File
 		err        error
@@ -170,8 +432,12 @@ func (m *MockProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
 	return []byte(f), nil
 }
 
-// CheckQuota mocks a check quota operation.
-func (m *MockProvider) CheckQuota(sku string) (bool, error) {
+// CheckQuotaContext mocks a check quota operation.
+func (m *MockProvider) CheckQuotaContext(ctx context.Context, sku string) (bool, error) {
+	if err := m.script.run(ctx, "CheckQuota"); err != nil {
+		return false, err
+	}
+
 	if m.env == "fail" {
 		return false, fmt.Errorf("failed to get versions: Some fake error")
 	}
@@ -181,36 +447,31 @@ func (m *MockProvider) CheckQuota(sku string) (bool, error) {
 	return true, nil
 }
 
-// InstallAddons mocks an install addons operation.
-func (m *MockProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, params map[spi.AddOnID]spi.AddOnParams) (int, error) {
+// InstallAddonsContext mocks an install addons operation.
+func (m *MockProvider) InstallAddonsContext(ctx context.Context, clusterID string, addonIDs []spi.AddOnID, params map[spi.AddOnID]spi.AddOnParams) (int, error) {
+	if err := m.script.run(ctx, "InstallAddons"); err != nil {
+		return 0, err
+	}
+
 	if clusterID == "fail" {
 		return 0, fmt.Errorf("failed to get versions: Some fake error")
 	}
 
-	cluster, err := m.GetCluster(clusterID)
-	if err != nil {
+	if _, err := m.updateCluster(clusterID, func(builder *spi.ClusterBuilder) {
+		builder.Addons(addonIDs)
+	}); err != nil {
 		return 0, fmt.Errorf("Unable to retrieve cluster: %s", err.Error())
 	}
-	// We can't access the addons field directly so we have to rebuild the cluster object from scratch
-	// This is fine as any real provider would call an external API to update or retrieve addons and
-	// we lose no state doing this.
-	m.clusters[clusterID] = spi.NewClusterBuilder().
-		ID(clusterID).
-		Name(cluster.Name()).
-		Version(cluster.Version()).
-		State(cluster.State()).
-		CloudProvider(cluster.CloudProvider()).
-		Region(cluster.Region()).
-		ExpirationTimestamp(cluster.ExpirationTimestamp()).
-		Flavour(cluster.Flavour()).
-		Addons(addonIDs).
-		Build()
 
 	return len(addonIDs), nil
 }
 
-// Versions mocks a versions operation.
-func (m *MockProvider) Versions() (*spi.VersionList, error) {
+// VersionsContext mocks a versions operation.
+func (m *MockProvider) VersionsContext(ctx context.Context) (*spi.VersionList, error) {
+	if err := m.script.run(ctx, "Versions"); err != nil {
+		return nil, err
+	}
+
 	if m.env == "fail" {
 		return nil, fmt.Errorf("Fake error returning version list")
 	}
@@ -218,8 +479,12 @@ func (m *MockProvider) Versions() (*spi.VersionList, error) {
 	return m.versions, nil
 }
 
-// Logs mocks a logs operation.
-func (m *MockProvider) Logs(clusterID string) (map[string][]byte, error) {
+// LogsContext mocks a logs operation.
+func (m *MockProvider) LogsContext(ctx context.Context, clusterID string) (map[string][]byte, error) {
+	if err := m.script.run(ctx, "Logs"); err != nil {
+		return nil, err
+	}
+
 	if clusterID == "fail" {
 		return nil, fmt.Errorf("failed to get versions: Some fake error")
 	}
@@ -262,65 +527,237 @@ func (m *MockProvider) Type() string {
 	return "mock"
 }
 
-// ExtendExpiry mocks an extend cluster expiry operation.
-func (m *MockProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
-	return fmt.Errorf("ExtendExpiry is unsupported by mock clusters")
+// Capabilities reports the optional features enabled by the mock scenario, which is none by default.
+func (m *MockProvider) Capabilities() spi.Capabilities {
+	return m.capabilities
+}
+
+// ExtendExpiryContext mocks an extend cluster expiry operation.
+func (m *MockProvider) ExtendExpiryContext(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	if err := m.script.run(ctx, "ExtendExpiry"); err != nil {
+		return err
+	}
+
+	if !m.capabilities.ExtendExpiry {
+		return fmt.Errorf("extending expiry is not supported by mock clusters")
+	}
+
+	extension := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+
+	_, err := m.updateCluster(clusterID, func(builder *spi.ClusterBuilder) {
+		cluster := builder.Build()
+		builder.ExpirationTimestamp(cluster.ExpirationTimestamp().Add(extension))
+	})
+	return err
+}
+
+// ExpireContext mocks an expire cluster expiry operation.
+func (m *MockProvider) ExpireContext(ctx context.Context, clusterID string) error {
+	if err := m.script.run(ctx, "Expire"); err != nil {
+		return err
+	}
+
+	if !m.capabilities.ExtendExpiry {
+		return fmt.Errorf("expiring is not supported by mock clusters")
+	}
+
+	_, err := m.updateCluster(clusterID, func(builder *spi.ClusterBuilder) {
+		builder.ExpirationTimestamp(time.Now().Add(1 * time.Minute))
+	})
+	return err
 }
 
-// Expire mocks an expire cluster expiry operation.
-func (m *MockProvider) Expire(clusterID string) error {
-	return fmt.Errorf("Expire is unsupported by mock clusters")
+// AddPropertyContext mocks an add new cluster property operation.
+func (m *MockProvider) AddPropertyContext(ctx context.Context, cluster *spi.Cluster, tag string, value string) error {
+	if err := m.script.run(ctx, "AddProperty"); err != nil {
+		return err
+	}
+
+	_, err := m.updateCluster(cluster.ID(), func(builder *spi.ClusterBuilder) {
+		properties := builder.Build().Properties()
+		clusterproperties.Set(properties, tag, value, time.Now())
+		builder.Properties(properties)
+	})
+	return err
 }
 
-// AddProperty mocks an add new cluster property operation.
-func (m *MockProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
-	return fmt.Errorf("AddProperty is unsupported by mock clusters")
+// UpgradeContext mocks initiates a cluster upgrade to the given version
+func (m *MockProvider) UpgradeContext(ctx context.Context, clusterID string, version string, t time.Time) error {
+	if err := m.script.run(ctx, "Upgrade"); err != nil {
+		return err
+	}
+
+	if !m.capabilities.ManagedUpgrades {
+		return fmt.Errorf("Upgrade is unsupported by mock clusters")
+	}
+
+	cluster, err := m.clusters.get(clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return errNotFound
+	}
+
+	if !m.canUpgrade(cluster.Version(), version) {
+		return fmt.Errorf("no upgrade path from %s to %s", cluster.Version(), version)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.upgradePolicies[clusterID] = &upgradePolicy{
+		id:      uuid.New().String(),
+		version: version,
+		nextRun: t,
+	}
+
+	return nil
 }
 
-// Upgrade mocks initiates a cluster upgrade to the given version
-func (m *MockProvider) Upgrade(clusterID string, version string, t time.Time) error {
-	return fmt.Errorf("Upgrade is unsupported by mock clusters")
+// canUpgrade checks the version graph for an upgrade edge between two versions.
+func (m *MockProvider) canUpgrade(from, to string) bool {
+	fromVersion, err := util.OpenshiftVersionToSemver(from)
+	if err != nil {
+		return false
+	}
+	toVersion, err := util.OpenshiftVersionToSemver(to)
+	if err != nil {
+		return false
+	}
+
+	for _, version := range m.versions.AvailableVersions() {
+		if !version.Version().Equal(fromVersion) {
+			continue
+		}
+		for upgrade := range version.AvailableUpgrades() {
+			if upgrade.Equal(toVersion) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 // Get upgrade policy ID mocks fetch the upgrade policy for a cluster
-func (m *MockProvider) GetUpgradePolicyID(clusterID string) (string, error) {
-	return "mock", fmt.Errorf("Get mock upgrade policy failed")
+func (m *MockProvider) GetUpgradePolicyIDContext(ctx context.Context, clusterID string) (string, error) {
+	if err := m.script.run(ctx, "GetUpgradePolicyID"); err != nil {
+		return "", err
+	}
+
+	if !m.capabilities.ManagedUpgrades {
+		return "mock", fmt.Errorf("Get mock upgrade policy failed")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if policy, ok := m.upgradePolicies[clusterID]; ok {
+		return policy.id, nil
+	}
+
+	return "", nil
 }
 
-// UpdateSchedule mocks reschedule the upgrade
-func (m *MockProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
-	return fmt.Errorf("Upgrade Schedule is not supported by mock clusters")
+// UpdateScheduleContext mocks reschedule the upgrade
+func (m *MockProvider) UpdateScheduleContext(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error {
+	if err := m.script.run(ctx, "UpdateSchedule"); err != nil {
+		return err
+	}
+
+	if !m.capabilities.ManagedUpgrades {
+		return fmt.Errorf("Upgrade Schedule is not supported by mock clusters")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	policy, ok := m.upgradePolicies[clusterID]
+	if !ok || policy.id != policyID {
+		return fmt.Errorf("couldn't find upgrade policy %s for cluster %s", policyID, clusterID)
+	}
+
+	policy.version = version
+	policy.nextRun = t
+
+	return nil
 }
 
-// DetermineMachineType returns a random machine type for a given cluster
-func (m *MockProvider) DetermineMachineType(cloudProvider string) (string, error) {
+// DetermineMachineTypeContext returns a random machine type for a given cluster
+func (m *MockProvider) DetermineMachineTypeContext(ctx context.Context, cloudProvider string) (string, error) {
+	if err := m.script.run(ctx, "DetermineMachineType"); err != nil {
+		return "", err
+	}
+
 	return "mock", fmt.Errorf("DetermineMachineType is not supported by mock clusters")
 }
 
-// Resume resumes a cluster via OCM
-func (o *MockProvider) Resume(id string) bool {
-	log.Println("Hibernation not supported in Mock Provider")
-	return true
+// ResumeContext mocks resuming a hibernating cluster.
+func (m *MockProvider) ResumeContext(ctx context.Context, id string) bool {
+	if err := m.script.run(ctx, "Resume"); err != nil {
+		log.Printf("%v", err)
+		return false
+	}
+
+	return m.changeState(id, spi.ClusterStateHibernating, spi.ClusterStateResuming)
+}
+
+// HibernateContext mocks hibernating a ready cluster.
+func (m *MockProvider) HibernateContext(ctx context.Context, id string) bool {
+	if err := m.script.run(ctx, "Hibernate"); err != nil {
+		log.Printf("%v", err)
+		return false
+	}
+
+	return m.changeState(id, spi.ClusterStateReady, spi.ClusterStateHibernating)
 }
 
-// Hibernate resumes a cluster via OCM
-func (o *MockProvider) Hibernate(id string) bool {
-	log.Println("Hibernation not supported in Mock Provider")
-	return true
+// changeState moves a cluster between hibernation states if the scenario enables hibernation.
+func (m *MockProvider) changeState(id string, from, to spi.ClusterState) bool {
+	if !m.capabilities.Hibernation {
+		log.Println("Hibernation not supported in Mock Provider")
+		return false
+	}
+
+	changed := false
+	if _, err := m.updateCluster(id, func(builder *spi.ClusterBuilder) {
+		if builder.Build().State() == from {
+			builder.State(to)
+			changed = true
+		}
+	}); err != nil {
+		log.Printf("Error changing state of cluster %s: %v", id, err)
+		return false
+	}
+
+	if !changed {
+		log.Printf("Cluster %s is not %s", id, from)
+	}
+	return changed
 }
 
-// AddClusterProxy adds a proxy to a cluster
-func (m *MockProvider) AddClusterProxy(clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+// AddClusterProxyContext adds a proxy to a cluster
+func (m *MockProvider) AddClusterProxyContext(ctx context.Context, clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+	if err := m.script.run(ctx, "AddClusterProxy"); err != nil {
+		return err
+	}
+
 	return fmt.Errorf("proxies not supported in Mock Provider")
 }
 
-// RemoveClusterProxy removes a proxy from a cluster
-func (m *MockProvider) RemoveClusterProxy(clusterId string) error {
+// RemoveClusterProxyContext removes a proxy from a cluster
+func (m *MockProvider) RemoveClusterProxyContext(ctx context.Context, clusterId string) error {
+	if err := m.script.run(ctx, "RemoveClusterProxy"); err != nil {
+		return err
+	}
+
 	return fmt.Errorf("proxies not supported in Mock Provider")
 }
 
-// RemoveUserCABundle removes a CA Bundle from a cluster
-func (m *MockProvider) RemoveUserCABundle(clusterId string) error {
+// RemoveUserCABundleContext removes a CA Bundle from a cluster
+func (m *MockProvider) RemoveUserCABundleContext(ctx context.Context, clusterId string) error {
+	if err := m.script.run(ctx, "RemoveUserCABundle"); err != nil {
+		return err
+	}
+
 	return fmt.Errorf("proxies not supported in Mock Provider")
 }
 
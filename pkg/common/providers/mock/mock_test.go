@@ -1,11 +1,19 @@
 package mock
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	"github.com/openshift/osde2e/pkg/common/metadata"
 	"github.com/openshift/osde2e/pkg/common/spi"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -49,6 +57,10 @@ func TestClusterInteraction(t *testing.T) {
 		t.Errorf("cluster IDs did not match for cluster 2. Expected %s, got %s", clusterID2, cluster2.ID())
 	}
 
+	if err := mockProvider.ExtendExpiry(clusterID1, 1, 0, 0); err == nil {
+		t.Errorf("expected an error extending expiry without the extend-expiry capability")
+	}
+
 	mockProvider.DeleteCluster(clusterID1)
 
 	_, err = mockProvider.GetCluster(clusterID1)
@@ -203,6 +215,408 @@ func TestSetVersionList(t *testing.T) {
 	}
 }
 
+func TestCancelledContext(t *testing.T) {
+	mockProvider := makeMockProviderWithEnv("mockEnv")
+
+	clusterID, err := mockProvider.LaunchCluster("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := mockProvider.LaunchClusterContext(ctx, "cluster2"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected LaunchClusterContext to return context.Canceled, got: %v", err)
+	}
+
+	if _, err := mockProvider.GetClusterContext(ctx, clusterID); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected GetClusterContext to return context.Canceled, got: %v", err)
+	}
+
+	if mockProvider.HibernateContext(ctx, clusterID) {
+		t.Errorf("expected HibernateContext to fail with a cancelled context")
+	}
+
+	// The cancelled calls must not have touched the provider's state.
+	if _, err := mockProvider.GetCluster(clusterID); err != nil {
+		t.Errorf("expected cluster to still exist after cancelled calls: %v", err)
+	}
+}
+
+func TestBundledScenarios(t *testing.T) {
+	for _, name := range []string{"provisioning", "recycle", "upgrade"} {
+		if _, err := LoadScenario(name); err != nil {
+			t.Errorf("unable to load bundled scenario %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadScenarioErrors(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Contents string
+	}{
+		{
+			Name:     "unknown method",
+			Contents: "methods:\n  GetClusters:\n    latency: 1s\n",
+		},
+		{
+			Name:     "capability that can't be emulated",
+			Contents: "capabilities:\n  - scaling\n",
+		},
+		{
+			Name:     "transition without a target",
+			Contents: "transitions:\n  pending:\n    after: 2\n",
+		},
+		{
+			Name:     "invalid version",
+			Contents: "versions:\n  - version: latest\n",
+		},
+		{
+			Name:     "invalid yaml",
+			Contents: "methods: [",
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "scenario.yaml")
+		if err := os.WriteFile(path, []byte(test.Contents), 0o600); err != nil {
+			t.Fatalf("unable to write scenario: %v", err)
+		}
+
+		if _, err := LoadScenario(path); err == nil {
+			t.Errorf("%s: expected an error loading the scenario", test.Name)
+		}
+	}
+
+	if _, err := LoadScenario("does-not-exist"); err == nil {
+		t.Errorf("expected an error loading a scenario that doesn't exist")
+	}
+}
+
+func TestScenarioMethodScripts(t *testing.T) {
+	mockProvider := makeMockProviderWithEnv("mockEnv")
+	if err := mockProvider.SetScenario(&MockScenario{
+		Methods: map[string]MethodScript{
+			"GetCluster": {Errors: []string{"first failure", "", "second failure"}},
+			"Logs":       {Latency: time.Hour},
+		},
+	}); err != nil {
+		t.Fatalf("unable to set scenario: %v", err)
+	}
+
+	clusterID, err := mockProvider.LaunchCluster("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+
+	for i, expectError := range []bool{true, false, true, false, false} {
+		if _, err := mockProvider.GetCluster(clusterID); (err != nil) != expectError {
+			t.Errorf("GetCluster call %d: expected error to be %t, got %v", i, expectError, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := mockProvider.LogsContext(ctx, clusterID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the scripted latency to outlast the context, got: %v", err)
+	}
+}
+
+func TestScenarioStateTransitions(t *testing.T) {
+	mockProvider := makeMockProviderWithEnv("mockEnv")
+	if err := mockProvider.SetScenario(&MockScenario{
+		Capabilities: []spi.Capability{spi.HibernationCapability},
+		InitialState: spi.ClusterStatePending,
+		Transitions: map[spi.ClusterState]StateTransition{
+			spi.ClusterStatePending:    {To: spi.ClusterStateInstalling, After: 1},
+			spi.ClusterStateInstalling: {To: spi.ClusterStateReady, After: 2},
+			spi.ClusterStateResuming:   {To: spi.ClusterStateReady},
+		},
+	}); err != nil {
+		t.Fatalf("unable to set scenario: %v", err)
+	}
+
+	clusterID, err := mockProvider.LaunchCluster("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+
+	steps := []struct {
+		Name          string
+		Action        func() bool
+		ExpectedState spi.ClusterState
+	}{
+		{Name: "launched", ExpectedState: spi.ClusterStatePending},
+		{Name: "installation starts", ExpectedState: spi.ClusterStateInstalling},
+		{Name: "installing", ExpectedState: spi.ClusterStateInstalling},
+		{Name: "still installing", ExpectedState: spi.ClusterStateInstalling},
+		{Name: "installed", ExpectedState: spi.ClusterStateReady},
+		{
+			Name:          "hibernate",
+			Action:        func() bool { return mockProvider.Hibernate(clusterID) },
+			ExpectedState: spi.ClusterStateHibernating,
+		},
+		{
+			Name:          "resume",
+			Action:        func() bool { return mockProvider.Resume(clusterID) },
+			ExpectedState: spi.ClusterStateReady,
+		},
+	}
+
+	for _, step := range steps {
+		if step.Action != nil && !step.Action() {
+			t.Fatalf("%s: action failed", step.Name)
+		}
+
+		cluster, err := mockProvider.GetCluster(clusterID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error getting cluster: %v", step.Name, err)
+		}
+		if cluster.State() != step.ExpectedState {
+			t.Errorf("%s: expected state %s, got %s", step.Name, step.ExpectedState, cluster.State())
+		}
+	}
+
+	if mockProvider.Resume(clusterID) {
+		t.Errorf("expected resuming a ready cluster to fail")
+	}
+}
+
+func TestScenarioClusters(t *testing.T) {
+	mockProvider := makeMockProviderWithEnv("mockEnv")
+	scenario, err := LoadScenario("recycle")
+	if err != nil {
+		t.Fatalf("unable to load scenario: %v", err)
+	}
+	if err := mockProvider.SetScenario(scenario); err != nil {
+		t.Fatalf("unable to set scenario: %v", err)
+	}
+
+	cluster, err := mockProvider.GetCluster("mock-recycled-hibernating")
+	if err != nil {
+		t.Fatalf("unexpected error getting a scenario cluster: %v", err)
+	}
+	if cluster.State() != spi.ClusterStateHibernating || cluster.Properties()["Status"] != "completed-passing" {
+		t.Errorf("unexpected scenario cluster: state %s, properties %v", cluster.State(), cluster.Properties())
+	}
+	if !cluster.ExpirationTimestamp().After(time.Now().Add(23 * time.Hour)) {
+		t.Errorf("expected the scenario cluster to expire in a day, got %v", cluster.ExpirationTimestamp())
+	}
+
+	if err := mockProvider.AddProperty(cluster, "JobID", "1234"); err != nil {
+		t.Fatalf("unexpected error adding a property: %v", err)
+	}
+	if cluster, _ = mockProvider.GetCluster(cluster.ID()); cluster.Properties()["JobID"] != "1234" {
+		t.Errorf("expected the property to be added, got %v", cluster.Properties())
+	}
+}
+
+func TestScenarioRecycling(t *testing.T) {
+	viper.Reset()
+	viper.Set(Scenario, "recycle")
+	viper.Set(config.Cluster.Version, "openshift-v4.11.12")
+	viper.Set(config.JobID, "1234")
+	mockProvider, err := New()
+	if err != nil {
+		t.Fatalf("unable to create mock provider with scenario: %v", err)
+	}
+
+	clusterID, err := mockProvider.LaunchCluster("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+	if strings.HasPrefix(clusterID, "mock-recycled-") || viper.GetBool(config.Cluster.Reused) {
+		t.Errorf("expected a new cluster unless existing clusters may be used, got %s", clusterID)
+	}
+
+	viper.Set(config.Cluster.UseExistingCluster, true)
+	recycled := map[string]*spi.Cluster{}
+	for _, name := range []string{"cluster2", "cluster3"} {
+		clusterID, err := mockProvider.LaunchCluster(name)
+		if err != nil {
+			t.Fatalf("unexpected error launching cluster: %v", err)
+		}
+		cluster, err := mockProvider.GetCluster(clusterID)
+		if err != nil {
+			t.Fatalf("unexpected error getting cluster: %v", err)
+		}
+		recycled[clusterID] = cluster
+	}
+	if !viper.GetBool(config.Cluster.Reused) {
+		t.Errorf("expected recycled clusters to be marked as reused")
+	}
+
+	if cluster, ok := recycled["mock-recycled-ready"]; !ok {
+		t.Errorf("expected the ready cluster to be recycled, got %v", recycled)
+	} else if cluster.Properties()["JobID"] != "1234" || cluster.Properties()["Status"] != "healthy" {
+		t.Errorf("expected the ready cluster to be claimed as healthy, got %v", cluster.Properties())
+	}
+	if cluster, ok := recycled["mock-recycled-hibernating"]; !ok {
+		t.Errorf("expected the hibernating cluster to be recycled, got %v", recycled)
+	} else if cluster.State() != spi.ClusterStateResuming || cluster.Properties()["Status"] != "resuming" {
+		t.Errorf("expected the hibernating cluster to be resumed, got state %s, properties %v", cluster.State(), cluster.Properties())
+	}
+
+	clusterID, err = mockProvider.LaunchCluster("cluster4")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+	if strings.HasPrefix(clusterID, "mock-recycled-") {
+		t.Errorf("expected a new cluster once every cluster is claimed, got %s", clusterID)
+	}
+
+	expiring, err := mockProvider.GetCluster("mock-recycled-expiring")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster: %v", err)
+	}
+	if expiring.Properties()["JobName"] != "expiring" || expiring.ExpirationTimestamp().After(time.Now()) {
+		t.Errorf("expected the cluster close to expiry to be expired, got expiry %v, properties %v", expiring.ExpirationTimestamp(), expiring.Properties())
+	}
+}
+
+func TestScenarioUpgrades(t *testing.T) {
+	viper.Reset()
+	viper.Set(Scenario, "upgrade")
+	viper.Set(config.Cluster.Version, "openshift-v4.11.12")
+	mockProvider, err := New()
+	if err != nil {
+		t.Fatalf("unable to create mock provider with scenario: %v", err)
+	}
+
+	versions, err := mockProvider.Versions()
+	if err != nil {
+		t.Fatalf("error retrieving provider versions: %v", err)
+	}
+	if len(versions.AvailableVersions()) != 4 || versions.Default().String() != "4.11.12" {
+		t.Errorf("unexpected scenario versions: %v, default %v", versions.AvailableVersions(), versions.Default())
+	}
+
+	clusterID, err := mockProvider.LaunchCluster("cluster1")
+	if err != nil {
+		t.Fatalf("unexpected error launching cluster: %v", err)
+	}
+
+	if err := mockProvider.Upgrade(clusterID, "openshift-v4.10.40", time.Now()); err == nil {
+		t.Errorf("expected an error upgrading to a version without an upgrade edge")
+	}
+
+	nextRun := time.Now().Add(time.Hour)
+	if err := mockProvider.Upgrade(clusterID, "openshift-v4.12.1", nextRun); err != nil {
+		t.Fatalf("unexpected error scheduling upgrade: %v", err)
+	}
+
+	if _, err := mockProvider.GetUpgradePolicyID(clusterID); err == nil {
+		t.Errorf("expected the scripted GetUpgradePolicyID error")
+	}
+
+	policyID, err := mockProvider.GetUpgradePolicyID(clusterID)
+	if err != nil || policyID == "" {
+		t.Fatalf("expected an upgrade policy, got %q, %v", policyID, err)
+	}
+
+	if err := mockProvider.UpdateSchedule(clusterID, "openshift-v4.12.1", nextRun.Add(time.Hour), policyID); err != nil {
+		t.Errorf("unexpected error rescheduling upgrade: %v", err)
+	}
+	if err := mockProvider.UpdateSchedule(clusterID, "openshift-v4.12.1", nextRun, "some-other-policy"); err == nil {
+		t.Errorf("expected an error rescheduling an unknown upgrade policy")
+	}
+}
+
+func TestPersistentState(t *testing.T) {
+	viper.Reset()
+	viper.Set(StateDir, t.TempDir())
+
+	// Each provider stands in for a separate osde2e invocation sharing the state directory.
+	newProvider := func() *MockProvider {
+		mockProvider, err := New()
+		if err != nil {
+			t.Fatalf("unable to create mock provider: %v", err)
+		}
+		if err := mockProvider.SetScenario(&MockScenario{Capabilities: []spi.Capability{spi.ExtendExpiryCapability}}); err != nil {
+			t.Fatalf("unable to set scenario: %v", err)
+		}
+		return mockProvider
+	}
+
+	launcher := newProvider()
+	var clusterIDs []string
+	for _, name := range []string{"cluster1", "cluster2"} {
+		clusterID, err := launcher.LaunchCluster(name)
+		if err != nil {
+			t.Fatalf("unexpected error launching cluster: %v", err)
+		}
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+
+	reader := newProvider()
+	cluster, err := reader.GetCluster(clusterIDs[0])
+	if err != nil {
+		t.Fatalf("expected a cluster launched by another provider to be found: %v", err)
+	}
+	if cluster.Name() != "cluster1" || cluster.State() != spi.ClusterStateReady {
+		t.Errorf("unexpected cluster: name %s, state %s", cluster.Name(), cluster.State())
+	}
+
+	if err := reader.AddProperty(cluster, "JobID", "1234"); err != nil {
+		t.Fatalf("unexpected error adding a property: %v", err)
+	}
+	expiration := cluster.ExpirationTimestamp()
+	if err := reader.ExtendExpiry(clusterIDs[0], 1, 30, 0); err != nil {
+		t.Fatalf("unexpected error extending expiry: %v", err)
+	}
+
+	cluster, err = newProvider().GetCluster(clusterIDs[0])
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster: %v", err)
+	}
+	if cluster.Properties()["JobID"] != "1234" {
+		t.Errorf("expected the property to persist, got %v", cluster.Properties())
+	}
+	if extension := cluster.ExpirationTimestamp().Sub(expiration); extension != 90*time.Minute {
+		t.Errorf("expected the expiry to be extended by 90m, got %v", extension)
+	}
+
+	if err := newProvider().Expire(clusterIDs[1]); err != nil {
+		t.Fatalf("unexpected error expiring cluster: %v", err)
+	}
+	if cluster, _ = launcher.GetCluster(clusterIDs[1]); cluster.ExpirationTimestamp().After(time.Now().Add(time.Minute)) {
+		t.Errorf("expected the cluster to expire within a minute, got %v", cluster.ExpirationTimestamp())
+	}
+
+	// Reading stored clusters doesn't record their state as the run's cluster status.
+	metadata.Instance.Status = ""
+	clusters, err := newProvider().ListClusters("")
+	if err != nil {
+		t.Fatalf("unexpected error listing clusters: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Errorf("expected 2 clusters, got %d", len(clusters))
+	}
+	if metadata.Instance.Status != "" {
+		t.Errorf("expected listing clusters to leave the run's status alone, got %s", metadata.Instance.Status)
+	}
+
+	clusters, err = newProvider().ListClusters("properties.JobID='1234'")
+	if err != nil {
+		t.Fatalf("unexpected error listing clusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].ID() != clusterIDs[0] {
+		t.Errorf("expected only the cluster with the property to match, got %d clusters", len(clusters))
+	}
+	if _, err := launcher.ListClusters("properties.JobID"); err == nil {
+		t.Errorf("expected an error listing clusters with an invalid query")
+	}
+
+	if err := launcher.DeleteCluster(clusterIDs[0]); err != nil {
+		t.Fatalf("unexpected error deleting cluster: %v", err)
+	}
+	if _, err := reader.GetCluster(clusterIDs[0]); err == nil {
+		t.Errorf("expected a deleted cluster to be gone for every provider")
+	}
+}
+
 func makeMockProviderWithEnv(env string) *MockProvider {
 	viper.Reset()
 	viper.Set(Env, env)
@@ -0,0 +1,274 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/osde2e/assets"
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// scenarioDir is where bundled scenarios live in the assets.
+const scenarioDir = "providers/mock/scenarios"
+
+// scriptedMethods are the provider methods a scenario may script, named without their Context suffix.
+var scriptedMethods = map[string]bool{
+	"IsValidClusterName":   true,
+	"LaunchCluster":        true,
+	"DeleteCluster":        true,
+	"ListClusters":         true,
+	"GetCluster":           true,
+	"ScaleCluster":         true,
+	"ClusterKubeconfig":    true,
+	"CheckQuota":           true,
+	"InstallAddons":        true,
+	"Versions":             true,
+	"Logs":                 true,
+	"ExtendExpiry":         true,
+	"Expire":               true,
+	"AddProperty":          true,
+	"Upgrade":              true,
+	"GetUpgradePolicyID":   true,
+	"UpdateSchedule":       true,
+	"DetermineMachineType": true,
+	"Hibernate":            true,
+	"Resume":               true,
+	"AddClusterProxy":      true,
+	"RemoveClusterProxy":   true,
+	"RemoveUserCABundle":   true,
+}
+
+// MockScenario scripts the behaviour of the mock provider so that provisioning, recycling and upgrade
+// flows can be exercised without OCM.
+type MockScenario struct {
+	// Capabilities lists the optional provider features the mock should report and emulate.
+	// Only hibernation, managed-upgrades, extend-expiry and recycling are emulated.
+	Capabilities []spi.Capability `yaml:"capabilities"`
+
+	// Methods scripts individual provider methods, keyed by method name without the Context suffix.
+	Methods map[string]MethodScript `yaml:"methods"`
+
+	// InitialState is the state launched clusters start in. Clusters start ready if unset.
+	InitialState spi.ClusterState `yaml:"initialState"`
+
+	// Transitions moves clusters out of a state once they have been read enough times in it.
+	Transitions map[spi.ClusterState]StateTransition `yaml:"transitions"`
+
+	// Versions replaces the mock's default versions, along with their upgrade edges.
+	Versions []ScenarioVersion `yaml:"versions"`
+
+	// Clusters exist before anything is launched, e.g. for recycling to find.
+	Clusters []ScenarioCluster `yaml:"clusters"`
+}
+
+// MethodScript describes how a single provider method behaves.
+type MethodScript struct {
+	// Latency is how long each call takes.
+	Latency time.Duration `yaml:"latency"`
+
+	// Errors are returned by successive calls, one per call. An empty entry lets that call
+	// succeed, and calls beyond the end of the list always succeed.
+	Errors []string `yaml:"errors"`
+}
+
+// StateTransition moves a cluster to the next state.
+type StateTransition struct {
+	// To is the state the cluster moves to.
+	To spi.ClusterState `yaml:"to"`
+
+	// After is the number of GetCluster calls that observe the current state before it changes.
+	After int `yaml:"after"`
+}
+
+// ScenarioVersion is a version offered by the mock provider.
+type ScenarioVersion struct {
+	Version  string   `yaml:"version"`
+	Default  bool     `yaml:"default"`
+	Upgrades []string `yaml:"upgrades"`
+}
+
+// ScenarioCluster is a cluster that exists when the mock provider is created.
+type ScenarioCluster struct {
+	ID         string            `yaml:"id"`
+	Name       string            `yaml:"name"`
+	Version    string            `yaml:"version"`
+	State      spi.ClusterState  `yaml:"state"`
+	Properties map[string]string `yaml:"properties"`
+
+	// ExpiresIn is how long after the provider is created the cluster expires.
+	ExpiresIn time.Duration `yaml:"expiresIn"`
+}
+
+// LoadScenario reads a scenario bundled with osde2e by name, or from a file if name is a path.
+func LoadScenario(name string) (*MockScenario, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		data, err = assets.FS.ReadFile(fmt.Sprintf("%s/%s.yaml", scenarioDir, name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read mock scenario %s: %v", name, err)
+	}
+
+	scenario := &MockScenario{}
+	if err := yaml.Unmarshal(data, scenario); err != nil {
+		return nil, fmt.Errorf("unable to parse mock scenario %s: %v", name, err)
+	}
+
+	if err := scenario.validate(); err != nil {
+		return nil, fmt.Errorf("invalid mock scenario %s: %v", name, err)
+	}
+
+	return scenario, nil
+}
+
+func (s *MockScenario) validate() error {
+	for method := range s.Methods {
+		if !scriptedMethods[method] {
+			return fmt.Errorf("unknown method %s", method)
+		}
+	}
+
+	for _, capability := range s.Capabilities {
+		switch capability {
+		case spi.HibernationCapability, spi.ManagedUpgradesCapability, spi.ExtendExpiryCapability, spi.RecyclingCapability:
+		default:
+			return fmt.Errorf("capability %s can't be emulated", capability)
+		}
+	}
+
+	for from, transition := range s.Transitions {
+		if transition.To == "" {
+			return fmt.Errorf("transition from %s has no target state", from)
+		}
+	}
+
+	for _, version := range s.Versions {
+		if _, err := semver.NewVersion(version.Version); err != nil {
+			return fmt.Errorf("version %s: %v", version.Version, err)
+		}
+		for _, upgrade := range version.Upgrades {
+			if _, err := semver.NewVersion(upgrade); err != nil {
+				return fmt.Errorf("upgrade %s of version %s: %v", upgrade, version.Version, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// capabilities converts the scenario's capability list.
+func (s *MockScenario) capabilities() spi.Capabilities {
+	capabilities := spi.Capabilities{}
+	for _, capability := range s.Capabilities {
+		switch capability {
+		case spi.HibernationCapability:
+			capabilities.Hibernation = true
+		case spi.ManagedUpgradesCapability:
+			capabilities.ManagedUpgrades = true
+		case spi.ExtendExpiryCapability:
+			capabilities.ExtendExpiry = true
+		case spi.RecyclingCapability:
+			capabilities.Recycling = true
+		}
+	}
+	return capabilities
+}
+
+// versionList builds the scenario's version graph.
+func (s *MockScenario) versionList() *spi.VersionList {
+	versions := []*spi.Version{}
+	for _, scenarioVersion := range s.Versions {
+		version := spi.NewVersionBuilder().
+			Version(semver.MustParse(scenarioVersion.Version)).
+			Default(scenarioVersion.Default).
+			Build()
+		for _, upgrade := range scenarioVersion.Upgrades {
+			version.AddUpgradePath(semver.MustParse(upgrade))
+		}
+		versions = append(versions, version)
+	}
+
+	return spi.NewVersionListBuilder().
+		AvailableVersions(versions).
+		Build()
+}
+
+// scriptRunner tracks the progress of a scenario.
+type scriptRunner struct {
+	scenario *MockScenario
+
+	mu    sync.Mutex
+	calls map[string]int
+	reads map[string]int
+}
+
+func newScriptRunner(scenario *MockScenario) *scriptRunner {
+	if scenario == nil {
+		scenario = &MockScenario{}
+	}
+
+	return &scriptRunner{
+		scenario: scenario,
+		calls:    map[string]int{},
+		reads:    map[string]int{},
+	}
+}
+
+// run applies the method's latency and returns its next scripted error, or the context's error.
+func (r *scriptRunner) run(ctx context.Context, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	script, ok := r.scenario.Methods[method]
+	if !ok {
+		return nil
+	}
+
+	if script.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(script.Latency):
+		}
+	}
+
+	r.mu.Lock()
+	call := r.calls[method]
+	r.calls[method]++
+	r.mu.Unlock()
+
+	if call < len(script.Errors) && script.Errors[call] != "" {
+		return fmt.Errorf("%s: %s", method, script.Errors[call])
+	}
+
+	return nil
+}
+
+// observe records a read of a cluster in its current state and returns the state it should move
+// to, if the scenario moves it on.
+func (r *scriptRunner) observe(clusterID string, state spi.ClusterState) (spi.ClusterState, bool) {
+	transition, ok := r.scenario.Transitions[state]
+	if !ok {
+		return state, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reads[clusterID] < transition.After {
+		r.reads[clusterID]++
+		return state, false
+	}
+
+	delete(r.reads, clusterID)
+	return transition.To, true
+}
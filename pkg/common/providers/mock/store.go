@@ -0,0 +1,289 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+const (
+	// stateFile holds the clusters of a file-backed store.
+	stateFile = "clusters.json"
+
+	// lockFile serializes access to the state file between processes.
+	lockFile = "clusters.lock"
+)
+
+// clusterStore keeps the mock provider's clusters.
+type clusterStore interface {
+	// get returns a cluster, or nil if it doesn't exist.
+	get(id string) (*spi.Cluster, error)
+
+	// put creates or replaces a cluster.
+	put(cluster *spi.Cluster) error
+
+	// update replaces a cluster with the result of fn, atomically. fn isn't called for missing clusters.
+	update(id string, fn func(*spi.Cluster) *spi.Cluster) (*spi.Cluster, error)
+
+	// delete removes a cluster. Deleting a missing cluster is not an error.
+	delete(id string) error
+
+	// list returns all clusters ordered by creation time.
+	list() ([]*spi.Cluster, error)
+}
+
+// memoryStore keeps clusters for the life of the process.
+type memoryStore struct {
+	mu       sync.Mutex
+	clusters map[string]*spi.Cluster
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{clusters: map[string]*spi.Cluster{}}
+}
+
+func (s *memoryStore) get(id string) (*spi.Cluster, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.clusters[id], nil
+}
+
+func (s *memoryStore) put(cluster *spi.Cluster) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clusters[cluster.ID()] = cluster
+	return nil
+}
+
+func (s *memoryStore) update(id string, fn func(*spi.Cluster) *spi.Cluster) (*spi.Cluster, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cluster, ok := s.clusters[id]
+	if !ok {
+		return nil, nil
+	}
+	s.clusters[id] = fn(cluster)
+	return s.clusters[id], nil
+}
+
+func (s *memoryStore) delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clusters, id)
+	return nil
+}
+
+func (s *memoryStore) list() ([]*spi.Cluster, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedClusters(s.clusters), nil
+}
+
+// fileStore keeps clusters in a JSON file so that they outlive the process. Every operation locks
+// the directory and rereads the file, so concurrent osde2e invocations see each other's changes.
+//
+// It works on records, which are turned back into clusters without recording their state in the run's metadata.
+type fileStore struct {
+	dir string
+}
+
+func newFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create mock state directory %s: %v", dir, err)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) get(id string) (cluster *spi.Cluster, err error) {
+	err = s.transact(false, func(records map[string]clusterRecord) {
+		if record, ok := records[id]; ok {
+			cluster = record.cluster()
+		}
+	})
+	return cluster, err
+}
+
+func (s *fileStore) put(cluster *spi.Cluster) error {
+	return s.transact(true, func(records map[string]clusterRecord) {
+		records[cluster.ID()] = newClusterRecord(cluster)
+	})
+}
+
+func (s *fileStore) update(id string, fn func(*spi.Cluster) *spi.Cluster) (cluster *spi.Cluster, err error) {
+	err = s.transact(true, func(records map[string]clusterRecord) {
+		if record, ok := records[id]; ok {
+			cluster = fn(record.cluster())
+			records[id] = newClusterRecord(cluster)
+		}
+	})
+	return cluster, err
+}
+
+func (s *fileStore) delete(id string) error {
+	return s.transact(true, func(records map[string]clusterRecord) {
+		delete(records, id)
+	})
+}
+
+func (s *fileStore) list() (list []*spi.Cluster, err error) {
+	err = s.transact(false, func(records map[string]clusterRecord) {
+		clusters := map[string]*spi.Cluster{}
+		for id, record := range records {
+			clusters[id] = record.cluster()
+		}
+		list = sortedClusters(clusters)
+	})
+	return list, err
+}
+
+// transact runs fn against the stored records while holding the lock, saving them afterwards if write is set.
+func (s *fileStore) transact(write bool, fn func(map[string]clusterRecord)) error {
+	lock, err := os.OpenFile(filepath.Join(s.dir, lockFile), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open mock state lock: %v", err)
+	}
+	defer lock.Close()
+
+	how := syscall.LOCK_SH
+	if write {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(lock.Fd()), how); err != nil {
+		return fmt.Errorf("unable to lock mock state: %v", err)
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	fn(records)
+
+	if !write {
+		return nil
+	}
+	return s.save(records)
+}
+
+func (s *fileStore) load() (map[string]clusterRecord, error) {
+	records := map[string]clusterRecord{}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, stateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return records, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read mock state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("unable to parse mock state %s: %v", filepath.Join(s.dir, stateFile), err)
+	}
+	return records, nil
+}
+
+// save replaces the state file in one step so that readers never see a partial write.
+func (s *fileStore) save(records map[string]clusterRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode mock state: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, stateFile+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write mock state: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write mock state: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write mock state: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, stateFile))
+}
+
+// clusterRecord is the stored form of an spi.Cluster.
+type clusterRecord struct {
+	ID                  string            `json:"id"`
+	Name                string            `json:"name"`
+	Version             string            `json:"version"`
+	CloudProvider       string            `json:"cloudProvider"`
+	Product             string            `json:"product"`
+	Region              string            `json:"region"`
+	CreationTimestamp   time.Time         `json:"creationTimestamp"`
+	ExpirationTimestamp time.Time         `json:"expirationTimestamp"`
+	State               spi.ClusterState  `json:"state"`
+	Flavour             string            `json:"flavour"`
+	Addons              []string          `json:"addons,omitempty"`
+	NumComputeNodes     int               `json:"numComputeNodes"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+func newClusterRecord(cluster *spi.Cluster) clusterRecord {
+	return clusterRecord{
+		ID:                  cluster.ID(),
+		Name:                cluster.Name(),
+		Version:             cluster.Version(),
+		CloudProvider:       cluster.CloudProvider(),
+		Product:             cluster.Product(),
+		Region:              cluster.Region(),
+		CreationTimestamp:   cluster.CreationTimestamp(),
+		ExpirationTimestamp: cluster.ExpirationTimestamp(),
+		State:               cluster.State(),
+		Flavour:             cluster.Flavour(),
+		Addons:              cluster.Addons(),
+		NumComputeNodes:     cluster.NumComputeNodes(),
+		Properties:          cluster.Properties(),
+	}
+}
+
+func (r clusterRecord) cluster() *spi.Cluster {
+	return spi.NewClusterBuilder().
+		ID(r.ID).
+		Name(r.Name).
+		Version(r.Version).
+		CloudProvider(r.CloudProvider).
+		Product(r.Product).
+		Region(r.Region).
+		CreationTimestamp(r.CreationTimestamp).
+		ExpirationTimestamp(r.ExpirationTimestamp).
+		StoredState(r.State).
+		Flavour(r.Flavour).
+		Addons(append([]string{}, r.Addons...)).
+		NumComputeNodes(r.NumComputeNodes).
+		Properties(r.Properties).
+		Build()
+}
+
+func sortedClusters(clusters map[string]*spi.Cluster) []*spi.Cluster {
+	list := make([]*spi.Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		list = append(list, cluster)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreationTimestamp().Equal(list[j].CreationTimestamp()) {
+			return list[i].CreationTimestamp().Before(list[j].CreationTimestamp())
+		}
+		return list[i].ID() < list[j].ID()
+	})
+	return list
+}
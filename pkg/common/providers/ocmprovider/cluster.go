@@ -23,12 +23,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-// IsValidClusterName validates the clustername prior to proceeding with it
+// IsValidClusterNameContext validates the clustername prior to proceeding with it
 // in launching a cluster.
-func (o *OCMProvider) IsValidClusterName(clusterName string) (bool, error) {
-	// Create a context:
-	ctx := context.Background()
-
+func (o *OCMProvider) IsValidClusterNameContext(ctx context.Context, clusterName string) (bool, error) {
 	collection := o.conn.ClustersMgmt().V1().Clusters()
 
 	// Retrieve the list of clusters using pages of ten items, till we get a page that has less
@@ -63,14 +60,14 @@ func (o *OCMProvider) IsValidClusterName(clusterName string) (bool, error) {
 	return true, nil
 }
 
-// LaunchCluster setups an new cluster using the OSD API and returns it's ID.
+// LaunchClusterContext setups an new cluster using the OSD API and returns it's ID.
 // nolint:gocyclo
-func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
+func (o *OCMProvider) LaunchClusterContext(ctx context.Context, clusterName string) (string, error) {
 	flavourID := getFlavour()
 	skuID := getSKU()
 	if skuID != "" {
 		// check that enough quota exists for this test if creating cluster
-		if enoughQuota, err := o.CheckQuota(skuID); err != nil {
+		if enoughQuota, err := o.CheckQuotaContext(ctx, skuID); err != nil {
 			log.Printf("Failed to check if enough quota is available: %v", err)
 		} else if !enoughQuota {
 			return "", fmt.Errorf("currently not enough quota exists to run this test")
@@ -82,12 +79,12 @@ func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
 
 	multiAZ := viper.GetBool(config.Cluster.MultiAZ)
 	cloudProvider := viper.GetString(config.CloudProvider.CloudProviderID)
-	computeMachineType, err := o.DetermineMachineType(cloudProvider)
+	computeMachineType, err := o.DetermineMachineTypeContext(ctx, cloudProvider)
 	if err != nil {
 		return "", fmt.Errorf("error while determining machine type: %v", err)
 	}
 
-	region, err := o.DetermineRegion(cloudProvider)
+	region, err := o.DetermineRegion(ctx, cloudProvider)
 	if err != nil {
 		return "", fmt.Errorf("error while determining region: %v", err)
 	}
@@ -174,7 +171,7 @@ func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
 					if err != nil {
 						return "", fmt.Errorf("error building AWS cloud provider data for retrieving Availability Zones: %v", err)
 					}
-					subnetworks, err := o.GetSubnetworks(cloudProviderData)
+					subnetworks, err := o.GetSubnetworks(ctx, cloudProviderData)
 					if err != nil {
 						return "", fmt.Errorf("error retrieving AWS subnetworks: %v", err)
 					}
@@ -272,16 +269,16 @@ func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
 		if product == "" {
 			product = "osd"
 		}
-		if clusterID := o.FindRecycledCluster(cluster.Version().ID(), cluster.CloudProvider().ID(), product); clusterID != "" {
+		if clusterID := o.FindRecycledCluster(ctx, cluster.Version().ID(), cluster.CloudProvider().ID(), product); clusterID != "" {
 			return clusterID, nil
 		}
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Add().
 			Body(cluster).
-			Send()
+			SendContext(ctx)
 
 		if resp != nil && resp.Error() != nil {
 			return errResp(resp.Error())
@@ -296,18 +293,18 @@ func (o *OCMProvider) LaunchCluster(clusterName string) (string, error) {
 	return resp.Body().ID(), nil
 }
 
-func (o *OCMProvider) FindRecycledCluster(originalVersion, cloudProvider, product string) string {
+func (o *OCMProvider) FindRecycledCluster(ctx context.Context, originalVersion, cloudProvider, product string) string {
 	version := semver.MustParse(strings.TrimPrefix(originalVersion, "openshift-"))
 	query := fmt.Sprintf("cloud_provider.id='%s' and properties.JobName='' and properties.JobID='' and product.id='%s' and properties.Status like '%s%%' and version.id like 'openshift-v%s%%'",
 		cloudProvider, product, "completed-", version.String())
 
 	log.Println(query)
 
-	listResponse, err := o.conn.ClustersMgmt().V1().Clusters().List().Search(query).Send()
+	listResponse, err := o.conn.ClustersMgmt().V1().Clusters().List().Search(query).SendContext(ctx)
 	if err == nil && listResponse.Total() > 0 {
 		log.Printf("We've found %d matching clusters to reuse", listResponse.Total())
 		recycledCluster := listResponse.Items().Slice()[rand.Intn(listResponse.Total())]
-		spiRecycledCluster, err := o.ocmToSPICluster(recycledCluster)
+		spiRecycledCluster, err := o.ocmToSPICluster(ctx, recycledCluster)
 		if err != nil {
 			log.Printf("Error converting recycled cluster to an SPI Cluster: %s", err.Error())
 			return ""
@@ -315,45 +312,50 @@ func (o *OCMProvider) FindRecycledCluster(originalVersion, cloudProvider, produc
 
 		if recycledCluster.ExpirationTimestamp().Before(time.Now().Add(4 * time.Hour)) {
 			// Let's just expire this cluster immediately
-			err = o.AddProperty(spiRecycledCluster, clusterproperties.JobName, "expiring")
+			err = o.AddPropertyContext(ctx, spiRecycledCluster, clusterproperties.JobName, "expiring")
 			if err != nil {
 				log.Printf("Error adding `expiring` to job name: %s", err.Error())
 				return ""
 			}
-			err = o.Expire(spiRecycledCluster.ID())
+			err = o.ExpireContext(ctx, spiRecycledCluster.ID())
 			if err != nil {
 				log.Printf("Error expiring cluster %s: %s", spiRecycledCluster.ID(), err.Error())
 				return ""
 			}
 			// Now try and grab a different existing cluster
-			return o.FindRecycledCluster(originalVersion, cloudProvider, product)
+			return o.FindRecycledCluster(ctx, originalVersion, cloudProvider, product)
 		}
 
-		err = o.AddProperty(spiRecycledCluster, clusterproperties.JobID, viper.GetString(config.JobID))
+		err = o.AddPropertyContext(ctx, spiRecycledCluster, clusterproperties.JobID, viper.GetString(config.JobID))
 		if err != nil {
 			log.Printf("Error adding property to cluster: %s", err.Error())
 			return ""
 		}
-		err = o.AddProperty(spiRecycledCluster, clusterproperties.JobName, viper.GetString(config.JobName))
+		err = o.AddPropertyContext(ctx, spiRecycledCluster, clusterproperties.JobName, viper.GetString(config.JobName))
 		if err != nil {
 			log.Printf("Error adding property to cluster: %s", err.Error())
 			return ""
 		}
 
-		time.Sleep(5 * time.Second)
+		select {
+		case <-ctx.Done():
+			log.Printf("Gave up recycling cluster %s: %v", spiRecycledCluster.ID(), ctx.Err())
+			return ""
+		case <-time.After(5 * time.Second):
+		}
 
-		spiRecycledCluster, err = o.GetCluster(spiRecycledCluster.ID())
+		spiRecycledCluster, err = o.GetClusterContext(ctx, spiRecycledCluster.ID())
 		if err != nil {
 			log.Printf("Error retrieving cluster during job ID check: %s", err.Error())
 			return ""
 		}
 		if jobID, ok := spiRecycledCluster.Properties()["JobID"]; ok && jobID != viper.GetString(config.JobID) {
 			log.Printf("Cluster already recycled by %s", spiRecycledCluster.Properties()["JobID"])
-			return o.FindRecycledCluster(originalVersion, cloudProvider, product)
+			return o.FindRecycledCluster(ctx, originalVersion, cloudProvider, product)
 		}
 
 		if recycledCluster.State() == v1.ClusterStateReady {
-			err = o.AddProperty(spiRecycledCluster, clusterproperties.Status, clusterproperties.StatusHealthy)
+			err = o.AddPropertyContext(ctx, spiRecycledCluster, clusterproperties.Status, clusterproperties.StatusHealthy)
 			if err != nil {
 				log.Printf("Error adding property to cluster: %s", err.Error())
 				return ""
@@ -366,9 +368,9 @@ func (o *OCMProvider) FindRecycledCluster(originalVersion, cloudProvider, produc
 
 			return recycledCluster.ID()
 		}
-		if recycledCluster.State() == "hibernating" && o.Resume(recycledCluster.ID()) {
+		if recycledCluster.State() == "hibernating" && o.ResumeContext(ctx, recycledCluster.ID()) {
 			log.Println("Resuming cluster to use...")
-			err = o.AddProperty(spiRecycledCluster, clusterproperties.Status, clusterproperties.StatusResuming)
+			err = o.AddPropertyContext(ctx, spiRecycledCluster, clusterproperties.Status, clusterproperties.StatusResuming)
 			if err != nil {
 				log.Printf("Error adding property to cluster: %s", err.Error())
 				return ""
@@ -389,7 +391,7 @@ func (o *OCMProvider) FindRecycledCluster(originalVersion, cloudProvider, produc
 
 // DetermineRegion will return the region provided by configs. This mainly wraps the random functionality for use
 // by the ROSA provider.
-func (o *OCMProvider) DetermineRegion(cloudProvider string) (string, error) {
+func (o *OCMProvider) DetermineRegion(ctx context.Context, cloudProvider string) (string, error) {
 	region := viper.GetString(config.CloudProvider.Region)
 
 	// If a region is set to "random", it will poll OCM for all the regions available
@@ -410,7 +412,7 @@ func (o *OCMProvider) DetermineRegion(cloudProvider string) (string, error) {
 				return "", err
 			}
 
-			response, err := o.conn.ClustersMgmt().V1().CloudProviders().CloudProvider(cloudProvider).AvailableRegions().Search().Body(awsCredentials).Send()
+			response, err := o.conn.ClustersMgmt().V1().CloudProviders().CloudProvider(cloudProvider).AvailableRegions().Search().Body(awsCredentials).SendContext(ctx)
 			if err != nil {
 				log.Printf("Error selecting region: %s", err.Error())
 				log.Println("Defaulting to us-east-1")
@@ -487,9 +489,9 @@ func ChooseRandomRegion(regions ...CloudRegion) (CloudRegion, bool) {
 	return nil, false
 }
 
-// DetermineMachineType will return the machine type provided by configs. This mainly wraps the random functionality for use by the OCM provider.
+// DetermineMachineTypeContext will return the machine type provided by configs. This mainly wraps the random functionality for use by the OCM provider.
 // Returns a random machine type if the machine type is set to "random" and a more narrowed random if a regex was specified.
-func (o *OCMProvider) DetermineMachineType(cloudProvider string) (string, error) {
+func (o *OCMProvider) DetermineMachineTypeContext(ctx context.Context, cloudProvider string) (string, error) {
 	computeMachineType, computeMachineTypeRegex := viper.GetString(ComputeMachineType), viper.GetString(ComputeMachineTypeRegex)
 	searchString, returnedType := "", ""
 
@@ -506,7 +508,7 @@ func (o *OCMProvider) DetermineMachineType(cloudProvider string) (string, error)
 		machinetypeClient := o.conn.ClustersMgmt().V1().MachineTypes().List().Search(searchString)
 		log.Printf("Randomly picking size for MachineTypes with search string %s", computeMachineTypeRegex)
 
-		machinetypes, err := machinetypeClient.Send()
+		machinetypes, err := machinetypeClient.SendContext(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -588,23 +590,23 @@ func (o *OCMProvider) GenerateProperties() (map[string]string, error) {
 	return properties, nil
 }
 
-// DeleteCluster requests the deletion of clusterID.
-func (o *OCMProvider) DeleteCluster(clusterID string) error {
+// DeleteClusterContext requests the deletion of clusterID.
+func (o *OCMProvider) DeleteClusterContext(ctx context.Context, clusterID string) error {
 	var deleteResp *v1.ClusterDeleteResponse
 	var resumeResp *v1.ClusterResumeResponse
 	var cluster *spi.Cluster
 	var err error
 
-	cluster, err = o.GetCluster(clusterID)
+	cluster, err = o.GetClusterContext(ctx, clusterID)
 	if err != nil {
 		return fmt.Errorf("error retrieving cluster for deletion: %v", err)
 	}
 
 	// If the cluster is hibernating according to OCM, wake it up
 	if cluster.State() == spi.ClusterStateHibernating {
-		if err = retryer().Do(func() error {
+		if err = retryWithContext(ctx, func() error {
 			var err error
-			resumeResp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Resume().Send()
+			resumeResp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Resume().SendContext(ctx)
 
 			if err != nil {
 				return fmt.Errorf("couldn't resume cluster '%s': %v", clusterID, err)
@@ -622,10 +624,10 @@ func (o *OCMProvider) DeleteCluster(clusterID string) error {
 		}
 	}
 
-	wait.PollImmediate(1*time.Minute, 15*time.Minute, func() (bool, error) {
+	wait.PollImmediateWithContext(ctx, 1*time.Minute, 15*time.Minute, func(ctx context.Context) (bool, error) {
 		// If the cluster state is anything but Hibernating or Ready, poll the state again
 		if cluster.State() == spi.ClusterStateHibernating || cluster.State() == spi.ClusterStateReady {
-			cluster, err = o.GetCluster(clusterID)
+			cluster, err = o.GetClusterContext(ctx, clusterID)
 			if err != nil {
 				log.Printf("error retrieving cluster for deletion: %v", err)
 				return false, nil
@@ -645,16 +647,16 @@ func (o *OCMProvider) DeleteCluster(clusterID string) error {
 		return false, nil
 	})
 
-	err = o.AddProperty(cluster, clusterproperties.Status, clusterproperties.StatusUninstalling)
+	err = o.AddPropertyContext(ctx, cluster, clusterproperties.Status, clusterproperties.StatusUninstalling)
 	if err != nil {
 		return fmt.Errorf("error adding uninstalling status to cluster: %v", err)
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		deleteResp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
 			Delete().
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			return fmt.Errorf("couldn't delete cluster '%s': %v", clusterID, err)
@@ -676,12 +678,12 @@ func (o *OCMProvider) DeleteCluster(clusterID string) error {
 	return nil
 }
 
-// ScaleCluster will grow or shink the cluster to the desired number of compute nodes.
-func (o *OCMProvider) ScaleCluster(clusterID string, numComputeNodes int) error {
+// ScaleClusterContext will grow or shink the cluster to the desired number of compute nodes.
+func (o *OCMProvider) ScaleClusterContext(ctx context.Context, clusterID string, numComputeNodes int) error {
 	var resp *v1.ClusterUpdateResponse
 
 	// Get the current state of the cluster
-	ocmCluster, err := o.getOCMCluster(clusterID)
+	ocmCluster, err := o.getOCMCluster(ctx, clusterID)
 	if err != nil {
 		return err
 	}
@@ -699,11 +701,11 @@ func (o *OCMProvider) ScaleCluster(clusterID string, numComputeNodes int) error
 		return fmt.Errorf("error while building scaled cluster object: %v", err)
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Update().
 			Body(scaledCluster).
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			err = fmt.Errorf("couldn't update cluster '%s': %v", clusterID, err)
@@ -716,7 +718,7 @@ func (o *OCMProvider) ScaleCluster(clusterID string, numComputeNodes int) error
 			return errResp(resp.Error())
 		}
 
-		o.updateClusterCache(clusterID, resp.Body())
+		o.updateClusterCache(ctx, clusterID, resp.Body())
 
 		return nil
 	})
@@ -734,8 +736,8 @@ func (o *OCMProvider) ScaleCluster(clusterID string, numComputeNodes int) error
 	return nil
 }
 
-// ListClusters returns a list of clusters filtered on key/value pairs
-func (o *OCMProvider) ListClusters(query string) ([]*spi.Cluster, error) {
+// ListClustersContext returns a list of clusters filtered on key/value pairs
+func (o *OCMProvider) ListClustersContext(ctx context.Context, query string) ([]*spi.Cluster, error) {
 	var clusters []*spi.Cluster
 
 	totalItems := math.MaxInt64
@@ -745,7 +747,7 @@ func (o *OCMProvider) ListClusters(query string) ([]*spi.Cluster, error) {
 	for len(clusters) < totalItems || !emptyPage {
 		clusterListRequest := o.conn.ClustersMgmt().V1().Clusters().List()
 
-		response, err := clusterListRequest.Search(query).Page(page).Send()
+		response, err := clusterListRequest.Search(query).Page(page).SendContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -759,7 +761,7 @@ func (o *OCMProvider) ListClusters(query string) ([]*spi.Cluster, error) {
 		}
 
 		for _, cluster := range response.Items().Slice() {
-			spiCluster, err := o.ocmToSPICluster(cluster)
+			spiCluster, err := o.ocmToSPICluster(ctx, cluster)
 			if err != nil {
 				return nil, err
 			}
@@ -772,14 +774,14 @@ func (o *OCMProvider) ListClusters(query string) ([]*spi.Cluster, error) {
 	return clusters, nil
 }
 
-// GetCluster returns a cluster from OCM.
-func (o *OCMProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
-	ocmCluster, err := o.getOCMCluster(clusterID)
+// GetClusterContext returns a cluster from OCM.
+func (o *OCMProvider) GetClusterContext(ctx context.Context, clusterID string) (*spi.Cluster, error) {
+	ocmCluster, err := o.getOCMCluster(ctx, clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	cluster, err := o.ocmToSPICluster(ocmCluster)
+	cluster, err := o.ocmToSPICluster(ctx, ocmCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -788,14 +790,48 @@ func (o *OCMProvider) GetCluster(clusterID string) (*spi.Cluster, error) {
 	return cluster, nil
 }
 
-func (o *OCMProvider) getOCMCluster(clusterID string) (*v1.Cluster, error) {
+// AvailabilityZonesContext returns the availability zones the cluster's compute nodes are spread across. For
+// clusters installed into existing subnets, the zones are looked up from the subnets.
+func (o *OCMProvider) AvailabilityZonesContext(ctx context.Context, clusterID string) ([]string, error) {
+	ocmCluster, err := o.getOCMCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if availabilityZones := ocmCluster.Nodes().AvailabilityZones(); len(availabilityZones) > 0 {
+		return availabilityZones, nil
+	}
+
+	subnetIDs := ocmCluster.AWS().SubnetIDs()
+	if len(subnetIDs) == 0 {
+		return nil, nil
+	}
+	cloudProviderData, err := v1.NewCloudProviderData().
+		AWS(v1.NewAWS().
+			AccountID(viper.GetString(config.AWSAccount)).
+			AccessKeyID(viper.GetString(config.AWSAccessKey)).
+			SecretAccessKey(viper.GetString(config.AWSSecretAccessKey)).
+			SubnetIDs(subnetIDs...)).
+		Region(v1.NewCloudRegion().ID(ocmCluster.Region().ID())).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("error building AWS cloud provider data for retrieving Availability Zones: %v", err)
+	}
+	subnetworks, err := o.GetSubnetworks(ctx, cloudProviderData)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving AWS subnetworks: %v", err)
+	}
+	return GetAvailabilityZones(subnetworks, subnetIDs), nil
+}
+
+func (o *OCMProvider) getOCMCluster(ctx context.Context, clusterID string) (*v1.Cluster, error) {
 	var resp *v1.ClusterGetResponse
 
-	err := retryer().Do(func() error {
+	err := retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
 			Get().
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			err = fmt.Errorf("couldn't retrieve cluster '%s': %v", clusterID, err)
@@ -820,8 +856,8 @@ func (o *OCMProvider) getOCMCluster(clusterID string) (*v1.Cluster, error) {
 	return resp.Body(), nil
 }
 
-// ClusterKubeconfig returns the kubeconfig for the given cluster ID.
-func (o *OCMProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
+// ClusterKubeconfigContext returns the kubeconfig for the given cluster ID.
+func (o *OCMProvider) ClusterKubeconfigContext(ctx context.Context, clusterID string) ([]byte, error) {
 	// Override with a local kubeconfig if defined
 	localKubeConfig := viper.GetString(config.Kubeconfig.Path)
 	if len(localKubeConfig) > 0 {
@@ -841,12 +877,12 @@ func (o *OCMProvider) ClusterKubeconfig(clusterID string) ([]byte, error) {
 
 	var resp *v1.CredentialsGetResponse
 
-	err := retryer().Do(func() error {
+	err := retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
 			Credentials().
 			Get().
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			log.Printf("couldn't get credentials: %v", err)
@@ -884,9 +920,9 @@ func getLocalKubeConfig(path string) ([]byte, error) {
 	return []byte(f), nil
 }
 
-// InstallAddons loops through the addons list in the config
+// InstallAddonsContext loops through the addons list in the config
 // and performs the CRUD operation to trigger addon installation
-func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, addonParams map[spi.AddOnID]spi.AddOnParams) (num int, err error) {
+func (o *OCMProvider) InstallAddonsContext(ctx context.Context, clusterID string, addonIDs []spi.AddOnID, addonParams map[spi.AddOnID]spi.AddOnParams) (num int, err error) {
 	num = 0
 	addonsClient := o.conn.ClustersMgmt().V1().Addons()
 	clusterClient := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID)
@@ -894,9 +930,9 @@ func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, ad
 		var addonResp *v1.AddOnGetResponse
 		params := addonParams[addonID]
 
-		err = retryer().Do(func() error {
+		err = retryWithContext(ctx, func() error {
 			var err error
-			addonResp, err = addonsClient.Addon(addonID).Get().Send()
+			addonResp, err = addonsClient.Addon(addonID).Get().SendContext(ctx)
 
 			if err != nil {
 				return err
@@ -916,7 +952,7 @@ func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, ad
 
 		alreadyInstalled := false
 
-		cluster, err := o.GetCluster(clusterID)
+		cluster, err := o.GetClusterContext(ctx, clusterID)
 		if err != nil {
 			return 0, fmt.Errorf("error getting current cluster state when trying to install addon %s", addonID)
 		}
@@ -951,9 +987,9 @@ func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, ad
 
 			var aoar *v1.AddOnInstallationsAddResponse
 
-			err = retryer().Do(func() error {
+			err = retryWithContext(ctx, func() error {
 				var err error
-				aoar, err = clusterClient.Addons().Add().Body(addonInstallation).Send()
+				aoar, err = clusterClient.Addons().Add().Body(addonInstallation).SendContext(ctx)
 				if err != nil {
 					log.Printf("couldn't install addons: %v", err)
 					return err
@@ -965,7 +1001,7 @@ func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, ad
 					return err
 				}
 
-				o.updateClusterCache(clusterID, aoar.Body().Cluster())
+				o.updateClusterCache(ctx, clusterID, aoar.Body().Cluster())
 
 				return nil
 			})
@@ -983,7 +1019,7 @@ func (o *OCMProvider) InstallAddons(clusterID string, addonIDs []spi.AddOnID, ad
 	return num, nil
 }
 
-func (o *OCMProvider) ocmToSPICluster(ocmCluster *v1.Cluster) (*spi.Cluster, error) {
+func (o *OCMProvider) ocmToSPICluster(ctx context.Context, ocmCluster *v1.Cluster) (*spi.Cluster, error) {
 	var err error
 	var resp *v1.ClusterGetResponse
 
@@ -1018,11 +1054,11 @@ func (o *OCMProvider) ocmToSPICluster(ocmCluster *v1.Cluster) (*spi.Cluster, err
 
 	if !viper.GetBool(config.Addons.SkipAddonList) {
 		var addonsResp *v1.AddOnInstallationsListResponse
-		err = retryer().Do(func() error {
+		err = retryWithContext(ctx, func() error {
 			var err error
 			addonsResp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(ocmCluster.ID()).Addons().
 				List().
-				Send()
+				SendContext(ctx)
 
 			if err != nil {
 				err = fmt.Errorf("couldn't retrieve addons for cluster '%s': %v", ocmCluster.ID(), err)
@@ -1083,17 +1119,17 @@ func ocmStateToInternalState(state v1.ClusterState) spi.ClusterState {
 	}
 }
 
-// ExtendExpiry extends the expiration time of an existing cluster
-func (o *OCMProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+// ExtendExpiryContext extends the expiration time of an existing cluster
+func (o *OCMProvider) ExtendExpiryContext(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error {
 	var resp *v1.ClusterUpdateResponse
 
 	// Get the current state of the cluster
-	ocmCluster, err := o.getOCMCluster(clusterID)
+	ocmCluster, err := o.getOCMCluster(ctx, clusterID)
 	if err != nil {
 		return err
 	}
 
-	cluster, err := o.ocmToSPICluster(ocmCluster)
+	cluster, err := o.ocmToSPICluster(ctx, ocmCluster)
 	if err != nil {
 		return err
 	}
@@ -1120,11 +1156,11 @@ func (o *OCMProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint6
 		return fmt.Errorf("error while building updated expiration time cluster object: %v", err)
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Update().
 			Body(extendexpiryCluster).
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			err = fmt.Errorf("couldn't update cluster '%s': %v", clusterID, err)
@@ -1137,7 +1173,7 @@ func (o *OCMProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint6
 			return errResp(resp.Error())
 		}
 
-		o.updateClusterCache(clusterID, resp.Body())
+		o.updateClusterCache(ctx, clusterID, resp.Body())
 
 		return nil
 	})
@@ -1155,8 +1191,8 @@ func (o *OCMProvider) ExtendExpiry(clusterID string, hours uint64, minutes uint6
 	return nil
 }
 
-// Expire sets the expiration time of an existing cluster to the current time
-func (o *OCMProvider) Expire(clusterID string) error {
+// ExpireContext sets the expiration time of an existing cluster to the current time
+func (o *OCMProvider) ExpireContext(ctx context.Context, clusterID string) error {
 	var resp *v1.ClusterUpdateResponse
 
 	now := time.Now().Add(1 * time.Minute)
@@ -1166,11 +1202,11 @@ func (o *OCMProvider) Expire(clusterID string) error {
 		return fmt.Errorf("error while building updated expiration time cluster object: %v", err)
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).Update().
 			Body(extendexpiryCluster).
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			err = fmt.Errorf("couldn't update cluster '%s': %v", clusterID, err)
@@ -1183,7 +1219,7 @@ func (o *OCMProvider) Expire(clusterID string) error {
 			return errResp(resp.Error())
 		}
 
-		o.updateClusterCache(clusterID, resp.Body())
+		o.updateClusterCache(ctx, clusterID, resp.Body())
 
 		return nil
 	})
@@ -1201,21 +1237,21 @@ func (o *OCMProvider) Expire(clusterID string) error {
 	return nil
 }
 
-// AddProperty adds a new property to the properties field of an existing cluster
-func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string) error {
+// AddPropertyContext adds a new property to the properties field of an existing cluster
+func (o *OCMProvider) AddPropertyContext(ctx context.Context, cluster *spi.Cluster, tag string, value string) error {
 	var resp *v1.ClusterUpdateResponse
 
-	clusterproperties := cluster.Properties()
+	properties := cluster.Properties()
 
-	// Apparently, if cluster properties are empty in OCM, the clusterproperties are nil, In this case, we'll just make our own
+	// Apparently, if cluster properties are empty in OCM, the properties are nil, In this case, we'll just make our own
 	// properties map.
-	if clusterproperties == nil {
-		clusterproperties = map[string]string{}
+	if properties == nil {
+		properties = map[string]string{}
 	}
 
-	clusterproperties[tag] = value
+	clusterproperties.Set(properties, tag, value, time.Now())
 
-	modifiedCluster, err := v1.NewCluster().Properties(clusterproperties).Build()
+	modifiedCluster, err := v1.NewCluster().Properties(properties).Build()
 	if err != nil {
 		return fmt.Errorf("error while building updated modified cluster object with new property: %v", err)
 	}
@@ -1230,11 +1266,11 @@ func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string
 		log.Println(data)
 	}
 
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).Update().
 			Body(modifiedCluster).
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			err = fmt.Errorf("couldn't update cluster '%s': %v", cluster.ID(), err)
@@ -1259,21 +1295,21 @@ func (o *OCMProvider) AddProperty(cluster *spi.Cluster, tag string, value string
 	}
 
 	// We need to update the cache post-update
-	o.updateClusterCache(cluster.ID(), resp.Body())
+	o.updateClusterCache(ctx, cluster.ID(), resp.Body())
 
 	log.Printf("Successfully added property[%s] - %s \n", tag, resp.Body().Properties()[tag])
 
 	return nil
 }
 
-// Upgrade initiates a cluster upgrade to the given version
-func (o *OCMProvider) Upgrade(clusterID string, version string, t time.Time) error {
+// UpgradeContext initiates a cluster upgrade to the given version
+func (o *OCMProvider) UpgradeContext(ctx context.Context, clusterID string, version string, t time.Time) error {
 	policy, err := v1.NewUpgradePolicy().Version(version).NextRun(t).ScheduleType("manual").Build()
 	if err != nil {
 		return err
 	}
 
-	addResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().Add().Body(policy).SendContext(context.TODO())
+	addResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().Add().Body(policy).SendContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -1286,9 +1322,9 @@ func (o *OCMProvider) Upgrade(clusterID string, version string, t time.Time) err
 	return nil
 }
 
-// GetUpgradePolicyID gets the first upgrade policy from the top
-func (o *OCMProvider) GetUpgradePolicyID(clusterID string) (string, error) {
-	listResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().List().SendContext(context.TODO())
+// GetUpgradePolicyIDContext gets the first upgrade policy from the top
+func (o *OCMProvider) GetUpgradePolicyIDContext(ctx context.Context, clusterID string) (string, error) {
+	listResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().List().SendContext(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -1311,14 +1347,14 @@ func (o *OCMProvider) GetUpgradePolicyID(clusterID string) (string, error) {
 	return policyID, nil
 }
 
-// UpdateSchedule updates the existing upgrade policy for re-scheduling
-func (o *OCMProvider) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+// UpdateScheduleContext updates the existing upgrade policy for re-scheduling
+func (o *OCMProvider) UpdateScheduleContext(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error {
 	policyBody, err := v1.NewUpgradePolicy().NextRun(t).Build()
 	if err != nil {
 		return err
 	}
 
-	updateResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().UpgradePolicy(policyID).Update().Body(policyBody).SendContext(context.TODO())
+	updateResp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).UpgradePolicies().UpgradePolicy(policyID).Update().Body(policyBody).SendContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -1331,9 +1367,9 @@ func (o *OCMProvider) UpdateSchedule(clusterID string, version string, t time.Ti
 	return nil
 }
 
-// Resume resumes a cluster via OCM
-func (o *OCMProvider) Resume(id string) bool {
-	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(id).Resume().Send()
+// ResumeContext resumes a cluster via OCM
+func (o *OCMProvider) ResumeContext(ctx context.Context, id string) bool {
+	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(id).Resume().SendContext(ctx)
 	if err != nil {
 		err = fmt.Errorf("couldn't resume cluster '%s': %v", id, err)
 		log.Printf("%v", err)
@@ -1348,9 +1384,9 @@ func (o *OCMProvider) Resume(id string) bool {
 	return true
 }
 
-// Hibernate resumes a cluster via OCM
-func (o *OCMProvider) Hibernate(id string) bool {
-	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(id).Hibernate().Send()
+// HibernateContext resumes a cluster via OCM
+func (o *OCMProvider) HibernateContext(ctx context.Context, id string) bool {
+	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(id).Hibernate().SendContext(ctx)
 	if err != nil {
 		err = fmt.Errorf("couldn't hibernate cluster '%s': %v", id, err)
 		log.Printf("%v", err)
@@ -1366,8 +1402,8 @@ func (o *OCMProvider) Hibernate(id string) bool {
 }
 
 // This assumes cluster is a resp.Body() response from an OCM update
-func (o *OCMProvider) updateClusterCache(id string, cluster *v1.Cluster) error {
-	c, err := o.ocmToSPICluster(cluster)
+func (o *OCMProvider) updateClusterCache(ctx context.Context, id string, cluster *v1.Cluster) error {
+	c, err := o.ocmToSPICluster(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -1375,13 +1411,13 @@ func (o *OCMProvider) updateClusterCache(id string, cluster *v1.Cluster) error {
 	return nil
 }
 
-func (o *OCMProvider) GetSubnetworks(cloudProviderData *v1.CloudProviderData) (subnetworks []*v1.Subnetwork, err error) {
+func (o *OCMProvider) GetSubnetworks(ctx context.Context, cloudProviderData *v1.CloudProviderData) (subnetworks []*v1.Subnetwork, err error) {
 	if viper.GetBool(CCS) && viper.GetString(config.CloudProvider.CloudProviderID) == "aws" {
 		response, err := o.conn.ClustersMgmt().V1().AWSInquiries().Vpcs().Search().
 			Page(1).
 			Size(-1).
 			Body(cloudProviderData).
-			Send()
+			SendContext(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -1,11 +1,17 @@
 package ocmprovider_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osde2e/pkg/common/clusterproperties"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/spi"
 	"gotest.tools/v3/assert"
 )
 
@@ -88,3 +94,449 @@ func TestGetAvailabilityZones(t *testing.T) {
 	result = ocmprovider.GetAvailabilityZones(subnetworksInput, configSubnetsInput)
 	assert.DeepEqual(t, result, expected)
 }
+
+func readyCluster(name string) *v1.ClusterBuilder {
+	return v1.NewCluster().
+		Name(name).
+		State(v1.ClusterStateReady).
+		Version(v1.NewVersion().ID("openshift-v4.11.12")).
+		CloudProvider(v1.NewCloudProvider().ID("aws")).
+		Region(v1.NewCloudRegion().ID("us-east-1")).
+		Nodes(v1.NewClusterNodes().Compute(3))
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	ctx := context.Background()
+	srv, provider := newFakeProvider(t)
+	setConfig(t, map[string]interface{}{
+		config.Cluster.Version:               "openshift-v4.11.12",
+		config.CloudProvider.CloudProviderID: "aws",
+		config.CloudProvider.Region:          "us-east-1",
+		config.Cluster.NumWorkerNodes:        4,
+		config.Cluster.ExpiryInMinutes:       60,
+		config.Kubeconfig.Path:               "",
+		config.Kubeconfig.Contents:           "",
+		ocmprovider.UserOverride:             "osde2e-tester",
+	})
+
+	clusterID, err := provider.LaunchClusterContext(ctx, "osde2e-lcycl")
+	assert.NilError(t, err)
+
+	for name, expected := range map[string]bool{"osde2e-lcycl": false, "osde2e-other": true} {
+		valid, err := provider.IsValidClusterNameContext(ctx, name)
+		assert.NilError(t, err)
+		assert.Equal(t, valid, expected, "cluster name %s", name)
+	}
+
+	steps := []struct {
+		Name          string
+		Action        func() bool
+		ExpectedState spi.ClusterState
+	}{
+		{
+			Name:          "installing",
+			ExpectedState: spi.ClusterStateInstalling,
+		},
+		{
+			Name:          "ready",
+			ExpectedState: spi.ClusterStateReady,
+		},
+		{
+			Name:          "hibernate",
+			Action:        func() bool { return provider.HibernateContext(ctx, clusterID) },
+			ExpectedState: spi.ClusterStateHibernating,
+		},
+		{
+			Name:          "resume",
+			Action:        func() bool { return provider.ResumeContext(ctx, clusterID) },
+			ExpectedState: spi.ClusterStateReady,
+		},
+	}
+
+	for _, step := range steps {
+		if step.Action != nil && !step.Action() {
+			t.Fatalf("%s: action failed", step.Name)
+		}
+
+		cluster, err := provider.GetClusterContext(ctx, clusterID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error getting cluster: %v", step.Name, err)
+		}
+		if cluster.State() != step.ExpectedState {
+			t.Errorf("%s: expected state %s, got %s", step.Name, step.ExpectedState, cluster.State())
+		}
+	}
+
+	cluster, err := provider.GetClusterContext(ctx, clusterID)
+	assert.NilError(t, err)
+	assert.Equal(t, cluster.Name(), "osde2e-lcycl")
+	assert.Equal(t, cluster.Version(), "openshift-v4.11.12")
+	assert.Equal(t, cluster.CloudProvider(), "aws")
+	assert.Equal(t, cluster.Region(), "us-east-1")
+	assert.Equal(t, cluster.NumComputeNodes(), 4)
+	assert.Equal(t, cluster.Properties()[clusterproperties.OwnedBy], "osde2e-tester")
+	assert.Equal(t, cluster.Properties()[clusterproperties.Status], clusterproperties.StatusProvisioning)
+	assert.Assert(t, cluster.ExpirationTimestamp().After(time.Now().Add(59*time.Minute)))
+
+	kubeconfig, err := provider.ClusterKubeconfigContext(ctx, clusterID)
+	assert.NilError(t, err)
+	assert.Equal(t, string(kubeconfig), srv.Kubeconfig)
+
+	clusters, err := provider.ListClustersContext(ctx, "properties.OwnedBy = 'osde2e-tester'")
+	assert.NilError(t, err)
+	assert.Equal(t, len(clusters), 1)
+	assert.Equal(t, clusters[0].ID(), clusterID)
+}
+
+func TestListClusters(t *testing.T) {
+	srv, provider := newFakeProvider(t)
+	for i := 0; i < 150; i++ {
+		builder := readyCluster(fmt.Sprintf("osde2e-%05d", i))
+		if i%3 == 0 {
+			builder.CloudProvider(v1.NewCloudProvider().ID("gcp"))
+		}
+		_, err := srv.AddCluster(builder)
+		assert.NilError(t, err)
+	}
+
+	tests := []struct {
+		Name     string
+		Query    string
+		Expected int
+	}{
+		{
+			Name:     "everything across pages",
+			Query:    "",
+			Expected: 150,
+		},
+		{
+			Name:     "equality",
+			Query:    "cloud_provider.id = 'gcp'",
+			Expected: 50,
+		},
+		{
+			Name:     "like and conjunction",
+			Query:    "cloud_provider.id='aws' and name like 'osde2e-0001%'",
+			Expected: 7,
+		},
+		{
+			Name:     "no matches",
+			Query:    "version.id like 'openshift-v4.12%'",
+			Expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			clusters, err := provider.ListClustersContext(context.Background(), test.Query)
+			assert.NilError(t, err)
+			assert.Equal(t, len(clusters), test.Expected)
+		})
+	}
+}
+
+func TestDeleteCluster(t *testing.T) {
+	tests := []struct {
+		Name  string
+		State v1.ClusterState
+	}{
+		{
+			Name:  "ready cluster",
+			State: v1.ClusterStateReady,
+		},
+		{
+			Name:  "hibernating cluster is resumed first",
+			State: v1.ClusterStateHibernating,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			clusterID, err := srv.AddCluster(readyCluster("osde2e-delet").State(test.State))
+			assert.NilError(t, err)
+
+			assert.NilError(t, provider.DeleteClusterContext(context.Background(), clusterID))
+
+			cluster, ok := srv.Cluster(clusterID)
+			assert.Assert(t, ok)
+			assert.Equal(t, cluster.State(), v1.ClusterStateUninstalling)
+			assert.Equal(t, cluster.Properties()[clusterproperties.Status], clusterproperties.StatusUninstalling)
+		})
+	}
+}
+
+func TestClusterUpdates(t *testing.T) {
+	expiration := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name    string
+		Cluster *v1.ClusterBuilder
+		Update  func(provider *ocmprovider.OCMProvider, clusterID string) error
+		Check   func(t *testing.T, cluster *v1.Cluster)
+	}{
+		{
+			Name:    "extend expiry",
+			Cluster: readyCluster("osde2e-extnd").ExpirationTimestamp(expiration),
+			Update: func(provider *ocmprovider.OCMProvider, clusterID string) error {
+				return provider.ExtendExpiryContext(context.Background(), clusterID, 1, 30, 15)
+			},
+			Check: func(t *testing.T, cluster *v1.Cluster) {
+				assert.Assert(t, cluster.ExpirationTimestamp().Equal(expiration.Add(time.Hour+30*time.Minute+15*time.Second)))
+			},
+		},
+		{
+			Name:    "extend expiry without an expiration",
+			Cluster: readyCluster("osde2e-noexp"),
+			Update: func(provider *ocmprovider.OCMProvider, clusterID string) error {
+				return provider.ExtendExpiryContext(context.Background(), clusterID, 1, 0, 0)
+			},
+			Check: func(t *testing.T, cluster *v1.Cluster) {
+				_, ok := cluster.GetExpirationTimestamp()
+				assert.Assert(t, !ok)
+			},
+		},
+		{
+			Name:    "expire",
+			Cluster: readyCluster("osde2e-expir").ExpirationTimestamp(expiration),
+			Update: func(provider *ocmprovider.OCMProvider, clusterID string) error {
+				return provider.ExpireContext(context.Background(), clusterID)
+			},
+			Check: func(t *testing.T, cluster *v1.Cluster) {
+				assert.Assert(t, cluster.ExpirationTimestamp().Before(time.Now().Add(2*time.Minute)))
+			},
+		},
+		{
+			Name:    "add property",
+			Cluster: readyCluster("osde2e-propr").Properties(map[string]string{clusterproperties.OwnedBy: "osde2e-tester"}),
+			Update: func(provider *ocmprovider.OCMProvider, clusterID string) error {
+				cluster, err := provider.GetClusterContext(context.Background(), clusterID)
+				if err != nil {
+					return err
+				}
+				return provider.AddPropertyContext(context.Background(), cluster, clusterproperties.Status, clusterproperties.StatusHealthy)
+			},
+			Check: func(t *testing.T, cluster *v1.Cluster) {
+				properties := cluster.Properties()
+				updated, ok := clusterproperties.StatusUpdatedAt(properties)
+				assert.Assert(t, ok && time.Since(updated) < time.Minute, "status update not stamped: %v", properties)
+				delete(properties, clusterproperties.StatusUpdated)
+				assert.DeepEqual(t, properties, map[string]string{
+					clusterproperties.OwnedBy: "osde2e-tester",
+					clusterproperties.Status:  clusterproperties.StatusHealthy,
+				})
+			},
+		},
+		{
+			Name:    "scale",
+			Cluster: readyCluster("osde2e-scale"),
+			Update: func(provider *ocmprovider.OCMProvider, clusterID string) error {
+				return provider.ScaleClusterContext(context.Background(), clusterID, 6)
+			},
+			Check: func(t *testing.T, cluster *v1.Cluster) {
+				assert.Equal(t, cluster.Nodes().Compute(), 6)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			setConfig(t, map[string]interface{}{config.JobName: ""})
+
+			clusterID, err := srv.AddCluster(test.Cluster)
+			assert.NilError(t, err)
+
+			assert.NilError(t, test.Update(provider, clusterID))
+
+			cluster, ok := srv.Cluster(clusterID)
+			assert.Assert(t, ok)
+			test.Check(t, cluster)
+		})
+	}
+}
+
+func TestInstallAddons(t *testing.T) {
+	tests := []struct {
+		Name              string
+		PreInstalled      []spi.AddOnID
+		AddonIDs          []spi.AddOnID
+		ExpectedNum       int
+		ExpectedInstalled []string
+	}{
+		{
+			Name:              "enabled addon",
+			AddonIDs:          []spi.AddOnID{"enabled-addon"},
+			ExpectedNum:       1,
+			ExpectedInstalled: []string{"enabled-addon"},
+		},
+		{
+			Name:              "disabled addon",
+			AddonIDs:          []spi.AddOnID{"disabled-addon"},
+			ExpectedNum:       0,
+			ExpectedInstalled: []string{},
+		},
+		{
+			Name:              "already installed addon",
+			PreInstalled:      []spi.AddOnID{"enabled-addon"},
+			AddonIDs:          []spi.AddOnID{"enabled-addon", "other-addon"},
+			ExpectedNum:       1,
+			ExpectedInstalled: []string{"enabled-addon", "other-addon"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			srv.AddAddon("enabled-addon", true)
+			srv.AddAddon("other-addon", true)
+			srv.AddAddon("disabled-addon", false)
+
+			clusterID, err := srv.AddCluster(readyCluster("osde2e-addon"))
+			assert.NilError(t, err)
+
+			if len(test.PreInstalled) > 0 {
+				_, err := provider.InstallAddonsContext(context.Background(), clusterID, test.PreInstalled, nil)
+				assert.NilError(t, err)
+			}
+
+			num, err := provider.InstallAddonsContext(context.Background(), clusterID, test.AddonIDs, map[spi.AddOnID]spi.AddOnParams{
+				"enabled-addon": {"param": "value"},
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, num, test.ExpectedNum)
+			assert.DeepEqual(t, srv.InstalledAddons(clusterID), test.ExpectedInstalled)
+		})
+	}
+}
+
+func TestUpgradePolicies(t *testing.T) {
+	ctx := context.Background()
+	srv, provider := newFakeProvider(t)
+	clusterID, err := srv.AddCluster(readyCluster("osde2e-upgrd"))
+	assert.NilError(t, err)
+
+	policyID, err := provider.GetUpgradePolicyIDContext(ctx, clusterID)
+	assert.NilError(t, err)
+	assert.Equal(t, policyID, "")
+
+	firstRun := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Second)
+	assert.NilError(t, provider.UpgradeContext(ctx, clusterID, "openshift-v4.12.1", firstRun))
+
+	policyID, err = provider.GetUpgradePolicyIDContext(ctx, clusterID)
+	assert.NilError(t, err)
+	assert.Assert(t, policyID != "")
+
+	secondRun := firstRun.Add(time.Hour)
+	assert.NilError(t, provider.UpdateScheduleContext(ctx, clusterID, "openshift-v4.12.1", secondRun, policyID))
+
+	policies := srv.UpgradePolicies(clusterID)
+	assert.Equal(t, len(policies), 1)
+	assert.Equal(t, policies[0].ID(), policyID)
+	assert.Equal(t, policies[0].Version(), "openshift-v4.12.1")
+	assert.Equal(t, policies[0].ScheduleType(), "manual")
+	assert.Assert(t, policies[0].NextRun().Equal(secondRun))
+}
+
+func TestFindRecycledCluster(t *testing.T) {
+	completed := map[string]string{clusterproperties.Status: clusterproperties.StatusCompletedPassing}
+	farExpiration := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		Name           string
+		Cluster        *v1.ClusterBuilder
+		ExpectRecycled bool
+		ExpectedState  v1.ClusterState
+		ExpectedStatus string
+	}{
+		{
+			Name:           "ready cluster",
+			Cluster:        readyCluster("osde2e-ready").Properties(completed).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: true,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusHealthy,
+		},
+		{
+			Name:           "hibernating cluster is resumed",
+			Cluster:        readyCluster("osde2e-hiber").State(v1.ClusterStateHibernating).Properties(completed).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: true,
+			ExpectedState:  v1.ClusterStateResuming,
+			ExpectedStatus: clusterproperties.StatusResuming,
+		},
+		{
+			Name:           "cluster about to expire is expired instead",
+			Cluster:        readyCluster("osde2e-expir").Properties(completed).ExpirationTimestamp(time.Now().Add(time.Hour)),
+			ExpectRecycled: false,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusCompletedPassing,
+		},
+		{
+			Name:           "cluster with a different version",
+			Cluster:        readyCluster("osde2e-versn").Version(v1.NewVersion().ID("openshift-v4.10.40")).Properties(completed).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: false,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusCompletedPassing,
+		},
+		{
+			Name:           "cluster on another cloud provider",
+			Cluster:        readyCluster("osde2e-cloud").CloudProvider(v1.NewCloudProvider().ID("gcp")).Properties(completed).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: false,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusCompletedPassing,
+		},
+		{
+			Name: "cluster claimed by another job",
+			Cluster: readyCluster("osde2e-claim").Properties(map[string]string{
+				clusterproperties.Status: clusterproperties.StatusCompletedPassing,
+				clusterproperties.JobID:  "999",
+			}).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: false,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusCompletedPassing,
+		},
+		{
+			Name:           "cluster still in use",
+			Cluster:        readyCluster("osde2e-inuse").Properties(map[string]string{clusterproperties.Status: clusterproperties.StatusHealthy}).ExpirationTimestamp(farExpiration),
+			ExpectRecycled: false,
+			ExpectedState:  v1.ClusterStateReady,
+			ExpectedStatus: clusterproperties.StatusHealthy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			setConfig(t, map[string]interface{}{
+				config.JobName:        "",
+				config.Cluster.Reused: viper.GetBool(config.Cluster.Reused),
+			})
+
+			clusterID, err := srv.AddCluster(test.Cluster)
+			assert.NilError(t, err)
+
+			recycledID := provider.FindRecycledCluster(context.Background(), "openshift-v4.11.12", "aws", "osd")
+			if test.ExpectRecycled {
+				assert.Equal(t, recycledID, clusterID)
+			} else {
+				assert.Equal(t, recycledID, "")
+			}
+
+			cluster, ok := srv.Cluster(clusterID)
+			assert.Assert(t, ok)
+			assert.Equal(t, cluster.State(), test.ExpectedState)
+			assert.Equal(t, cluster.Properties()[clusterproperties.Status], test.ExpectedStatus)
+		})
+	}
+}
+
+func TestRetriesFailedRequests(t *testing.T) {
+	srv, provider := newFakeProvider(t)
+	clusterID, err := srv.AddCluster(readyCluster("osde2e-retry"))
+	assert.NilError(t, err)
+
+	srv.FailRequests(1, 503)
+
+	cluster, err := provider.GetClusterContext(context.Background(), clusterID)
+	assert.NilError(t, err)
+	assert.Equal(t, cluster.ID(), clusterID)
+}
@@ -0,0 +1,49 @@
+package fakeocm
+
+import (
+	"strings"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/osde2e/pkg/common/spi"
+)
+
+// parseSearch builds a cluster filter from a search string, using the same query language the
+// other providers evaluate against spi clusters.
+func parseSearch(search string) (func(*v1.Cluster) bool, error) {
+	query, err := spi.ParseClusterQuery(search)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(cluster *v1.Cluster) bool {
+		return query.MatchesFields(func(key string) string {
+			return clusterField(cluster, key)
+		})
+	}, nil
+}
+
+// clusterField returns the value of a searchable cluster field.
+func clusterField(cluster *v1.Cluster, key string) string {
+	if strings.HasPrefix(key, "properties.") {
+		return cluster.Properties()[strings.TrimPrefix(key, "properties.")]
+	}
+
+	switch key {
+	case "id":
+		return cluster.ID()
+	case "name":
+		return cluster.Name()
+	case "state":
+		return string(cluster.State())
+	case "cloud_provider.id":
+		return cluster.CloudProvider().ID()
+	case "region.id":
+		return cluster.Region().ID()
+	case "product.id":
+		return cluster.Product().ID()
+	case "version.id":
+		return cluster.Version().ID()
+	}
+	return ""
+}
@@ -0,0 +1,713 @@
+// Package fakeocm provides an in-process fake of the OCM clusters_mgmt and accounts_mgmt APIs
+// for testing the OCM provider without a live OCM environment.
+package fakeocm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	accounts "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const (
+	clustersMgmtPrefix = "/api/clusters_mgmt/v1/"
+	accountsMgmtPrefix = "/api/accounts_mgmt/v1/"
+
+	// OrganizationID is the organization of the account the fake server authenticates.
+	OrganizationID = "fake-organization"
+
+	// DefaultKubeconfig is returned by the credentials endpoint unless Kubeconfig is changed.
+	DefaultKubeconfig = "apiVersion: v1\nkind: Config\nclusters: []\ncontexts: []\nusers: []\n"
+
+	defaultPageSize = 100
+)
+
+// transitions is the state a cluster moves to the next time it is read.
+var transitions = map[v1.ClusterState]v1.ClusterState{
+	v1.ClusterStatePending:    v1.ClusterStateInstalling,
+	v1.ClusterStateInstalling: v1.ClusterStateReady,
+	v1.ClusterStateResuming:   v1.ClusterStateReady,
+}
+
+type quota struct {
+	allowed  int
+	consumed int
+}
+
+// Server is a fake OCM API. Every cluster read moves the cluster one step along its lifecycle:
+// pending -> installing -> ready, resuming -> ready, and uninstalling -> deleted.
+type Server struct {
+	*httptest.Server
+
+	// Kubeconfig is returned by the cluster credentials endpoint.
+	Kubeconfig string
+
+	mu               sync.Mutex
+	nextID           int
+	token            string
+	clusters         map[string]*v1.Cluster
+	clusterOrder     []string
+	addons           map[string]*v1.AddOn
+	installations    map[string][]*v1.AddOnInstallation
+	upgradePolicies  map[string][]*v1.UpgradePolicy
+	versions         []*v1.Version
+	skuRules         map[string]string
+	quotas           map[string]*quota
+	failures         int
+	failureStatus    int
+	requestsByMethod map[string]int
+}
+
+// NewServer starts a new fake OCM server. Callers must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Kubeconfig:       DefaultKubeconfig,
+		clusters:         map[string]*v1.Cluster{},
+		addons:           map[string]*v1.AddOn{},
+		installations:    map[string][]*v1.AddOnInstallation{},
+		upgradePolicies:  map[string][]*v1.UpgradePolicy{},
+		skuRules:         map[string]string{},
+		quotas:           map[string]*quota{},
+		requestsByMethod: map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.token = makeToken(s.URL)
+	return s
+}
+
+// Token returns an access token accepted by the OCM SDK that never needs refreshing. It is unique
+// per server so that cached OCM connections are never shared between servers.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// makeToken builds an unsigned bearer JWT. The SDK only parses tokens, it does not verify them.
+func makeToken(subject string) string {
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	header := encode(map[string]string{"alg": "HS256", "typ": "JWT"})
+	claims := encode(map[string]interface{}{
+		"typ": "Bearer",
+		"sub": subject,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(24 * time.Hour).Unix(),
+	})
+	return header + "." + claims + "." + base64.RawURLEncoding.EncodeToString([]byte("fake"))
+}
+
+// AddCluster stores a cluster as-is, assigning an ID if it has none, and returns the ID.
+func (s *Server) AddCluster(builder *v1.ClusterBuilder) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cluster, err := builder.Build()
+	if err != nil {
+		return "", err
+	}
+	return s.storeNewCluster(cluster)
+}
+
+// Cluster returns the stored cluster without advancing its lifecycle.
+func (s *Server) Cluster(id string) (*v1.Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cluster, ok := s.clusters[id]
+	return cluster, ok
+}
+
+// SetClusterState forces a cluster into the given state.
+func (s *Server) SetClusterState(id string, state v1.ClusterState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cluster, ok := s.clusters[id]
+	if !ok {
+		return fmt.Errorf("cluster %s not found", id)
+	}
+	updated, err := v1.NewCluster().Copy(cluster).State(state).Build()
+	if err != nil {
+		return err
+	}
+	s.clusters[id] = updated
+	return nil
+}
+
+// AddAddon makes an addon available for installation.
+func (s *Server) AddAddon(id string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addon, _ := v1.NewAddOn().ID(id).Name(id).Enabled(enabled).Build()
+	s.addons[id] = addon
+}
+
+// InstalledAddons returns the IDs of the addons installed on a cluster.
+func (s *Server) InstalledAddons(clusterID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []string{}
+	for _, installation := range s.installations[clusterID] {
+		ids = append(ids, installation.ID())
+	}
+	return ids
+}
+
+// UpgradePolicies returns the upgrade policies scheduled for a cluster.
+func (s *Server) UpgradePolicies(clusterID string) []*v1.UpgradePolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*v1.UpgradePolicy{}, s.upgradePolicies[clusterID]...)
+}
+
+// AddVersion makes a version available from the versions endpoint.
+func (s *Server) AddVersion(id, channelGroup string, isDefault bool, availableUpgrades ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, _ := v1.NewVersion().
+		ID(id).
+		ChannelGroup(channelGroup).
+		Enabled(true).
+		Default(isDefault).
+		AvailableUpgrades(availableUpgrades...).
+		Build()
+	s.versions = append(s.versions, version)
+}
+
+// AddSkuRule registers a SKU rule that draws on the given quota.
+func (s *Server) AddSkuRule(skuRuleID, quotaID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.skuRules[skuRuleID] = quotaID
+}
+
+// SetQuota sets the allowance and consumption of a quota in the organization's quota cost.
+func (s *Server) SetQuota(quotaID string, allowed, consumed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotas[quotaID] = &quota{allowed: allowed, consumed: consumed}
+}
+
+// FailRequests makes the next n requests fail with the given HTTP status.
+func (s *Server) FailRequests(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+	s.failureStatus = status
+}
+
+// Requests returns how many requests have been served with the given HTTP method.
+func (s *Server) Requests(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requestsByMethod[method]
+}
+
+func (s *Server) storeNewCluster(cluster *v1.Cluster) (string, error) {
+	id := cluster.ID()
+	if id == "" {
+		s.nextID++
+		id = fmt.Sprintf("fake-cluster-%d", s.nextID)
+	}
+
+	builder := v1.NewCluster().Copy(cluster).ID(id)
+	if _, ok := cluster.GetState(); !ok {
+		builder.State(v1.ClusterStatePending)
+	}
+	if _, ok := cluster.GetProduct(); !ok {
+		builder.Product(v1.NewProduct().ID("osd"))
+	}
+	if _, ok := cluster.GetCreationTimestamp(); !ok {
+		builder.CreationTimestamp(time.Now().UTC())
+	}
+
+	stored, err := builder.Build()
+	if err != nil {
+		return "", err
+	}
+	if _, exists := s.clusters[id]; !exists {
+		s.clusterOrder = append(s.clusterOrder, id)
+	}
+	s.clusters[id] = stored
+	return id, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requestsByMethod[r.Method]++
+
+	if s.failures > 0 {
+		s.failures--
+		writeError(w, s.failureStatus, "injected failure")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, clustersMgmtPrefix):
+		s.serveClustersMgmt(w, r, strings.Split(strings.TrimPrefix(r.URL.Path, clustersMgmtPrefix), "/"))
+	case strings.HasPrefix(r.URL.Path, accountsMgmtPrefix):
+		s.serveAccountsMgmt(w, r, strings.Split(strings.TrimPrefix(r.URL.Path, accountsMgmtPrefix), "/"))
+	default:
+		writeError(w, http.StatusNotFound, "unknown API "+r.URL.Path)
+	}
+}
+
+func (s *Server) serveClustersMgmt(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case route(r, segments, http.MethodGet, "clusters"):
+		s.listClusters(w, r)
+	case route(r, segments, http.MethodPost, "clusters"):
+		s.createCluster(w, r)
+	case route(r, segments, http.MethodGet, "clusters", "*"):
+		s.getCluster(w, segments[1])
+	case route(r, segments, http.MethodPatch, "clusters", "*"):
+		s.updateCluster(w, r, segments[1])
+	case route(r, segments, http.MethodDelete, "clusters", "*"):
+		s.deleteCluster(w, segments[1])
+	case route(r, segments, http.MethodPost, "clusters", "*", "hibernate"):
+		s.changeClusterState(w, segments[1], v1.ClusterStateReady, v1.ClusterStateHibernating)
+	case route(r, segments, http.MethodPost, "clusters", "*", "resume"):
+		s.changeClusterState(w, segments[1], v1.ClusterStateHibernating, v1.ClusterStateResuming)
+	case route(r, segments, http.MethodGet, "clusters", "*", "credentials"):
+		s.getCredentials(w, segments[1])
+	case route(r, segments, http.MethodGet, "clusters", "*", "addons"):
+		s.listInstallations(w, r, segments[1])
+	case route(r, segments, http.MethodPost, "clusters", "*", "addons"):
+		s.installAddon(w, r, segments[1])
+	case route(r, segments, http.MethodGet, "clusters", "*", "upgrade_policies"):
+		s.listUpgradePolicies(w, r, segments[1])
+	case route(r, segments, http.MethodPost, "clusters", "*", "upgrade_policies"):
+		s.addUpgradePolicy(w, r, segments[1])
+	case route(r, segments, http.MethodPatch, "clusters", "*", "upgrade_policies", "*"):
+		s.updateUpgradePolicy(w, r, segments[1], segments[3])
+	case route(r, segments, http.MethodGet, "addons", "*"):
+		s.getAddon(w, segments[1])
+	case route(r, segments, http.MethodGet, "versions"):
+		s.listVersions(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) serveAccountsMgmt(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case route(r, segments, http.MethodGet, "current_account"):
+		account, _ := accounts.NewAccount().
+			ID("fake-account").
+			Username("fake").
+			Organization(accounts.NewOrganization().ID(OrganizationID)).
+			Build()
+		writeObject(w, http.StatusOK, func(out io.Writer) error { return accounts.MarshalAccount(account, out) })
+	case route(r, segments, http.MethodGet, "sku_rules", "*"):
+		quotaID, ok := s.skuRules[segments[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "sku rule "+segments[1]+" not found")
+			return
+		}
+		rule, _ := accounts.NewSkuRule().ID(segments[1]).QuotaId(quotaID).Build()
+		writeObject(w, http.StatusOK, func(out io.Writer) error { return accounts.MarshalSkuRule(rule, out) })
+	case route(r, segments, http.MethodGet, "organizations", OrganizationID, "quota_cost"):
+		items := []interface{}{}
+		for quotaID, q := range s.quotas {
+			cost, _ := accounts.NewQuotaCost().
+				OrganizationID(OrganizationID).
+				QuotaID(quotaID).
+				Allowed(q.allowed).
+				Consumed(q.consumed).
+				Build()
+			items = append(items, cost)
+		}
+		writeList(w, r, items, func(item interface{}, out io.Writer) error {
+			return accounts.MarshalQuotaCost(item.(*accounts.QuotaCost), out)
+		})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// route reports whether the request matches the method and path segments, where "*" matches any one segment.
+func route(r *http.Request, segments []string, method string, pattern ...string) bool {
+	if r.Method != method || len(segments) != len(pattern) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readCluster returns the cluster after moving it one step along its lifecycle.
+func (s *Server) readCluster(id string) (*v1.Cluster, bool) {
+	cluster, ok := s.clusters[id]
+	if !ok {
+		return nil, false
+	}
+
+	if cluster.State() == v1.ClusterStateUninstalling {
+		delete(s.clusters, id)
+		for i, existing := range s.clusterOrder {
+			if existing == id {
+				s.clusterOrder = append(s.clusterOrder[:i], s.clusterOrder[i+1:]...)
+				break
+			}
+		}
+		return nil, false
+	}
+
+	if next, ok := transitions[cluster.State()]; ok {
+		cluster, _ = v1.NewCluster().Copy(cluster).State(next).Build()
+		s.clusters[id] = cluster
+	}
+	return cluster, true
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSearch(r.URL.Query().Get("search"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items := []interface{}{}
+	for _, id := range s.clusterOrder {
+		if cluster := s.clusters[id]; filter(cluster) {
+			items = append(items, cluster)
+		}
+	}
+	writeList(w, r, items, func(item interface{}, out io.Writer) error {
+		return v1.MarshalCluster(item.(*v1.Cluster), out)
+	})
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
+	cluster, err := v1.UnmarshalCluster(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, existing := range s.clusters {
+		if existing.Name() == cluster.Name() {
+			writeError(w, http.StatusBadRequest, "cluster name "+cluster.Name()+" is already taken")
+			return
+		}
+	}
+
+	id, err := s.storeNewCluster(cluster)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, installation := range cluster.Addons().Slice() {
+		s.installations[id] = append(s.installations[id], s.newInstallation(id, installation.Addon().ID()))
+	}
+
+	stored := s.clusters[id]
+	writeObject(w, http.StatusCreated, func(out io.Writer) error { return v1.MarshalCluster(stored, out) })
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, id string) {
+	cluster, ok := s.readCluster(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "cluster "+id+" not found")
+		return
+	}
+	writeObject(w, http.StatusOK, func(out io.Writer) error { return v1.MarshalCluster(cluster, out) })
+}
+
+// updateCluster applies the fields of the patch that the OCM provider is known to send.
+func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request, id string) {
+	cluster, ok := s.clusters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "cluster "+id+" not found")
+		return
+	}
+
+	patch, err := v1.UnmarshalCluster(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	builder := v1.NewCluster().Copy(cluster)
+	if properties, ok := patch.GetProperties(); ok {
+		builder.Properties(properties)
+	}
+	if expiration, ok := patch.GetExpirationTimestamp(); ok {
+		builder.ExpirationTimestamp(expiration)
+	}
+	if nodes, ok := patch.GetNodes(); ok {
+		builder.Nodes(v1.NewClusterNodes().Copy(cluster.Nodes()).Compute(nodes.Compute()))
+	}
+	if proxy, ok := patch.GetProxy(); ok {
+		builder.Proxy(v1.NewProxy().Copy(proxy))
+	}
+	if bundle, ok := patch.GetAdditionalTrustBundle(); ok {
+		builder.AdditionalTrustBundle(bundle)
+	}
+
+	updated, err := builder.Build()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.clusters[id] = updated
+	writeObject(w, http.StatusOK, func(out io.Writer) error { return v1.MarshalCluster(updated, out) })
+}
+
+func (s *Server) deleteCluster(w http.ResponseWriter, id string) {
+	if _, ok := s.clusters[id]; !ok {
+		writeError(w, http.StatusNotFound, "cluster "+id+" not found")
+		return
+	}
+	s.clusters[id], _ = v1.NewCluster().Copy(s.clusters[id]).State(v1.ClusterStateUninstalling).Build()
+	writeEmpty(w, http.StatusNoContent)
+}
+
+func (s *Server) changeClusterState(w http.ResponseWriter, id string, from, to v1.ClusterState) {
+	cluster, ok := s.clusters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "cluster "+id+" not found")
+		return
+	}
+	if cluster.State() != from {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("cluster %s is %s, not %s", id, cluster.State(), from))
+		return
+	}
+	s.clusters[id], _ = v1.NewCluster().Copy(cluster).State(to).Build()
+	writeEmpty(w, http.StatusOK)
+}
+
+func (s *Server) getCredentials(w http.ResponseWriter, id string) {
+	if _, ok := s.clusters[id]; !ok {
+		writeError(w, http.StatusNotFound, "cluster "+id+" not found")
+		return
+	}
+	credentials, _ := v1.NewClusterCredentials().Kubeconfig(s.Kubeconfig).Build()
+	writeObject(w, http.StatusOK, func(out io.Writer) error { return v1.MarshalClusterCredentials(credentials, out) })
+}
+
+func (s *Server) newInstallation(clusterID, addonID string) *v1.AddOnInstallation {
+	installation, _ := v1.NewAddOnInstallation().
+		ID(addonID).
+		Addon(v1.NewAddOn().ID(addonID)).
+		Cluster(v1.NewCluster().Copy(s.clusters[clusterID])).
+		State(v1.AddOnInstallationStateInstalling).
+		CreationTimestamp(time.Now().UTC()).
+		Build()
+	return installation
+}
+
+func (s *Server) listInstallations(w http.ResponseWriter, r *http.Request, clusterID string) {
+	items := []interface{}{}
+	for _, installation := range s.installations[clusterID] {
+		items = append(items, installation)
+	}
+	writeList(w, r, items, func(item interface{}, out io.Writer) error {
+		return v1.MarshalAddOnInstallation(item.(*v1.AddOnInstallation), out)
+	})
+}
+
+func (s *Server) installAddon(w http.ResponseWriter, r *http.Request, clusterID string) {
+	if _, ok := s.clusters[clusterID]; !ok {
+		writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+		return
+	}
+
+	request, err := v1.UnmarshalAddOnInstallation(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	addonID := request.Addon().ID()
+	addon, ok := s.addons[addonID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "addon "+addonID+" not found")
+		return
+	}
+	if !addon.Enabled() {
+		writeError(w, http.StatusBadRequest, "addon "+addonID+" is not enabled")
+		return
+	}
+	for _, existing := range s.installations[clusterID] {
+		if existing.ID() == addonID {
+			writeError(w, http.StatusConflict, "addon "+addonID+" is already installed")
+			return
+		}
+	}
+
+	installation := s.newInstallation(clusterID, addonID)
+	s.installations[clusterID] = append(s.installations[clusterID], installation)
+	writeObject(w, http.StatusCreated, func(out io.Writer) error { return v1.MarshalAddOnInstallation(installation, out) })
+}
+
+func (s *Server) getAddon(w http.ResponseWriter, id string) {
+	addon, ok := s.addons[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "addon "+id+" not found")
+		return
+	}
+	writeObject(w, http.StatusOK, func(out io.Writer) error { return v1.MarshalAddOn(addon, out) })
+}
+
+func (s *Server) listUpgradePolicies(w http.ResponseWriter, r *http.Request, clusterID string) {
+	items := []interface{}{}
+	for _, policy := range s.upgradePolicies[clusterID] {
+		items = append(items, policy)
+	}
+	writeList(w, r, items, func(item interface{}, out io.Writer) error {
+		return v1.MarshalUpgradePolicy(item.(*v1.UpgradePolicy), out)
+	})
+}
+
+func (s *Server) addUpgradePolicy(w http.ResponseWriter, r *http.Request, clusterID string) {
+	if _, ok := s.clusters[clusterID]; !ok {
+		writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+		return
+	}
+
+	request, err := v1.UnmarshalUpgradePolicy(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.nextID++
+	policy, _ := v1.NewUpgradePolicy().
+		Copy(request).
+		ID(fmt.Sprintf("fake-policy-%d", s.nextID)).
+		ClusterID(clusterID).
+		Build()
+	s.upgradePolicies[clusterID] = append(s.upgradePolicies[clusterID], policy)
+	writeObject(w, http.StatusCreated, func(out io.Writer) error { return v1.MarshalUpgradePolicy(policy, out) })
+}
+
+func (s *Server) updateUpgradePolicy(w http.ResponseWriter, r *http.Request, clusterID, policyID string) {
+	patch, err := v1.UnmarshalUpgradePolicy(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for i, policy := range s.upgradePolicies[clusterID] {
+		if policy.ID() != policyID {
+			continue
+		}
+		builder := v1.NewUpgradePolicy().Copy(policy)
+		if nextRun, ok := patch.GetNextRun(); ok {
+			builder.NextRun(nextRun)
+		}
+		updated, _ := builder.Build()
+		s.upgradePolicies[clusterID][i] = updated
+		writeObject(w, http.StatusOK, func(out io.Writer) error { return v1.MarshalUpgradePolicy(updated, out) })
+		return
+	}
+	writeError(w, http.StatusNotFound, "upgrade policy "+policyID+" not found")
+}
+
+func (s *Server) listVersions(w http.ResponseWriter, r *http.Request) {
+	items := []interface{}{}
+	for _, version := range s.versions {
+		items = append(items, version)
+	}
+	writeList(w, r, items, func(item interface{}, out io.Writer) error {
+		return v1.MarshalVersion(item.(*v1.Version), out)
+	})
+}
+
+// writeEmpty writes a response without a body. The SDK rejects any response that isn't JSON, even empty ones.
+func writeEmpty(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+}
+
+func writeObject(w http.ResponseWriter, status int, marshal func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := marshal(&buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// writeList writes the requested page of items in the OCM list format.
+func writeList(w http.ResponseWriter, r *http.Request, items []interface{}, marshal func(interface{}, io.Writer) error) {
+	page, size := 1, defaultPageSize
+	if value, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && value > 0 {
+		page = value
+	}
+	if value, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && value > 0 {
+		size = value
+	}
+
+	start := (page - 1) * size
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+
+	raw := []json.RawMessage{}
+	for _, item := range items[start:end] {
+		var buf bytes.Buffer
+		if err := marshal(item, &buf); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		raw = append(raw, buf.Bytes())
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"page":  page,
+		"size":  len(raw),
+		"total": len(items),
+		"items": raw,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, reason string) {
+	body, _ := json.Marshal(map[string]string{
+		"kind":   "Error",
+		"id":     strconv.Itoa(status),
+		"code":   fmt.Sprintf("FAKE-OCM-%d", status),
+		"reason": reason,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
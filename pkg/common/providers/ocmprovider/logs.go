@@ -1,6 +1,7 @@
 package ocmprovider
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strings"
@@ -8,10 +9,10 @@ import (
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
-// Logs provides all logs available for clusterID, ids can be optionally provided for only specific logs.
-func (o *OCMProvider) Logs(clusterID string) (logs map[string][]byte, err error) {
+// LogsContext provides all logs available for clusterID, ids can be optionally provided for only specific logs.
+func (o *OCMProvider) LogsContext(ctx context.Context, clusterID string) (logs map[string][]byte, err error) {
 	var ids []string
-	if ids, err = o.getLogList(clusterID); err != nil {
+	if ids, err = o.getLogList(ctx, clusterID); err != nil {
 		return logs, fmt.Errorf("couldn't get log list: %v", err)
 	}
 
@@ -20,12 +21,12 @@ func (o *OCMProvider) Logs(clusterID string) (logs map[string][]byte, err error)
 		var resp *v1.LogGetResponse
 
 		found := false
-		err = retryer().Do(func() error {
+		err = retryWithContext(ctx, func() error {
 			var err error
 			resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
 				Logs().Install().
 				Get().Parameter("tail", math.MaxInt32-1).
-				Send()
+				SendContext(ctx)
 
 			if err != nil {
 				// Log is just not found, so skip this log
@@ -59,15 +60,15 @@ func (o *OCMProvider) Logs(clusterID string) (logs map[string][]byte, err error)
 	return
 }
 
-func (o *OCMProvider) getLogList(clusterID string) ([]string, error) {
+func (o *OCMProvider) getLogList(ctx context.Context, clusterID string) ([]string, error) {
 	var resp *v1.LogsListResponse
 
-	err := retryer().Do(func() error {
+	err := retryWithContext(ctx, func() error {
 		var err error
 		resp, err = o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
 			Logs().
 			List().
-			Send()
+			SendContext(ctx)
 
 		if err != nil {
 			return err
@@ -32,6 +32,8 @@ var connectionCache = map[ocmConnectionKey]*ocm.Connection{}
 
 // OCMProvider will provision clusters using the OCM API.
 type OCMProvider struct {
+	spi.Background
+
 	env          string
 	conn         *ocm.Connection
 	prodProvider *OCMProvider
@@ -115,13 +117,22 @@ func NewWithEnv(env string) (*OCMProvider, error) {
 		}
 	}
 
-	return &OCMProvider{
+	return NewWithConnection(env, conn, prodProvider), nil
+}
+
+// NewWithConnection creates a new provider for env that uses an existing OCM connection. The
+// production provider is used to look up the production default version and may be nil, in which
+// case no default version override is applied.
+func NewWithConnection(env string, conn *ocm.Connection, prodProvider *OCMProvider) *OCMProvider {
+	o := &OCMProvider{
 		env:             env,
 		conn:            conn,
 		prodProvider:    prodProvider,
 		clusterCache:    make(map[string]*spi.Cluster),
 		credentialCache: make(map[string]string),
-	}, nil
+	}
+	o.Background = spi.NewBackground(o)
+	return o
 }
 
 // Environment simply returns the environment this OCMProvider is pointed to.
@@ -171,3 +182,16 @@ func errResp(resp *ocmerr.Error) error {
 func (o *OCMProvider) Type() string {
 	return "ocm"
 }
+
+// Capabilities reports the optional features OCM supports, which is all of them.
+func (o *OCMProvider) Capabilities() spi.Capabilities {
+	return spi.Capabilities{
+		Hibernation:     true,
+		Addons:          true,
+		ManagedUpgrades: true,
+		ClusterProxy:    true,
+		ExtendExpiry:    true,
+		Scaling:         true,
+		Recycling:       true,
+	}
+}
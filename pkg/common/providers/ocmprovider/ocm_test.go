@@ -0,0 +1,36 @@
+package ocmprovider_test
+
+import (
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider"
+	"github.com/openshift/osde2e/pkg/common/providers/ocmprovider/fakeocm"
+)
+
+// newFakeProvider returns a prod OCMProvider talking to a fake OCM server that is closed with the test.
+func newFakeProvider(t *testing.T) (*fakeocm.Server, *ocmprovider.OCMProvider) {
+	t.Helper()
+
+	srv := fakeocm.NewServer()
+	t.Cleanup(srv.Close)
+
+	conn, err := ocmprovider.OCMConnection(srv.Token(), srv.URL, false)
+	if err != nil {
+		t.Fatalf("unable to connect to the fake OCM server: %v", err)
+	}
+
+	return srv, ocmprovider.NewWithConnection("prod", conn, nil)
+}
+
+// setConfig sets config values for the duration of the test.
+func setConfig(t *testing.T, values map[string]interface{}) {
+	t.Helper()
+
+	for key, value := range values {
+		previous := viper.Get(key)
+		viper.Set(key, value)
+		key := key
+		t.Cleanup(func() { viper.Set(key, previous) })
+	}
+}
@@ -1,6 +1,7 @@
 package ocmprovider
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -16,8 +17,8 @@ type ClusterWideProxy struct {
 	AdditionalTrustBundle string
 }
 
-// AddClusterProxy sets the cluster proxy configuration for the supplied cluster
-func (o *OCMProvider) AddClusterProxy(clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+// AddClusterProxyContext sets the cluster proxy configuration for the supplied cluster
+func (o *OCMProvider) AddClusterProxyContext(ctx context.Context, clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
 	clusterBuilder := cmv1.NewCluster()
 
 	clusterProxyBuilder := cmv1.NewProxy()
@@ -34,11 +35,11 @@ func (o *OCMProvider) AddClusterProxy(clusterId string, httpsProxy string, httpP
 	if err != nil {
 		return err
 	}
-	return o.updateCluster(clusterId, clusterSpec)
+	return o.updateCluster(ctx, clusterId, clusterSpec)
 }
 
-// RemoveClusterProxy removes the cluster proxy configuration for the supplied cluster
-func (o *OCMProvider) RemoveClusterProxy(clusterId string) error {
+// RemoveClusterProxyContext removes the cluster proxy configuration for the supplied cluster
+func (o *OCMProvider) RemoveClusterProxyContext(ctx context.Context, clusterId string) error {
 	clusterBuilder := cmv1.NewCluster()
 
 	clusterProxyBuilder := cmv1.NewProxy()
@@ -51,22 +52,22 @@ func (o *OCMProvider) RemoveClusterProxy(clusterId string) error {
 	if err != nil {
 		return err
 	}
-	return o.updateCluster(clusterId, clusterSpec)
+	return o.updateCluster(ctx, clusterId, clusterSpec)
 }
 
-// RemoveUserCABundle removes only the Additional Trusted CA Bundle from the cluster
-func (o *OCMProvider) RemoveUserCABundle(clusterId string) error {
+// RemoveUserCABundleContext removes only the Additional Trusted CA Bundle from the cluster
+func (o *OCMProvider) RemoveUserCABundleContext(ctx context.Context, clusterId string) error {
 	clusterBuilder := cmv1.NewCluster()
 	clusterBuilder = clusterBuilder.AdditionalTrustBundle("")
 	clusterSpec, err := clusterBuilder.Build()
 	if err != nil {
 		return err
 	}
-	return o.updateCluster(clusterId, clusterSpec)
+	return o.updateCluster(ctx, clusterId, clusterSpec)
 }
 
-func (o *OCMProvider) updateCluster(clusterId string, clusterSpec *cmv1.Cluster) error {
-	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterId).Update().Body(clusterSpec).Send()
+func (o *OCMProvider) updateCluster(ctx context.Context, clusterId string, clusterSpec *cmv1.Cluster) error {
+	resp, err := o.conn.ClustersMgmt().V1().Clusters().Cluster(clusterId).Update().Body(clusterSpec).SendContext(ctx)
 	if err != nil {
 		err = fmt.Errorf("couldn't update proxy for cluster '%s': %v", clusterId, err)
 		log.Printf("%v", err)
@@ -1,6 +1,7 @@
 package ocmprovider
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -10,16 +11,16 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 )
 
-// CheckQuota determines if enough quota is available to launch with cfg.
-func (o *OCMProvider) CheckQuota(skuRuleID string) (bool, error) {
+// CheckQuotaContext determines if enough quota is available to launch with cfg.
+func (o *OCMProvider) CheckQuotaContext(ctx context.Context, skuRuleID string) (bool, error) {
 	// get flavour being deployed
 	var skuResp *accounts.SkuRuleGetResponse
-	err := retryer().Do(func() error {
+	err := retryWithContext(ctx, func() error {
 		var err error
 		if skuRuleID == "" {
 			return fmt.Errorf("No valid SKU selected")
 		}
-		skuResp, err = o.conn.AccountsMgmt().V1().SkuRules().SkuRule(skuRuleID).Get().Send()
+		skuResp, err = o.conn.AccountsMgmt().V1().SkuRules().SkuRule(skuRuleID).Get().SendContext(ctx)
 
 		if err != nil {
 			return err
@@ -44,7 +45,7 @@ func (o *OCMProvider) CheckQuota(skuRuleID string) (bool, error) {
 	sku := skuResp.Body()
 
 	// get quota
-	quotaList, err := o.currentAccountQuota()
+	quotaList, err := o.currentAccountQuota(ctx)
 	if err != nil {
 		return false, fmt.Errorf("could not get quota: %v", err)
 	}
@@ -68,8 +69,8 @@ func (o *OCMProvider) CheckQuota(skuRuleID string) (bool, error) {
 }
 
 // CurrentAccountQuota returns quota available for the current account's organization in the environment.
-func (o *OCMProvider) currentAccountQuota() (*accounts.QuotaCostList, error) {
-	resp, err := o.conn.AccountsMgmt().V1().CurrentAccount().Get().Send()
+func (o *OCMProvider) currentAccountQuota(ctx context.Context) (*accounts.QuotaCostList, error) {
+	resp, err := o.conn.AccountsMgmt().V1().CurrentAccount().Get().SendContext(ctx)
 	if err != nil || resp == nil {
 		return nil, fmt.Errorf("couldn't get current account: %v", err)
 	}
@@ -83,9 +84,9 @@ func (o *OCMProvider) currentAccountQuota() (*accounts.QuotaCostList, error) {
 	orgID := acc.Organization().ID()
 
 	var quotaList *accounts.QuotaCostListResponse
-	err = retryer().Do(func() error {
+	err = retryWithContext(ctx, func() error {
 		var err error
-		quotaList, err = o.conn.AccountsMgmt().V1().Organizations().Organization(orgID).QuotaCost().List().Send()
+		quotaList, err = o.conn.AccountsMgmt().V1().Organizations().Organization(orgID).QuotaCost().List().SendContext(ctx)
 
 		if err != nil {
 			return err
@@ -0,0 +1,54 @@
+package ocmprovider_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckQuota(t *testing.T) {
+	tests := []struct {
+		Name     string
+		QuotaID  string
+		Allowed  int
+		Consumed int
+		Expected bool
+	}{
+		{
+			Name:     "quota available",
+			QuotaID:  "cluster|byoc|osd",
+			Allowed:  10,
+			Consumed: 3,
+			Expected: true,
+		},
+		{
+			Name:     "quota exhausted",
+			QuotaID:  "cluster|byoc|osd",
+			Allowed:  10,
+			Consumed: 10,
+			Expected: false,
+		},
+		{
+			Name:     "no quota for the SKU",
+			QuotaID:  "cluster|rhinfra|osd",
+			Allowed:  10,
+			Consumed: 0,
+			Expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			srv.AddSkuRule("osd-byoc", "cluster|byoc|osd")
+			srv.SetQuota(test.QuotaID, test.Allowed, test.Consumed)
+
+			enoughQuota, err := provider.CheckQuotaContext(context.Background(), "osd-byoc")
+			if err != nil {
+				t.Fatalf("unexpected error checking quota: %v", err)
+			}
+			if enoughQuota != test.Expected {
+				t.Errorf("expected quota check to be %t, got %t", test.Expected, enoughQuota)
+			}
+		})
+	}
+}
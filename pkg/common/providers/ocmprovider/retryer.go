@@ -1,6 +1,7 @@
 package ocmprovider
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -28,3 +29,22 @@ func retryer() *retry.Retryer {
 
 	return ocmRetryer
 }
+
+// retryWithContext runs fn through the OCM retryer, giving up on further attempts
+// as soon as ctx is done. The context error is returned in that case.
+func retryWithContext(ctx context.Context, fn func() error) error {
+	var ctxErr error
+
+	err := retryer().Do(func() error {
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			// Returning nil stops the retryer; ctxErr is reported below.
+			return nil
+		}
+		return fn()
+	})
+	if ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
@@ -1,6 +1,8 @@
 package ocmprovider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -63,3 +65,24 @@ func TestRetryer(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	defer func(tries int) { retryer().Tries = tries }(retryer().Tries)
+	retryer().Tries = 3
+	err := retryWithContext(ctx, func() error {
+		attempts++
+		cancel()
+		return fmt.Errorf("failure")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt before cancellation was noticed, got %d", attempts)
+	}
+}
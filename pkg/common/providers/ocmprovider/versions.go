@@ -1,6 +1,7 @@
 package ocmprovider
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sort"
@@ -24,9 +25,9 @@ const (
 	NoVersionFound = "NoVersionFound"
 )
 
-// Versions will return all of the available version and a default override of the production default version
+// VersionsContext will return all of the available version and a default override of the production default version
 // if using a non-production environment.
-func (o *OCMProvider) Versions() (*spi.VersionList, error) {
+func (o *OCMProvider) VersionsContext(ctx context.Context) (*spi.VersionList, error) {
 	var err error
 
 	versions := []*spi.Version{}
@@ -34,10 +35,10 @@ func (o *OCMProvider) Versions() (*spi.VersionList, error) {
 	log.Printf("Querying cluster versions endpoint.")
 	for {
 		var resp *v1.VersionsListResponse
-		err = retryer().Do(func() error {
+		err = retryWithContext(ctx, func() error {
 			var err error
 
-			resp, err = o.conn.ClustersMgmt().V1().Versions().List().Page(page).Size(PageSize).Send()
+			resp, err = o.conn.ClustersMgmt().V1().Versions().List().Page(page).Size(PageSize).SendContext(ctx)
 
 			if err != nil {
 				return err
@@ -102,9 +103,9 @@ func (o *OCMProvider) Versions() (*spi.VersionList, error) {
 
 	var defaultVersionOverride *semver.Version = nil
 
-	if o.env != prod {
+	if o.env != prod && o.prodProvider != nil {
 		var versionList *spi.VersionList
-		versionList, err = o.prodProvider.Versions()
+		versionList, err = o.prodProvider.VersionsContext(ctx)
 
 		if err != nil {
 			return nil, fmt.Errorf("error getting production default: %v", err)
@@ -0,0 +1,78 @@
+package ocmprovider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/openshift/osde2e/pkg/common/config"
+)
+
+func TestVersions(t *testing.T) {
+	tests := []struct {
+		Name              string
+		Channel           string
+		ExpectedVersions  []string
+		ExpectedDefault   string
+		ExpectedUpgradeTo map[string]string
+	}{
+		{
+			Name:             "stable channel",
+			Channel:          "stable",
+			ExpectedVersions: []string{"4.10.40", "4.11.12"},
+			ExpectedDefault:  "4.11.12",
+			ExpectedUpgradeTo: map[string]string{
+				"4.10.40": "4.11.12",
+			},
+		},
+		{
+			Name:             "nightly channel",
+			Channel:          "nightly",
+			ExpectedVersions: []string{"4.10.40", "4.11.12", "4.12.0-0.nightly-2022-11-01-000000"},
+			ExpectedDefault:  "4.11.12",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv, provider := newFakeProvider(t)
+			setConfig(t, map[string]interface{}{config.Cluster.Channel: test.Channel})
+
+			srv.AddVersion("openshift-v4.10.40", "stable", false, "openshift-v4.11.12")
+			srv.AddVersion("openshift-v4.11.12", "stable", true)
+			srv.AddVersion("openshift-v4.12.0-0.nightly-2022-11-01-000000", "nightly", false)
+			srv.AddVersion("not-a-version", "stable", false)
+
+			versionList, err := provider.VersionsContext(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error getting versions: %v", err)
+			}
+
+			versions := versionList.AvailableVersions()
+			if len(versions) != len(test.ExpectedVersions) {
+				t.Fatalf("expected %d versions, got %d", len(test.ExpectedVersions), len(versions))
+			}
+			for i, version := range versions {
+				if version.Version().Original() != test.ExpectedVersions[i] {
+					t.Errorf("expected version %d to be %s, got %s", i, test.ExpectedVersions[i], version.Version().Original())
+				}
+				if upgrade, ok := test.ExpectedUpgradeTo[version.Version().Original()]; ok && !hasUpgrade(version.AvailableUpgrades(), upgrade) {
+					t.Errorf("expected %s to be able to upgrade to %s", version.Version().Original(), upgrade)
+				}
+			}
+
+			if versionList.Default() == nil || versionList.Default().Original() != test.ExpectedDefault {
+				t.Errorf("expected default version %s, got %v", test.ExpectedDefault, versionList.Default())
+			}
+		})
+	}
+}
+
+func hasUpgrade(upgrades map[*semver.Version]bool, target string) bool {
+	for upgrade := range upgrades {
+		if upgrade.Equal(semver.MustParse(target)) {
+			return true
+		}
+	}
+	return false
+}
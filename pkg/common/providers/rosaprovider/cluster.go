@@ -26,12 +26,9 @@ import (
 	"github.com/openshift/osde2e/pkg/common/util"
 )
 
-// IsValidClusterName validates the clustername prior to proceeding with it
+// IsValidClusterNameContext validates the clustername prior to proceeding with it
 // in launching a cluster.
-func (m *ROSAProvider) IsValidClusterName(clusterName string) (bool, error) {
-	// Create a context:
-	ctx := context.Background()
-
+func (m *ROSAProvider) IsValidClusterNameContext(ctx context.Context, clusterName string) (bool, error) {
 	collection := m.ocmProvider.GetConnection().ClustersMgmt().V1().Clusters()
 
 	// Retrieve the list of clusters using pages of ten items, till we get a page that has less
@@ -66,9 +63,9 @@ func (m *ROSAProvider) IsValidClusterName(clusterName string) (bool, error) {
 	return true, nil
 }
 
-// LaunchCluster will provision an AWS cluster.
+// LaunchClusterContext will provision an AWS cluster.
 // nolint:gocyclo
-func (m *ROSAProvider) LaunchCluster(clusterName string) (string, error) {
+func (m *ROSAProvider) LaunchClusterContext(ctx context.Context, clusterName string) (string, error) {
 	// Calculate an expiration date for the cluster so that it will be automatically deleted if
 	// we happen to forget to do it:
 	var expiration time.Time
@@ -92,7 +89,7 @@ func (m *ROSAProvider) LaunchCluster(clusterName string) (string, error) {
 	log.Printf("ROSA cluster version: %s", rosaClusterVersion)
 
 	if viper.GetBool(config.Cluster.UseExistingCluster) && viper.GetString(config.Addons.IDs) == "" {
-		if clusterID := m.ocmProvider.FindRecycledCluster(rosaClusterVersion, "aws", "rosa"); clusterID != "" {
+		if clusterID := m.ocmProvider.FindRecycledCluster(ctx, rosaClusterVersion, "aws", "rosa"); clusterID != "" {
 			return clusterID, nil
 		}
 	}
@@ -103,7 +100,7 @@ func (m *ROSAProvider) LaunchCluster(clusterName string) (string, error) {
 	}
 
 	// ROSA uses the AWS provider in the background, so we'll determine region this way.
-	region, err := m.DetermineRegion("aws")
+	region, err := m.DetermineRegion(ctx, "aws")
 	if err != nil {
 		return "", fmt.Errorf("error determining region to use: %v", err)
 	}
@@ -227,7 +224,7 @@ func (m *ROSAProvider) LaunchCluster(clusterName string) (string, error) {
 	newCluster := createCluster.Cmd
 	newCluster.SetArgs(createClusterArgs)
 	err = callAndSetAWSSession(func() error {
-		return newCluster.Execute()
+		return newCluster.ExecuteContext(ctx)
 	})
 	if err != nil {
 		log.Print("Error creating cluster: ", err)
@@ -247,24 +244,24 @@ func (m *ROSAProvider) LaunchCluster(clusterName string) (string, error) {
 	return cluster.ID(), nil
 }
 
-// DeleteCluster will call DeleteCluster from the OCM provider then delete
+// DeleteClusterContext will call DeleteClusterContext from the OCM provider then delete
 // additional AWS resources if STS is in use.
-func (m *ROSAProvider) DeleteCluster(clusterID string) error {
-	if err := m.ocmProvider.DeleteCluster(clusterID); err != nil {
+func (m *ROSAProvider) DeleteClusterContext(ctx context.Context, clusterID string) error {
+	if err := m.ocmProvider.DeleteClusterContext(ctx, clusterID); err != nil {
 		return err
 	}
 
 	if viper.GetBool(STS) {
-		return m.stsClusterCleanup(clusterID)
+		return m.stsClusterCleanup(ctx, clusterID)
 	}
 
 	return nil
 }
 
-func (m *ROSAProvider) stsClusterCleanup(clusterID string) error {
+func (m *ROSAProvider) stsClusterCleanup(ctx context.Context, clusterID string) error {
 	// wait for the cluster to no longer be available
-	wait.PollImmediate(2*time.Minute, 30*time.Minute, func() (bool, error) {
-		clusters, err := m.ocmProvider.ListClusters(fmt.Sprintf("id = '%s'", clusterID))
+	wait.PollImmediateWithContext(ctx, 2*time.Minute, 30*time.Minute, func(ctx context.Context) (bool, error) {
+		clusters, err := m.ocmProvider.ListClustersContext(ctx, fmt.Sprintf("id = '%s'", clusterID))
 		if err != nil {
 			return false, err
 		}
@@ -272,6 +269,10 @@ func (m *ROSAProvider) stsClusterCleanup(clusterID string) error {
 		return len(clusters) == 0, nil
 	})
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	return callAndSetAWSSession(func() error {
 		var err error
 		defaultArgs := []string{"--cluster", clusterID, "--mode", "auto", "--yes"}
@@ -286,13 +287,13 @@ func (m *ROSAProvider) stsClusterCleanup(clusterID string) error {
 		deleteOIDCProviderCmd.SetArgs(deleteOIDCProviderArgs)
 		log.Printf("%v", deleteOIDCProviderArgs)
 
-		if err = deleteOperatorRolesCmd.Execute(); err != nil {
+		if err = deleteOperatorRolesCmd.ExecuteContext(ctx); err != nil {
 			log.Printf("Error deleting operator roles: %v", err)
 			return err
 		}
 		log.Printf("Deleted operator roles for cluster %s", clusterID)
 
-		if err = deleteOIDCProviderCmd.Execute(); err != nil {
+		if err = deleteOIDCProviderCmd.ExecuteContext(ctx); err != nil {
 			log.Printf("Error deleting OIDC provider: %v", err)
 			return err
 		}
@@ -304,7 +305,7 @@ func (m *ROSAProvider) stsClusterCleanup(clusterID string) error {
 
 // DetermineRegion will return the region provided by configs. This mainly wraps the random functionality for use
 // by the ROSA provider.
-func (m *ROSAProvider) DetermineRegion(cloudProvider string) (string, error) {
+func (m *ROSAProvider) DetermineRegion(ctx context.Context, cloudProvider string) (string, error) {
 	region := viper.GetString(config.AWSRegion)
 
 	// If a region is set to "random", it will poll OCM for all the regions available
@@ -325,7 +326,7 @@ func (m *ROSAProvider) DetermineRegion(cloudProvider string) (string, error) {
 				return "", err
 			}
 
-			response, err := m.ocmProvider.GetConnection().ClustersMgmt().V1().CloudProviders().CloudProvider(cloudProvider).AvailableRegions().Search().Body(awsCredentials).Send()
+			response, err := m.ocmProvider.GetConnection().ClustersMgmt().V1().CloudProviders().CloudProvider(cloudProvider).AvailableRegions().Search().Body(awsCredentials).SendContext(ctx)
 			if err != nil {
 				return "", err
 			}
@@ -412,8 +413,13 @@ func (m *ROSAProvider) ocmLogin() (*ocm.Client, error) {
 	return ocmClient, err
 }
 
-// Versions will call Versions from the OCM provider.
-func (m *ROSAProvider) Versions() (*spi.VersionList, error) {
+// VersionsContext will retrieve the available versions through the ROSA OCM client.
+// The ROSA client does not accept a context, so ctx is only checked before logging in.
+func (m *ROSAProvider) VersionsContext(ctx context.Context) (*spi.VersionList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	ocmClient, err := m.ocmLogin()
 	if err != nil {
 		return nil, err
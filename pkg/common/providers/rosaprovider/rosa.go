@@ -15,6 +15,8 @@ func init() {
 
 // ROSAProvider will provision clusters via ROSA.
 type ROSAProvider struct {
+	spi.Background
+
 	ocmProvider *ocmprovider.OCMProvider
 }
 
@@ -25,12 +27,20 @@ func New() (*ROSAProvider, error) {
 		return nil, fmt.Errorf("error creating OCM provider for ROSA provider: %v", err)
 	}
 
-	return &ROSAProvider{
+	m := &ROSAProvider{
 		ocmProvider: ocmProvider,
-	}, nil
+	}
+	m.Background = spi.NewBackground(m)
+	return m, nil
 }
 
 // Type returns the provisioner type: rosa
 func (m *ROSAProvider) Type() string {
 	return "rosa"
 }
+
+// Capabilities reports the optional features of the underlying OCM provider, since ROSA
+// clusters are managed through OCM once created.
+func (m *ROSAProvider) Capabilities() spi.Capabilities {
+	return m.ocmProvider.Capabilities()
+}
@@ -1,6 +1,7 @@
 package rosaprovider
 
 import (
+	"context"
 	"time"
 
 	"github.com/openshift/osde2e/pkg/common/spi"
@@ -126,3 +127,103 @@ func (m *ROSAProvider) RemoveUserCABundle(clusterId string) error {
 func (m *ROSAProvider) LoadUserCaBundleData(file string) (string, error) {
 	return m.ocmProvider.LoadUserCaBundleData(file)
 }
+
+// ScaleClusterContext will call ScaleClusterContext from the OCM provider.
+func (m *ROSAProvider) ScaleClusterContext(ctx context.Context, clusterID string, numComputeNodes int) error {
+	return m.ocmProvider.ScaleClusterContext(ctx, clusterID, numComputeNodes)
+}
+
+// ListClustersContext will call ListClustersContext from the OCM provider.
+func (m *ROSAProvider) ListClustersContext(ctx context.Context, query string) ([]*spi.Cluster, error) {
+	return m.ocmProvider.ListClustersContext(ctx, query)
+}
+
+// GetClusterContext will call GetClusterContext from the OCM provider.
+func (m *ROSAProvider) GetClusterContext(ctx context.Context, clusterID string) (*spi.Cluster, error) {
+	return m.ocmProvider.GetClusterContext(ctx, clusterID)
+}
+
+// ClusterKubeconfigContext will call ClusterKubeconfigContext from the OCM provider.
+func (m *ROSAProvider) ClusterKubeconfigContext(ctx context.Context, clusterID string) ([]byte, error) {
+	return m.ocmProvider.ClusterKubeconfigContext(ctx, clusterID)
+}
+
+// CheckQuotaContext will call CheckQuotaContext from the OCM provider.
+func (m *ROSAProvider) CheckQuotaContext(ctx context.Context, sku string) (bool, error) {
+	return m.ocmProvider.CheckQuotaContext(ctx, sku)
+}
+
+// InstallAddonsContext will call InstallAddonsContext from the OCM provider.
+func (m *ROSAProvider) InstallAddonsContext(ctx context.Context, clusterID string, addonIDs []spi.AddOnID, addonParams map[spi.AddOnID]spi.AddOnParams) (int, error) {
+	return m.ocmProvider.InstallAddonsContext(ctx, clusterID, addonIDs, addonParams)
+}
+
+// LogsContext will call LogsContext from the OCM provider.
+func (m *ROSAProvider) LogsContext(ctx context.Context, clusterID string) (map[string][]byte, error) {
+	return m.ocmProvider.LogsContext(ctx, clusterID)
+}
+
+// ExtendExpiryContext will call ExtendExpiryContext from the OCM provider.
+func (m *ROSAProvider) ExtendExpiryContext(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	return m.ocmProvider.ExtendExpiryContext(ctx, clusterID, hours, minutes, seconds)
+}
+
+// ExpireContext will call ExpireContext from the OCM provider.
+func (m *ROSAProvider) ExpireContext(ctx context.Context, clusterID string) error {
+	return m.ocmProvider.ExpireContext(ctx, clusterID)
+}
+
+// AddPropertyContext will call AddPropertyContext from the OCM provider.
+func (m *ROSAProvider) AddPropertyContext(ctx context.Context, cluster *spi.Cluster, tag string, value string) error {
+	return m.ocmProvider.AddPropertyContext(ctx, cluster, tag, value)
+}
+
+// UpgradeContext initiates a cluster upgrade from the OCM provider.
+func (m *ROSAProvider) UpgradeContext(ctx context.Context, clusterID string, version string, t time.Time) error {
+	return m.ocmProvider.UpgradeContext(ctx, clusterID, version, t)
+}
+
+// GetUpgradePolicyIDContext fetchs the upgrade policy from the OCM provider
+func (m *ROSAProvider) GetUpgradePolicyIDContext(ctx context.Context, clusterID string) (string, error) {
+	return m.ocmProvider.GetUpgradePolicyIDContext(ctx, clusterID)
+}
+
+// UpdateScheduleContext reschedules the upgrade via the OCM provider
+func (m *ROSAProvider) UpdateScheduleContext(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error {
+	return m.ocmProvider.UpdateScheduleContext(ctx, clusterID, version, t, policyID)
+}
+
+// DetermineMachineTypeContext calls DetermineMachineTypeContext from the OCM provider
+func (m *ROSAProvider) DetermineMachineTypeContext(ctx context.Context, cloudProvider string) (string, error) {
+	return m.ocmProvider.DetermineMachineTypeContext(ctx, cloudProvider)
+}
+
+// ResumeContext calls ResumeContext from the OCM provider
+func (m *ROSAProvider) ResumeContext(ctx context.Context, id string) bool {
+	return m.ocmProvider.ResumeContext(ctx, id)
+}
+
+// HibernateContext calls HibernateContext from the OCM provider
+func (m *ROSAProvider) HibernateContext(ctx context.Context, id string) bool {
+	return m.ocmProvider.HibernateContext(ctx, id)
+}
+
+// AddClusterProxyContext sets the cluster proxy configuration for the supplied cluster
+func (m *ROSAProvider) AddClusterProxyContext(ctx context.Context, clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+	return m.ocmProvider.AddClusterProxyContext(ctx, clusterId, httpsProxy, httpProxy, userCABundle)
+}
+
+// RemoveClusterProxyContext removes the cluster proxy configuration for the supplied cluster
+func (m *ROSAProvider) RemoveClusterProxyContext(ctx context.Context, clusterId string) error {
+	return m.ocmProvider.RemoveClusterProxyContext(ctx, clusterId)
+}
+
+// RemoveUserCABundleContext removes only the Additional Trusted CA Bundle from the cluster
+func (m *ROSAProvider) RemoveUserCABundleContext(ctx context.Context, clusterId string) error {
+	return m.ocmProvider.RemoveUserCABundleContext(ctx, clusterId)
+}
+
+// AvailabilityZonesContext will call AvailabilityZonesContext from the OCM provider.
+func (m *ROSAProvider) AvailabilityZonesContext(ctx context.Context, clusterID string) ([]string, error) {
+	return m.ocmProvider.AvailabilityZonesContext(ctx, clusterID)
+}
@@ -6,7 +6,8 @@ import (
 	"text/template"
 )
 
-// testCmd configures default Service Account as a kubeconfig, runs openshift-tests, and serves results over HTTP
+// testCmd configures default Service Account as a kubeconfig, runs openshift-tests, and serves results and their
+// manifest over HTTP
 const testCmd = `#!/usr/bin/env bash
 oc cluster-info
 
@@ -38,8 +39,15 @@ python-3*)
 	;;
 esac
 
+cd {{$outDir}}
+
+# list the size and checksum of each result so that downloads can be verified
+find . -type f ! -name '` + resultsManifest + `' -printf '%s %P\n' | while read -r size file; do
+	echo "$(sha256sum "$file" | cut -d ' ' -f 1) $size $file"
+done > /tmp/` + resultsManifest + ` && mv /tmp/` + resultsManifest + ` ` + resultsManifest + `
+
 # make results available using HTTP
-cd {{$outDir}} && echo "Starting server" && python -m "${MODULE}"
+echo "Starting server" && python -m "${MODULE}"
 `
 
 var cmdTemplate = template.Must(template.New("testCmd").Parse(testCmd))
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// debugLogLines is the number of log lines captured from each container of a runner that failed.
+const debugLogLines = 200
+
+// withDiagnostics captures the state of a runner Pod that failed to finish into <ReportDir>/<Name>-debug and adds a
+// summary of it to err.
+func (r *Runner) withDiagnostics(podName string, err error) error {
+	dir := filepath.Join(viper.GetString(config.ReportDir), r.Name+"-debug")
+	summary, captureErr := r.captureDiagnostics(context.TODO(), podName, dir)
+	if captureErr != nil {
+		r.Printf("Unable to capture all diagnostics for %s runner Pod: %v", r.Name, captureErr)
+	}
+	if summary == "" {
+		return err
+	}
+	return fmt.Errorf("%w (%s; diagnostics in %s)", err, summary, dir)
+}
+
+// captureDiagnostics writes the Pod, its events, the last lines of its containers' logs, the conditions of its node
+// and the state of the runner's endpoints to dir. It returns a summary of what is most likely wrong.
+func (r *Runner) captureDiagnostics(ctx context.Context, podName, dir string) (string, error) {
+	if err := os.MkdirAll(dir, os.FileMode(0o755)); err != nil {
+		return "", err
+	}
+
+	pod, err := r.Kube.CoreV1().Pods(r.Namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error getting pod: %v", err)
+	}
+
+	var summary []string
+	var describe bytes.Buffer
+	fmt.Fprintf(&describe, "Name:\t%s\nNamespace:\t%s\nNode:\t%s\nPhase:\t%s\n", pod.Name, pod.Namespace, pod.Spec.NodeName, pod.Status.Phase)
+	if pod.Status.Reason != "" || pod.Status.Message != "" {
+		fmt.Fprintf(&describe, "Reason:\t%s\nMessage:\t%s\n", pod.Status.Reason, pod.Status.Message)
+	}
+	summary = append(summary, fmt.Sprintf("pod %s is %s", pod.Name, pod.Status.Phase))
+
+	fmt.Fprintln(&describe, "Conditions:")
+	for _, condition := range pod.Status.Conditions {
+		fmt.Fprintf(&describe, "  %s=%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		if condition.Type == kubev1.PodScheduled && condition.Status != kubev1.ConditionTrue {
+			summary = append(summary, fmt.Sprintf("unschedulable: %s", condition.Message))
+		}
+	}
+
+	fmt.Fprintln(&describe, "Containers:")
+	statuses := append(append([]kubev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		state := containerState(status.State)
+		fmt.Fprintf(&describe, "  %s:\tready=%t\trestarts=%d\t%s\n", status.Name, status.Ready, status.RestartCount, state)
+		if status.LastTerminationState.Terminated != nil {
+			fmt.Fprintf(&describe, "    last state:\t%s\n", containerState(status.LastTerminationState))
+		}
+		if !status.Ready && status.State.Running == nil {
+			summary = append(summary, fmt.Sprintf("container %s %s", status.Name, state))
+		}
+		if status.RestartCount > 0 {
+			summary = append(summary, fmt.Sprintf("container %s restarted %d times", status.Name, status.RestartCount))
+		}
+	}
+
+	var errs []string
+	events, err := r.Kube.CoreV1().Events(r.Namespace).List(ctx, metav1.ListOptions{FieldSelector: "involvedObject.name=" + pod.Name})
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("error listing events: %v", err))
+	} else {
+		fmt.Fprintln(&describe, "Events:")
+		var podEvents []kubev1.Event
+		for _, event := range events.Items {
+			if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == pod.Name {
+				podEvents = append(podEvents, event)
+			}
+		}
+		sort.SliceStable(podEvents, func(i, j int) bool {
+			return podEvents[i].LastTimestamp.Before(&podEvents[j].LastTimestamp)
+		})
+		var lastWarning *kubev1.Event
+		for i, event := range podEvents {
+			fmt.Fprintf(&describe, "  %s\t%s\t%s (x%d)\t%s\n", event.LastTimestamp.UTC().Format("2006-01-02T15:04:05Z"), event.Type, event.Reason, event.Count, event.Message)
+			if event.Type == kubev1.EventTypeWarning {
+				lastWarning = &podEvents[i]
+			}
+		}
+		if lastWarning != nil {
+			summary = append(summary, fmt.Sprintf("last warning %s: %s", lastWarning.Reason, lastWarning.Message))
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "describe.txt"), describe.Bytes(), os.FileMode(0o644)); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := writeJSON(filepath.Join(dir, "pod.json"), pod); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// last lines of every container, and of the previous run of those that restarted
+	for _, status := range statuses {
+		if err := r.writeLogTail(ctx, pod.Name, status.Name, false, filepath.Join(dir, status.Name+".log")); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if status.RestartCount > 0 {
+			if err := r.writeLogTail(ctx, pod.Name, status.Name, true, filepath.Join(dir, status.Name+"-previous.log")); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if pod.Spec.NodeName != "" {
+		nodeSummary, err := r.writeNodeConditions(ctx, pod.Spec.NodeName, filepath.Join(dir, "node.txt"))
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		summary = append(summary, nodeSummary...)
+	}
+
+	if r.svc != nil {
+		endpointsSummary, err := r.writeEndpoints(ctx, filepath.Join(dir, "endpoints.txt"))
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		summary = append(summary, endpointsSummary)
+	}
+
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return strings.Join(summary, "; "), err
+}
+
+// writeLogTail writes the last lines of a container's logs to a file.
+func (r *Runner) writeLogTail(ctx context.Context, podName, container string, previous bool, name string) error {
+	tailLines := int64(debugLogLines)
+	stream, err := r.Kube.CoreV1().Pods(r.Namespace).GetLogs(podName, &kubev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+		Previous:  previous,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting logs of %s: %v", container, err)
+	}
+	defer stream.Close()
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, stream)
+	return err
+}
+
+// writeNodeConditions writes the conditions of a node to a file and returns those that are unhealthy.
+func (r *Runner) writeNodeConditions(ctx context.Context, nodeName, name string) ([]string, error) {
+	node, err := r.Kube.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting node %s: %v", nodeName, err)
+	}
+
+	var unhealthy []string
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Name:\t%s\nUnschedulable:\t%t\nConditions:\n", node.Name, node.Spec.Unschedulable)
+	for _, condition := range node.Status.Conditions {
+		fmt.Fprintf(&out, "  %s=%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		// Ready is the only condition that is healthy when true.
+		if (condition.Type == kubev1.NodeReady) != (condition.Status == kubev1.ConditionTrue) {
+			unhealthy = append(unhealthy, fmt.Sprintf("node %s %s=%s", node.Name, condition.Type, condition.Status))
+		}
+	}
+	return unhealthy, os.WriteFile(name, out.Bytes(), os.FileMode(0o644))
+}
+
+// writeEndpoints writes the addresses of the runner's endpoints to a file and returns how many are ready.
+func (r *Runner) writeEndpoints(ctx context.Context, name string) (string, error) {
+	endpoints, err := r.Kube.CoreV1().Endpoints(r.svc.Namespace).Get(ctx, r.svc.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Sprintf("no endpoints %s", r.svc.Name), fmt.Errorf("error getting endpoints %s: %v", r.svc.Name, err)
+	}
+
+	var ready, notReady int
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Name:\t%s\nSubsets:\n", endpoints.Name)
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			fmt.Fprintf(&out, "  ready\t%s\n", address.IP)
+		}
+		for _, address := range subset.NotReadyAddresses {
+			fmt.Fprintf(&out, "  not ready\t%s\n", address.IP)
+		}
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+	return fmt.Sprintf("endpoints %s have %d ready and %d not ready addresses", endpoints.Name, ready, notReady),
+		os.WriteFile(name, out.Bytes(), os.FileMode(0o644))
+}
+
+// containerState describes the state of a container.
+func containerState(state kubev1.ContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		return strings.TrimSpace(fmt.Sprintf("waiting: %s %s", state.Waiting.Reason, state.Waiting.Message))
+	case state.Terminated != nil:
+		return strings.TrimSpace(fmt.Sprintf("terminated: %s exit code %d %s", state.Terminated.Reason, state.Terminated.ExitCode, state.Terminated.Message))
+	case state.Running != nil:
+		return fmt.Sprintf("running since %s", state.Running.StartedAt.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	return "unknown"
+}
+
+func writeJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, os.FileMode(0o644))
+}
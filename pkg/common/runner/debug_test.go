@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWithDiagnostics(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.ReportDir, t.TempDir())
+
+	now := metav1.NewTime(time.Now())
+	pod := &kubev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-abcde", Namespace: "osde2e"},
+		Spec:       kubev1.PodSpec{NodeName: "worker-0", Containers: []kubev1.Container{{Name: "e2e"}}},
+		Status: kubev1.PodStatus{
+			Phase: kubev1.PodRunning,
+			InitContainerStatuses: []kubev1.ContainerStatus{
+				{Name: "workloads", State: kubev1.ContainerState{Terminated: &kubev1.ContainerStateTerminated{Reason: "Completed"}}, Ready: true},
+			},
+			ContainerStatuses: []kubev1.ContainerStatus{{
+				Name:                 "e2e",
+				RestartCount:         3,
+				State:                kubev1.ContainerState{Waiting: &kubev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: kubev1.ContainerState{Terminated: &kubev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			}},
+		},
+	}
+	node := &kubev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Status: kubev1.NodeStatus{Conditions: []kubev1.NodeCondition{
+			{Type: kubev1.NodeReady, Status: kubev1.ConditionTrue},
+			{Type: kubev1.NodeMemoryPressure, Status: kubev1.ConditionTrue},
+		}},
+	}
+	event := &kubev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "e2e-abcde.1", Namespace: "osde2e"},
+		InvolvedObject: kubev1.ObjectReference{Kind: "Pod", Name: "e2e-abcde"},
+		Type:           kubev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          3,
+		LastTimestamp:  now,
+	}
+	otherEvent := &kubev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "other.1", Namespace: "osde2e"},
+		InvolvedObject: kubev1.ObjectReference{Kind: "Pod", Name: "other"},
+		Type:           kubev1.EventTypeWarning,
+		Reason:         "Failed",
+		LastTimestamp:  now,
+	}
+	endpoints := &kubev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-fghij", Namespace: "osde2e"},
+		Subsets:    []kubev1.EndpointSubset{{NotReadyAddresses: []kubev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+
+	r := &Runner{
+		Name:      "e2e",
+		Namespace: "osde2e",
+		Kube:      fake.NewSimpleClientset(pod, node, event, otherEvent, endpoints),
+		Logger:    DefaultRunner.Logger,
+		svc:       &kubev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "e2e-fghij", Namespace: "osde2e"}},
+	}
+
+	timeout := errors.New("timed out waiting for the condition")
+	err := r.withDiagnostics(pod.Name, timeout)
+	if !errors.Is(err, timeout) {
+		t.Errorf("expected the original error to be wrapped, got %v", err)
+	}
+	for _, expected := range []string{
+		"pod e2e-abcde is Running",
+		"container e2e waiting: CrashLoopBackOff",
+		"container e2e restarted 3 times",
+		"last warning BackOff: Back-off restarting failed container",
+		"node worker-0 MemoryPressure=True",
+		"endpoints e2e-fghij have 0 ready and 1 not ready addresses",
+		filepath.Join(viper.GetString(config.ReportDir), "e2e-debug"),
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected the error to contain %q, got %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "workloads") || strings.Contains(err.Error(), "Failed") {
+		t.Errorf("expected completed init containers and other pods' events to be left out of the summary, got %v", err)
+	}
+
+	dir := filepath.Join(viper.GetString(config.ReportDir), "e2e-debug")
+	for file, expected := range map[string]string{
+		"describe.txt":     "last state:\tterminated: Error exit code 1",
+		"pod.json":         `"name": "e2e-abcde"`,
+		"e2e.log":          "fake logs",
+		"e2e-previous.log": "fake logs",
+		"workloads.log":    "fake logs",
+		"node.txt":         "MemoryPressure=True",
+		"endpoints.txt":    "not ready\t10.0.0.1",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("expected %s to be captured: %v", file, err)
+		} else if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %s to contain %q, got %s", file, expected, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "workloads-previous.log")); err == nil {
+		t.Error("expected no previous logs for a container that never restarted")
+	}
+}
+
+func TestWithDiagnosticsMissingPod(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.ReportDir, t.TempDir())
+
+	r := &Runner{Name: "e2e", Kube: fake.NewSimpleClientset(), Logger: DefaultRunner.Logger}
+	original := errors.New("pod failed")
+	if err := r.withDiagnostics("missing", original); err != original {
+		t.Errorf("expected the original error without diagnostics, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// streamLogs follows the logs of every container in a runner pod until the pod finishes or the returned function is
+// called. Each line is written with a runner and container prefix to a file in the report directory and, up to the
+// configured rate, to the build log. The returned function stops streaming and waits for the followers to exit; calls
+// after the first do nothing.
+func (r *Runner) streamLogs(pod *kubev1.Pod) (stop func(), err error) {
+	logDirectory := filepath.Join(viper.GetString(config.ReportDir), viper.GetString(config.Phase), containerLogs)
+	if err = os.MkdirAll(logDirectory, os.FileMode(0o755)); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(logDirectory, pod.Name+"-stream.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(0o644))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-r.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	out := &streamOutput{runner: r, file: file}
+	var wg sync.WaitGroup
+	for _, container := range pod.Spec.Containers {
+		wg.Add(1)
+		go func(container string) {
+			defer wg.Done()
+			r.followContainer(ctx, pod.Namespace, pod.Name, container, out, &lineLimiter{rate: viper.GetInt(config.Runner.StreamLogsRate)})
+		}(container.Name)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+			file.Close()
+		})
+	}, nil
+}
+
+// followContainer streams the logs of a container, reconnecting from the last line seen when the stream ends
+// early or the container restarts, until the context is done or the pod finishes.
+func (r *Runner) followContainer(ctx context.Context, namespace, podName, container string, out *streamOutput, limiter *lineLimiter) {
+	var last time.Time
+	for {
+		opts := &kubev1.PodLogOptions{Container: container, Follow: true, Timestamps: true}
+		if !last.IsZero() {
+			since := metav1.NewTime(last)
+			opts.SinceTime = &since
+		}
+
+		stream, err := r.Kube.CoreV1().Pods(namespace).GetLogs(podName, opts).Stream(ctx)
+		if err == nil {
+			last = out.copy(stream, container, last, limiter)
+			stream.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		pod, err := r.Kube.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err == nil && (pod.Status.Phase == kubev1.PodSucceeded || pod.Status.Phase == kubev1.PodFailed) {
+			out.flush(container, limiter)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(fastPoll):
+		}
+	}
+}
+
+// streamOutput writes the lines of every container in a runner pod to its stream file and the build log.
+type streamOutput struct {
+	runner *Runner
+
+	mu   sync.Mutex
+	file io.Writer
+}
+
+// copy writes the lines read from a timestamped log stream that are newer than last, and returns the timestamp of
+// the newest line written.
+func (o *streamOutput) copy(stream io.Reader, container string, last time.Time, limiter *lineLimiter) time.Time {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if timestamp, text, ok := strings.Cut(line, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+				// Reconnecting from the last timestamp seen repeats the lines up to it.
+				if !t.After(last) {
+					continue
+				}
+				last, line = t, text
+			}
+		}
+		o.write(container, line, limiter)
+	}
+	return last
+}
+
+func (o *streamOutput) write(container, line string, limiter *lineLimiter) {
+	prefix := fmt.Sprintf("[%s/%s] ", o.runner.Name, container)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fmt.Fprintln(o.file, prefix+line)
+
+	allowed, suppressed := limiter.allow(time.Now())
+	if suppressed > 0 {
+		o.runner.Printf("%s%d lines suppressed from the build log", prefix, suppressed)
+	}
+	if allowed {
+		o.runner.Print(prefix + line)
+	}
+}
+
+// flush reports any lines still suppressed by the limiter once a container has no more logs.
+func (o *streamOutput) flush(container string, limiter *lineLimiter) {
+	if suppressed := limiter.reset(); suppressed > 0 {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.runner.Printf("[%s/%s] %d lines suppressed from the build log", o.runner.Name, container, suppressed)
+	}
+}
+
+// lineLimiter allows up to rate lines per second, counting the lines it drops. A rate of zero or less allows every line.
+type lineLimiter struct {
+	rate       int
+	window     time.Time
+	count      int
+	suppressed int
+}
+
+// allow reports whether a line seen at now may be written, and the number of lines dropped in the previous window
+// when now starts a new one.
+func (l *lineLimiter) allow(now time.Time) (allowed bool, suppressed int) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+	if now.Sub(l.window) >= time.Second {
+		suppressed = l.reset()
+		l.window = now
+	}
+	if l.count < l.rate {
+		l.count++
+		return true, suppressed
+	}
+	l.suppressed++
+	return false, suppressed
+}
+
+// reset starts a new window and returns the number of lines dropped in the last one.
+func (l *lineLimiter) reset() int {
+	suppressed := l.suppressed
+	l.count, l.suppressed = 0, 0
+	return suppressed
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLineLimiter(t *testing.T) {
+	limiter := &lineLimiter{rate: 2}
+	start := time.Now()
+
+	var allowed []bool
+	for i := 0; i < 4; i++ {
+		ok, suppressed := limiter.allow(start.Add(time.Duration(i) * time.Millisecond))
+		if suppressed != 0 {
+			t.Errorf("line %d: expected nothing reported as suppressed within the first second, got %d", i, suppressed)
+		}
+		allowed = append(allowed, ok)
+	}
+	if !allowed[0] || !allowed[1] || allowed[2] || allowed[3] {
+		t.Errorf("expected only the first two lines to be allowed, got %v", allowed)
+	}
+
+	if ok, suppressed := limiter.allow(start.Add(time.Second)); !ok || suppressed != 2 {
+		t.Errorf("expected a new window to allow the line and report 2 suppressed, got %v and %d", ok, suppressed)
+	}
+
+	unlimited := &lineLimiter{}
+	for i := 0; i < 100; i++ {
+		if ok, _ := unlimited.allow(start); !ok {
+			t.Fatal("expected no limit with a rate of 0")
+		}
+	}
+}
+
+func TestStreamOutputCopy(t *testing.T) {
+	var file, buildLog bytes.Buffer
+	out := &streamOutput{runner: &Runner{Name: "e2e", Logger: log.New(&buildLog, "", 0)}, file: &file}
+
+	last, _ := time.Parse(time.RFC3339Nano, "2022-01-01T00:00:01.5Z")
+	stream := strings.NewReader(strings.Join([]string{
+		"2022-01-01T00:00:01.000000000Z already seen",
+		"2022-01-01T00:00:01.500000000Z already seen",
+		"2022-01-01T00:00:02.000000000Z first",
+		"2022-01-01T00:00:03.000000000Z second",
+		"no timestamp",
+	}, "\n"))
+
+	newest := out.copy(stream, "tests", last, &lineLimiter{rate: 2})
+	if expected, _ := time.Parse(time.RFC3339, "2022-01-01T00:00:03Z"); !newest.Equal(expected) {
+		t.Errorf("expected the newest timestamp to be %v, got %v", expected, newest)
+	}
+
+	if expected := "[e2e/tests] first\n[e2e/tests] second\n[e2e/tests] no timestamp\n"; file.String() != expected {
+		t.Errorf("expected file contents %q, got %q", expected, file.String())
+	}
+	if expected := "[e2e/tests] first\n[e2e/tests] second\n"; buildLog.String() != expected {
+		t.Errorf("expected the build log to be rate limited to %q, got %q", expected, buildLog.String())
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.ReportDir, t.TempDir())
+	viper.Set(config.Phase, "install")
+
+	pod := &kubev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-abcde", Namespace: "default"},
+		Spec:       kubev1.PodSpec{Containers: []kubev1.Container{{Name: "tests"}}},
+		Status:     kubev1.PodStatus{Phase: kubev1.PodSucceeded},
+	}
+
+	var buildLog bytes.Buffer
+	r := &Runner{Name: "e2e", Kube: fake.NewSimpleClientset(pod), Logger: log.New(&buildLog, "", 0)}
+
+	stop, err := r.streamLogs(pod)
+	if err != nil {
+		t.Fatalf("unable to stream logs: %v", err)
+	}
+
+	logFile := filepath.Join(viper.GetString(config.ReportDir), "install", containerLogs, "e2e-abcde-stream.log")
+	var data []byte
+	for deadline := time.Now().Add(10 * time.Second); len(data) == 0 && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if data, err = os.ReadFile(logFile); err != nil {
+			t.Fatalf("unable to read streamed logs: %v", err)
+		}
+	}
+	stop()
+	// Run stops streaming once the pod completes and again on return.
+	stop()
+
+	if !strings.Contains(string(data), "[e2e/tests] fake logs") {
+		t.Errorf("expected prefixed logs in the stream file, got %q", data)
+	}
+	if !strings.Contains(buildLog.String(), "[e2e/tests] fake logs") {
+		t.Errorf("expected prefixed logs in the build log, got %q", buildLog.String())
+	}
+}
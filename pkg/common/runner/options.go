@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+// infraNodeLabel selects infra nodes, which are also tainted with it.
+const infraNodeLabel = "node-role.kubernetes.io/infra"
+
+// PodOptions control the resources, placement and security of a runner's Pod.
+type PodOptions struct {
+	// Resources of the runner container.
+	Resources kubev1.ResourceRequirements
+
+	// InfraNodes schedules the Pod on infra nodes, tolerating their taint.
+	InfraNodes bool
+
+	// Tolerations are added to the Pod.
+	Tolerations []kubev1.Toleration
+
+	// PriorityClassName of the Pod.
+	PriorityClassName string
+
+	// Restricted runs every container as a non-root user without privileges, as the restricted SCC requires.
+	Restricted bool
+}
+
+// PodOptionsFromConfig reads the Pod options for a kind of runner, falling back to the options of every runner
+// for those that aren't set.
+func PodOptionsFromConfig(keys config.RunnerPodOptions) (options PodOptions, err error) {
+	defaults := config.Runner.Pod
+	get := func(key, fallback string) string {
+		if viper.IsSet(key) {
+			return viper.GetString(key)
+		}
+		return viper.GetString(fallback)
+	}
+	getBool := func(key, fallback string) bool {
+		if viper.IsSet(key) {
+			return viper.GetBool(key)
+		}
+		return viper.GetBool(fallback)
+	}
+
+	if options.Resources.Requests, err = parseResourceList(get(keys.Requests, defaults.Requests)); err != nil {
+		return options, fmt.Errorf("invalid runner resource requests: %v", err)
+	}
+	if options.Resources.Limits, err = parseResourceList(get(keys.Limits, defaults.Limits)); err != nil {
+		return options, fmt.Errorf("invalid runner resource limits: %v", err)
+	}
+	if options.Tolerations, err = parseTolerations(get(keys.Tolerations, defaults.Tolerations)); err != nil {
+		return options, fmt.Errorf("invalid runner tolerations: %v", err)
+	}
+	options.InfraNodes = getBool(keys.InfraNodes, defaults.InfraNodes)
+	options.PriorityClassName = get(keys.PriorityClass, defaults.PriorityClass)
+	options.Restricted = getBool(keys.Restricted, defaults.Restricted)
+	return options, nil
+}
+
+// apply sets the options on a Pod spec whose runner container is named container.
+func (o PodOptions) apply(spec *kubev1.PodSpec, container string) {
+	if len(o.Resources.Requests) > 0 || len(o.Resources.Limits) > 0 {
+		for i := range spec.Containers {
+			if spec.Containers[i].Name == container {
+				spec.Containers[i].Resources = o.Resources
+			}
+		}
+	}
+
+	tolerations := append([]kubev1.Toleration{}, spec.Tolerations...)
+	if o.InfraNodes {
+		nodeSelector := map[string]string{infraNodeLabel: ""}
+		for key, value := range spec.NodeSelector {
+			nodeSelector[key] = value
+		}
+		spec.NodeSelector = nodeSelector
+		tolerations = append(tolerations, kubev1.Toleration{
+			Key:      infraNodeLabel,
+			Operator: kubev1.TolerationOpExists,
+			Effect:   kubev1.TaintEffectNoSchedule,
+		})
+	}
+	if tolerations = append(tolerations, o.Tolerations...); len(tolerations) > 0 {
+		spec.Tolerations = tolerations
+	}
+
+	if o.PriorityClassName != "" {
+		spec.PriorityClassName = o.PriorityClassName
+	}
+
+	if o.Restricted {
+		spec.SecurityContext = &kubev1.PodSecurityContext{
+			RunAsNonRoot:   pointer.BoolPtr(true),
+			SeccompProfile: &kubev1.SeccompProfile{Type: kubev1.SeccompProfileTypeRuntimeDefault},
+		}
+		// The restricted SCC assigns the user, so none may be requested.
+		for _, containers := range [][]kubev1.Container{spec.InitContainers, spec.Containers} {
+			for i := range containers {
+				containers[i].SecurityContext = &kubev1.SecurityContext{
+					RunAsNonRoot:             pointer.BoolPtr(true),
+					AllowPrivilegeEscalation: pointer.BoolPtr(false),
+					Capabilities:             &kubev1.Capabilities{Drop: []kubev1.Capability{"ALL"}},
+				}
+			}
+		}
+	}
+}
+
+// parseResourceList parses comma separated name=quantity pairs.
+func parseResourceList(s string) (kubev1.ResourceList, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	resources := kubev1.ResourceList{}
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not name=quantity", pair)
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for %s: %v", name, err)
+		}
+		resources[kubev1.ResourceName(name)] = quantity
+	}
+	return resources, nil
+}
+
+// parseTolerations parses comma separated key[=value]:effect taints. A taint without a value is tolerated
+// whatever its value.
+func parseTolerations(s string) ([]kubev1.Toleration, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var tolerations []kubev1.Toleration
+	for _, taint := range strings.Split(s, ",") {
+		taint = strings.TrimSpace(taint)
+		keyValue, effect, ok := strings.Cut(taint, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q has no effect", taint)
+		}
+		toleration := kubev1.Toleration{Effect: kubev1.TaintEffect(effect), Operator: kubev1.TolerationOpExists}
+		switch toleration.Effect {
+		case kubev1.TaintEffectNoSchedule, kubev1.TaintEffectPreferNoSchedule, kubev1.TaintEffectNoExecute:
+		default:
+			return nil, fmt.Errorf("%q has an unknown effect %q", taint, effect)
+		}
+		if key, value, ok := strings.Cut(keyValue, "="); ok {
+			toleration.Key, toleration.Value, toleration.Operator = key, value, kubev1.TolerationOpEqual
+		} else {
+			toleration.Key = keyValue
+		}
+		if toleration.Key == "" {
+			return nil, fmt.Errorf("%q has no key", taint)
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
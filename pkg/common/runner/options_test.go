@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
+	kubev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseResourceList(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected kubev1.ResourceList
+		err      bool
+	}{
+		{"", nil, false},
+		{"cpu=500m, memory=1Gi", kubev1.ResourceList{kubev1.ResourceCPU: resource.MustParse("500m"), kubev1.ResourceMemory: resource.MustParse("1Gi")}, false},
+		{"cpu", nil, true},
+		{"memory=lots", nil, true},
+	}
+
+	for _, test := range tests {
+		resources, err := parseResourceList(test.input)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error result %v", test.input, err)
+		}
+		if !reflect.DeepEqual(resources, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, resources)
+		}
+	}
+}
+
+func TestParseTolerations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []kubev1.Toleration
+		err      bool
+	}{
+		{"", nil, false},
+		{
+			"dedicated=e2e:NoSchedule,node.kubernetes.io/unreachable:NoExecute",
+			[]kubev1.Toleration{
+				{Key: "dedicated", Value: "e2e", Operator: kubev1.TolerationOpEqual, Effect: kubev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/unreachable", Operator: kubev1.TolerationOpExists, Effect: kubev1.TaintEffectNoExecute},
+			},
+			false,
+		},
+		{"dedicated=e2e", nil, true},
+		{"dedicated:Sometimes", nil, true},
+		{":NoSchedule", nil, true},
+	}
+
+	for _, test := range tests {
+		tolerations, err := parseTolerations(test.input)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error result %v", test.input, err)
+		}
+		if !reflect.DeepEqual(tolerations, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, tolerations)
+		}
+	}
+}
+
+func TestPodOptionsFromConfig(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(config.Runner.Pod.Requests, "cpu=100m")
+	viper.Set(config.Runner.Pod.PriorityClass, "e2e")
+	viper.Set(config.Runner.MustGather.Requests, "memory=2Gi")
+	viper.Set(config.Runner.MustGather.InfraNodes, true)
+
+	options, err := PodOptionsFromConfig(config.Runner.MustGather)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := PodOptions{
+		Resources:         kubev1.ResourceRequirements{Requests: kubev1.ResourceList{kubev1.ResourceMemory: resource.MustParse("2Gi")}},
+		InfraNodes:        true,
+		PriorityClassName: "e2e",
+	}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("expected must-gather options %+v, got %+v", expected, options)
+	}
+
+	if options, err = PodOptionsFromConfig(config.Runner.Scale); err != nil || options.InfraNodes || options.Resources.Requests.Cpu().String() != "100m" {
+		t.Errorf("expected scale options to fall back to those of every runner, got %+v: %v", options, err)
+	}
+
+	viper.Set(config.Runner.Addons.Tolerations, "dedicated")
+	if _, err = PodOptionsFromConfig(config.Runner.Addons); err == nil {
+		t.Error("expected an error for invalid tolerations")
+	}
+}
+
+func TestCreatePodWithOptions(t *testing.T) {
+	def := *DefaultRunner
+	r := &def
+	r.Kube = fake.NewSimpleClientset()
+	r.PodSpec = *DefaultRunner.PodSpec.DeepCopy()
+	r.Repos = Repos{{Name: "workloads", URL: "https://example.com/workloads.git", MountPath: "/workloads"}}
+	r.PodOptions = PodOptions{
+		Resources:         kubev1.ResourceRequirements{Requests: kubev1.ResourceList{kubev1.ResourceCPU: resource.MustParse("1")}},
+		InfraNodes:        true,
+		Tolerations:       []kubev1.Toleration{{Key: "dedicated", Operator: kubev1.TolerationOpExists, Effect: kubev1.TaintEffectNoSchedule}},
+		PriorityClassName: "e2e",
+		Restricted:        true,
+	}
+
+	pod, err := r.createPod()
+	if err != nil {
+		t.Fatalf("Failed to create example pod: %v", err)
+	}
+
+	if pod.Spec.Containers[0].Resources.Requests.Cpu().String() != "1" {
+		t.Errorf("expected the runner container to request 1 CPU, got %v", pod.Spec.Containers[0].Resources)
+	}
+	if _, ok := pod.Spec.NodeSelector[infraNodeLabel]; !ok || len(pod.Spec.Tolerations) != 2 || pod.Spec.Tolerations[0].Key != infraNodeLabel {
+		t.Errorf("expected the pod to be placed on infra nodes, got selector %v and tolerations %v", pod.Spec.NodeSelector, pod.Spec.Tolerations)
+	}
+	if pod.Spec.PriorityClassName != "e2e" {
+		t.Errorf("expected priority class e2e, got %q", pod.Spec.PriorityClassName)
+	}
+
+	if pod.Spec.SecurityContext == nil || !*pod.Spec.SecurityContext.RunAsNonRoot {
+		t.Errorf("expected the pod to run as non-root, got %v", pod.Spec.SecurityContext)
+	}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		sc := container.SecurityContext
+		if sc == nil || sc.RunAsUser != nil || *sc.AllowPrivilegeEscalation || len(sc.Capabilities.Drop) != 1 {
+			t.Errorf("expected container %s to have a restricted security context, got %v", container.Name, sc)
+		}
+	}
+	if DefaultContainer.SecurityContext.RunAsUser == nil {
+		t.Error("expected the default container to be left as it was")
+	}
+}
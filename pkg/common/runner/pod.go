@@ -153,6 +153,9 @@ func (r *Runner) createPod() (pod *kubev1.Pod, err error) {
 	// setup git repos to be cloned in init containers
 	r.Repos.ConfigurePod(&pod.Spec)
 
+	// set resources, placement and security
+	r.PodOptions.apply(&pod.Spec, r.Name)
+
 	// retry until Pod can be created or timeout occurs
 	var createdPod *kubev1.Pod
 	err = wait.PollImmediate(fastPoll, podCreateTimeout, func() (done bool, err error) {
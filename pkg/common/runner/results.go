@@ -1,29 +1,53 @@
 package runner
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	junit "github.com/joshdk/go-junit"
-	"golang.org/x/net/html"
 	"k8s.io/apimachinery/pkg/util/wait"
-	restclient "k8s.io/client-go/rest"
+)
+
+const (
+	// resultsManifest lists the size and SHA-256 checksum of every file served by a runner.
+	resultsManifest = ".osde2e-manifest"
+
+	// resultsManifestTimeout is how long to wait for the manifest to be served.
+	resultsManifestTimeout = 5 * time.Minute
+
+	// resultsAttempts is the number of times a file is downloaded before giving up on it.
+	resultsAttempts = 5
 )
 
 var (
 	resultsPortStr = strconv.Itoa(resultsPort)
 
+	// resultsRetryInterval is the time between attempts to download the manifest or a file.
+	resultsRetryInterval = fastPoll
+
 	errNotRun = errors.New("suite has not run yet")
 )
 
+// resultFile is an entry of the results manifest.
+type resultFile struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
 func ensurePassingXML(results map[string][]byte) (hadXML bool, err error) {
 	// ensure the junit xml indicates a passing job
 	var match bool
@@ -55,13 +79,24 @@ func ensurePassingXML(results map[string][]byte) (hadXML bool, err error) {
 	return
 }
 
-// RetrieveResults gathers the results from the test Pod. Should only be called after tests are finished.
+// RetrieveResults gathers the results from the test Pod into memory. Should only be called after tests are finished.
+// DownloadResults should be preferred for results that only need to be written to disk.
 func (r *Runner) RetrieveResults() (map[string][]byte, error) {
-	results, err := r.retrieveResultsForDirectory("")
+	dir, err := os.MkdirTemp("", r.Name+"-results-")
 	if err != nil {
 		return nil, fmt.Errorf("failed retrieving results: %w", err)
 	}
-	return results, err
+	defer os.RemoveAll(dir)
+
+	files, downloadErr := r.DownloadResults(dir)
+	results, err := readResults(dir, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving results: %w", err)
+	}
+	if downloadErr != nil {
+		return results, fmt.Errorf("failed retrieving results: %w", downloadErr)
+	}
+	return results, nil
 }
 
 // RetrieveTestResults gathers and validates the results from the test Pod. Should only be called after tests are finished. This method both fetches the results and ensures that they contain valid JUnit XML indicating that all tests passed.
@@ -80,89 +115,201 @@ func (r *Runner) RetrieveTestResults() (map[string][]byte, error) {
 	return results, err
 }
 
-func (r *Runner) retrieveResultsForDirectory(directory string) (map[string][]byte, error) {
-	var rdr io.ReadCloser
-	var resp restclient.ResponseWrapper
-	var err error
+// DownloadResults streams the results from the test Pod into dir, verifying the size and checksum of each file
+// and retrying files that fail. Files already in dir with the expected checksum are kept, so an interrupted
+// download can be resumed. It returns the paths of the files, relative to dir, that were downloaded. Should only
+// be called after tests are finished.
+func (r *Runner) DownloadResults(dir string) ([]string, error) {
 	if r.svc == nil {
 		return nil, errNotRun
 	}
 
-	// request result list
+	manifest, err := r.getResultsManifest()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve results manifest: %w", err)
+	}
+
+	var total int64
+	for _, file := range manifest {
+		total += file.Size
+	}
+	if r.MaxResultsSize > 0 && total > r.MaxResultsSize {
+		return nil, fmt.Errorf("results total %d bytes, more than the limit of %d", total, r.MaxResultsSize)
+	}
+
+	var files []string
+	var allErrors *multierror.Error
+	for _, file := range manifest {
+		if err := r.downloadResult(dir, file); err != nil {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("%s: %w", file.Path, err))
+			continue
+		}
+		files = append(files, file.Path)
+	}
+	return files, allErrors.ErrorOrNil()
+}
+
+// DownloadTestResults downloads the results from the test Pod into dir and ensures that they contain valid JUnit
+// XML indicating that all tests passed. Should only be called after tests are finished.
+func (r *Runner) DownloadTestResults(dir string) ([]string, error) {
+	files, err := r.DownloadResults(dir)
+	if err != nil {
+		return files, fmt.Errorf("failed downloading results: %w", err)
+	}
+
+	var junitFiles []string
+	for _, file := range files {
+		if match, _ := filepath.Match("junit*.xml", file); match {
+			junitFiles = append(junitFiles, file)
+		}
+	}
+	results, err := readResults(dir, junitFiles)
+	if err != nil {
+		return files, fmt.Errorf("failed reading Junit XML report: %w", err)
+	}
+
+	hadXML, err := ensurePassingXML(results)
+	if err != nil {
+		return files, fmt.Errorf("failed checking results for Junit XML report: %w", err)
+	}
+	if !hadXML {
+		return files, fmt.Errorf("results did not contain Junit XML report")
+	}
+	return files, nil
+}
+
+// getResultsManifest returns the files listed in the results manifest, waiting for it to be served.
+func (r *Runner) getResultsManifest() (manifest []resultFile, err error) {
 	// sometimes it is possible for the service/endpoint to not be ready before the results are finished.
-	// we loop through here five times with a sleep statement to check.
-	wait.PollImmediate(5*time.Second, 1*time.Minute, func() (bool, error) {
-		resp = r.Kube.CoreV1().Services(r.Namespace).ProxyGet("http", r.svc.Name, resultsPortStr, directory, nil)
-		rdr, err = resp.Stream(context.TODO())
+	pollErr := wait.PollImmediate(resultsRetryInterval, resultsManifestTimeout, func() (bool, error) {
+		var stream io.ReadCloser
+		stream, err = r.Kube.CoreV1().Services(r.Namespace).ProxyGet("http", r.svc.Name, resultsPortStr, resultsManifest, nil).Stream(context.TODO())
 		if err != nil {
 			return false, nil
 		}
-		return true, nil
+		defer stream.Close()
+
+		manifest, err = parseResultsManifest(stream)
+		return err == nil, err
 	})
+	if err == nil {
+		err = pollErr
+	}
+	return manifest, err
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve result file listing: %v", err)
+// parseResultsManifest reads a manifest with a line of "<sha256> <size> <path>" for every file.
+func parseResultsManifest(rdr io.Reader) ([]resultFile, error) {
+	var manifest []resultFile
+	scanner := bufio.NewScanner(rdr)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid manifest entry %q", scanner.Text())
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size in manifest entry %q: %v", scanner.Text(), err)
+		}
+		file := path.Clean(fields[2])
+		if path.IsAbs(file) || file == "." || file == ".." || strings.HasPrefix(file, "../") {
+			return nil, fmt.Errorf("manifest entry %q is outside of the results", fields[2])
+		}
+		manifest = append(manifest, resultFile{Path: file, Size: size, SHA256: fields[0]})
 	}
+	return manifest, scanner.Err()
+}
 
-	// parse list
-	n, err := html.Parse(rdr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse result file listing: %v", err)
-	} else if err = rdr.Close(); err != nil {
-		return nil, err
+// downloadResult streams a file from the test Pod into dir, retrying until its size and checksum match the manifest.
+func (r *Runner) downloadResult(dir string, file resultFile) error {
+	if r.MaxResultFileSize > 0 && file.Size > r.MaxResultFileSize {
+		return fmt.Errorf("%d bytes is more than the limit of %d", file.Size, r.MaxResultFileSize)
 	}
 
-	// download each file
-	results := map[string][]byte{}
-	if err = r.downloadLinks(n, results, directory); err != nil {
-		return results, fmt.Errorf("encountered error downloading results: %v", err)
+	dst := filepath.Join(dir, filepath.FromSlash(file.Path))
+	if checksum, err := fileChecksum(dst); err == nil && checksum == file.SHA256 {
+		log.Printf("Already downloaded %s", file.Path)
+		return nil
 	}
-	return results, nil
+	if err := os.MkdirAll(filepath.Dir(dst), os.FileMode(0o755)); err != nil {
+		return err
+	}
+
+	var err error
+	for attempt := 1; attempt <= resultsAttempts; attempt++ {
+		log.Printf("Downloading %s (%d bytes)", file.Path, file.Size)
+		if err = r.downloadResultOnce(dst, file); err == nil {
+			return nil
+		}
+		log.Printf("Attempt %d of %d to download %s failed: %v", attempt, resultsAttempts, file.Path, err)
+		if attempt < resultsAttempts {
+			time.Sleep(resultsRetryInterval)
+		}
+	}
+	return err
 }
 
-// downloadLinks, given an html page, will download all present links.
-// This is useful when a pod is publishing an html list of artifacts.
-func (r *Runner) downloadLinks(n *html.Node, results map[string][]byte, directory string) error {
-	if n.Type == html.ElementNode && n.Data == "a" {
-		for _, a := range n.Attr {
-			if a.Key == "href" {
-				if strings.HasSuffix(a.Val, "/") {
-					var newDirectory string
-					if directory != "" {
-						newDirectory = a.Val
-					} else {
-						newDirectory = path.Join(directory, a.Val)
-					}
+func (r *Runner) downloadResultOnce(dst string, file resultFile) error {
+	stream, err := r.Kube.CoreV1().Services(r.Namespace).ProxyGet("http", r.svc.Name, resultsPortStr, file.Path, nil).Stream(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
 
-					log.Println("Downloading directory " + newDirectory)
-					directoryResults, err := r.retrieveResultsForDirectory(newDirectory)
-					if err != nil {
-						log.Printf("error while getting results for directory %s: %v", newDirectory, err)
-						continue
-					}
+	// Write to a partial file so that a failed download never leaves a corrupt result behind.
+	partial := dst + ".part"
+	out, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(partial)
 
-					for k, v := range directoryResults {
-						results[k] = v
-					}
-				} else {
-					resp := r.Kube.CoreV1().Services(r.Namespace).ProxyGet("http", r.svc.Name, resultsPortStr, path.Join(directory, a.Val), nil)
-					data, err := resp.DoRaw(context.TODO())
-					if err != nil {
-						return err
-					}
+	hash := sha256.New()
+	// Read one more byte than expected to catch files that grew past their manifest size.
+	written, err := io.Copy(io.MultiWriter(out, hash), io.LimitReader(stream, file.Size+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
 
-					filename := a.Val
-					log.Println("Downloading " + filename)
-					results[path.Join(directory, filename)] = data
-				}
-			}
-		}
+	if written != file.Size {
+		return fmt.Errorf("expected %d bytes, got %d", file.Size, written)
 	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != file.SHA256 {
+		return fmt.Errorf("expected checksum %s, got %s", file.SHA256, checksum)
+	}
+	return os.Rename(partial, dst)
+}
+
+// fileChecksum returns the hex encoded SHA-256 checksum of a file.
+func fileChecksum(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
 
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if err := r.downloadLinks(c, results, directory); err != nil {
-			log.Printf("error while getting results for %s: %v", directory, err)
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readResults reads downloaded files into memory, keyed by their path relative to dir.
+func readResults(dir string, files []string) (map[string][]byte, error) {
+	results := make(map[string][]byte, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return results, err
 		}
+		results[file] = data
 	}
-	return nil
+	return results, nil
 }
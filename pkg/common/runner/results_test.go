@@ -3,12 +3,18 @@ package runner
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
-	"text/template"
+	"time"
 
 	kubev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -16,18 +22,12 @@ import (
 	kubetest "k8s.io/client-go/testing"
 )
 
-func resultString(files ...string) string {
+func manifest(results map[string][]byte) []byte {
 	var b bytes.Buffer
-	resultTemplate.Execute(&b, struct{ Files []string }{files})
-	return b.String()
-}
-
-func keys(in map[string][]byte) []string {
-	out := make([]string, 0, len(in))
-	for key := range in {
-		out = append(out, key)
+	for file, data := range results {
+		fmt.Fprintf(&b, "%x %d %s\n", sha256.Sum256(data), len(data), file)
 	}
-	return out
+	return b.Bytes()
 }
 
 var (
@@ -84,22 +84,6 @@ var (
 
 	badXML = `<testsuite name="Suite" tests="1" failures="0" errors="0" time="0">
     <test`
-
-	resultTemplate = template.Must(template.New("results").Parse(`
-<!DOCTYPE html PUBLIC "-//W3C//DTD HTML 3.2 Final//EN"><html>
-<title>Directory listing for /</title>
-<body>
-<h2>Directory listing for /</h2>
-<hr>
-<ul>
-{{ range .Files }}
-<li><a href="{{.}}">{{.}}</a></li>
-{{end}}
-</ul>
-<hr>
-</body>
-</html>
-`))
 )
 
 func TestRetrieveTestResults(t *testing.T) {
@@ -137,8 +121,8 @@ func TestRetrieveTestResults(t *testing.T) {
 	} {
 		t.Run(testcase.Name, func(t *testing.T) {
 			reactor := ResultsServerReactor{
-				IndexPage: response(resultString(keys(testcase.Expected)...)),
-				Results:   testcase.Expected,
+				Manifest: manifest(testcase.Expected),
+				Results:  testcase.Expected,
 			}
 			// setup mock client
 			client := fake.NewSimpleClientset()
@@ -177,8 +161,11 @@ func TestRetrieveTestResults(t *testing.T) {
 }
 
 type ResultsServerReactor struct {
-	IndexPage rest.ResponseWrapper
-	Results   map[string][]byte
+	Manifest []byte
+	Results  map[string][]byte
+
+	// Requests counts the requests for each path.
+	Requests map[string]int
 }
 
 func (r ResultsServerReactor) React(action kubetest.Action) (handled bool, ret rest.ResponseWrapper, err error) {
@@ -190,8 +177,11 @@ func (r ResultsServerReactor) React(action kubetest.Action) (handled bool, ret r
 	}
 
 	path := strings.TrimPrefix(proxyAction.Path, "/")
-	if path == "" {
-		ret = r.IndexPage
+	if r.Requests != nil {
+		r.Requests[path]++
+	}
+	if path == resultsManifest {
+		ret = response(r.Manifest)
 	} else if data, ok := r.Results[path]; ok {
 		ret = response(data)
 	} else {
@@ -216,3 +206,138 @@ func (r response) Stream(context.Context) (io.ReadCloser, error) {
 	}
 	return io.NopCloser(bytes.NewReader(r)), nil
 }
+
+func TestDownloadResults(t *testing.T) {
+	defer func(interval time.Duration) { resultsRetryInterval = interval }(resultsRetryInterval)
+	resultsRetryInterval = time.Millisecond
+
+	results := map[string][]byte{
+		"a":                   []byte("testdata"),
+		"nested/dir/b":        []byte("moretestdata"),
+		"junit-results.xml":   []byte(goodXML),
+		"must-gather/big.tgz": []byte("archive"),
+	}
+
+	tests := []struct {
+		Name     string
+		Manifest []byte
+		Runner   Runner
+		Existing map[string][]byte
+		Expected []string
+		Requests map[string]int
+		Error    string
+	}{
+		{
+			Name:     "allFiles",
+			Manifest: manifest(results),
+			Expected: []string{"a", "junit-results.xml", "must-gather/big.tgz", "nested/dir/b"},
+		},
+		{
+			Name:     "resumed",
+			Manifest: manifest(results),
+			Existing: map[string][]byte{"a": []byte("testdata"), "nested/dir/b": []byte("partial")},
+			Expected: []string{"a", "junit-results.xml", "must-gather/big.tgz", "nested/dir/b"},
+			Requests: map[string]int{"a": 0, "nested/dir/b": 1},
+		},
+		{
+			Name:     "checksumMismatch",
+			Manifest: []byte(fmt.Sprintf("%x 8 a\n", sha256.Sum256([]byte("otherdata")))),
+			Requests: map[string]int{"a": resultsAttempts},
+			Error:    "expected checksum",
+		},
+		{
+			Name:     "sizeMismatch",
+			Manifest: []byte(fmt.Sprintf("%x 4 a\n", sha256.Sum256([]byte("testdata")))),
+			Error:    "expected 4 bytes, got 5",
+		},
+		{
+			Name:     "fileTooLarge",
+			Manifest: manifest(results),
+			Runner:   Runner{MaxResultFileSize: 10},
+			Expected: []string{"a", "must-gather/big.tgz"},
+			Requests: map[string]int{"junit-results.xml": 0, "nested/dir/b": 0},
+			Error:    "more than the limit of 10",
+		},
+		{
+			Name:     "resultsTooLarge",
+			Manifest: manifest(results),
+			Runner:   Runner{MaxResultsSize: 20},
+			Error:    "more than the limit of 20",
+		},
+		{
+			Name:     "outsideResults",
+			Manifest: []byte(fmt.Sprintf("%x 8 ../a\n", sha256.Sum256([]byte("testdata")))),
+			Error:    "outside of the results",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			reactor := ResultsServerReactor{Manifest: test.Manifest, Results: results, Requests: map[string]int{}}
+			client := fake.NewSimpleClientset()
+			client.AddProxyReactor("services", reactor.React)
+
+			r := &test.Runner
+			r.Kube = client
+			svc, err := r.createService(new(kubev1.Pod))
+			if err != nil {
+				t.Fatalf("Failed to create example service: %v", err)
+			}
+			r.svc = svc
+
+			dir := t.TempDir()
+			for file, data := range test.Existing {
+				os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), os.FileMode(0o755))
+				os.WriteFile(filepath.Join(dir, file), data, os.FileMode(0o644))
+			}
+
+			files, err := r.DownloadResults(dir)
+			if test.Error == "" && err != nil {
+				t.Fatalf("Failed to download results: %v", err)
+			} else if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Fatalf("expected an error containing %q, got %v", test.Error, err)
+			}
+
+			sort.Strings(files)
+			if !reflect.DeepEqual(files, test.Expected) {
+				t.Errorf("expected files %v, got %v", test.Expected, files)
+			}
+			for _, file := range files {
+				data, err := os.ReadFile(filepath.Join(dir, file))
+				if err != nil || !bytes.Equal(data, results[file]) {
+					t.Errorf("file '%s' has been corrupted: want '%s', got '%s' (%v)", file, results[file], data, err)
+				}
+			}
+			for path, count := range test.Requests {
+				if reactor.Requests[path] != count {
+					t.Errorf("expected %d requests for %s, got %d", count, path, reactor.Requests[path])
+				}
+			}
+			if partials, _ := filepath.Glob(filepath.Join(dir, "*.part")); len(partials) > 0 {
+				t.Errorf("expected no partial downloads to be left behind, got %v", partials)
+			}
+		})
+	}
+}
+
+func TestDownloadTestResults(t *testing.T) {
+	for name, results := range map[string]map[string][]byte{"passing": goodResults, "failing": failingResults} {
+		client := fake.NewSimpleClientset()
+		client.AddProxyReactor("services", ResultsServerReactor{Manifest: manifest(results), Results: results}.React)
+
+		r := &Runner{Kube: client}
+		svc, err := r.createService(new(kubev1.Pod))
+		if err != nil {
+			t.Fatalf("Failed to create example service: %v", err)
+		}
+		r.svc = svc
+
+		files, err := r.DownloadTestResults(t.TempDir())
+		if len(files) != len(results) {
+			t.Errorf("%s: expected %d files, got %v", name, len(results), files)
+		}
+		if (err != nil) != (name == "failing") {
+			t.Errorf("%s: unexpected error result %v", name, err)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	image "github.com/openshift/client-go/image/clientset/versioned"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/util"
 	kubev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,11 +33,13 @@ var DefaultRunner = &Runner{
 		},
 		RestartPolicy: kubev1.RestartPolicyNever,
 	},
-	OutputDir: "/test-run-results",
-	Server:    "https://kubernetes.default",
-	CA:        serviceAccountDir + "/ca.crt",
-	TokenFile: serviceAccountDir + "/token",
-	Logger:    log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile),
+	OutputDir:         "/test-run-results",
+	MaxResultFileSize: 4 << 30,
+	MaxResultsSize:    10 << 30,
+	Server:            "https://kubernetes.default",
+	CA:                serviceAccountDir + "/ca.crt",
+	TokenFile:         serviceAccountDir + "/token",
+	Logger:            log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile),
 }
 
 // Runner runs the OpenShift extended test suite within a cluster.
@@ -82,12 +86,22 @@ type Runner struct {
 	// Tarball will create a single .tgz file for the entire OutputDir.
 	Tarball bool
 
+	// MaxResultFileSize is the largest file, in bytes, that will be downloaded from OutputDir. Zero means no limit.
+	MaxResultFileSize int64
+
+	// MaxResultsSize is the largest total size, in bytes, of the files that will be downloaded from OutputDir.
+	// Zero means no limit.
+	MaxResultsSize int64
+
 	// SkipLogsFromPod should be set to true if logs should not be collected.
 	SkipLogsFromPod bool
 
 	// Repos are cloned and mounted into the test Pod.
 	Repos
 
+	// PodOptions control the resources, placement and security of the test Pod.
+	PodOptions
+
 	// Logger receives all messages.
 	*log.Logger
 
@@ -118,10 +132,21 @@ func (r *Runner) Run(timeoutInSeconds int, stopCh <-chan struct{}) (err error) {
 
 	log.Printf("Waiting for %s runner Pod to start...", r.Name)
 	if err = r.waitForPodRunning(pod); err != nil {
-		return
+		return r.withDiagnostics(pod.Name, err)
 	}
 	r.status = StatusRunning
 
+	stopStreaming := func() {}
+	if viper.GetBool(config.Runner.StreamLogs) {
+		log.Printf("Streaming logs from containers on %s runner Pod...", r.Name)
+		if stop, streamErr := r.streamLogs(pod); streamErr != nil {
+			log.Printf("Unable to stream logs from %s runner Pod: %v", r.Name, streamErr)
+		} else {
+			stopStreaming = stop
+		}
+	}
+	defer stopStreaming()
+
 	log.Printf("Creating service for %s runner Pod...", r.Name)
 	if r.svc, err = r.createService(pod); err != nil {
 		return
@@ -130,10 +155,19 @@ func (r *Runner) Run(timeoutInSeconds int, stopCh <-chan struct{}) (err error) {
 	log.Printf("Waiting for endpoints of %s runner Pod with a timeout of %d seconds...", r.Name, timeoutInSeconds)
 	var completionErr error
 	completionErr = r.waitForCompletion(pod.Name, timeoutInSeconds)
+	stopStreaming()
+
+	// Capture diagnostics first, so that they aren't lost if collecting the logs fails.
+	if completionErr != nil {
+		completionErr = r.withDiagnostics(pod.Name, completionErr)
+	}
 
 	if !r.SkipLogsFromPod {
 		log.Printf("Collecting logs from containers on %s runner Pod...", r.Name)
 		if err = r.getAllLogsFromPod(pod.Name); err != nil {
+			if completionErr != nil {
+				return fmt.Errorf("%w; error collecting logs: %v", completionErr, err)
+			}
 			return
 		}
 	} else {
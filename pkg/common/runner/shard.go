@@ -0,0 +1,210 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ShardedRunner runs a suite split across several runner Pods in parallel.
+type ShardedRunner struct {
+	// Name is used for the merged JUnit XML report.
+	Name string
+
+	// Shards each run a subset of the suite.
+	Shards []*Runner
+}
+
+// NewShardedRunner returns a ShardedRunner with a copy of r for each command, named after the shard it runs.
+// A single command runs in r itself.
+func NewShardedRunner(r *Runner, cmds []string) *ShardedRunner {
+	if len(cmds) == 1 {
+		r.Cmd = cmds[0]
+		return &ShardedRunner{Name: r.Name, Shards: []*Runner{r}}
+	}
+
+	s := &ShardedRunner{Name: r.Name}
+	for i, cmd := range cmds {
+		shard := *r
+		shard.Name = fmt.Sprintf("%s-shard-%d", r.Name, i)
+		shard.Cmd = cmd
+		shard.PodSpec = *r.PodSpec.DeepCopy()
+		shard.Repos = make(Repos, len(r.Repos))
+		copy(shard.Repos, r.Repos)
+		s.Shards = append(s.Shards, &shard)
+	}
+	return s
+}
+
+// Run runs every shard in parallel and waits for all of them to finish.
+func (s *ShardedRunner) Run(timeoutInSeconds int, stopCh <-chan struct{}) error {
+	errs := make([]error, len(s.Shards))
+	var wg sync.WaitGroup
+	for i, shard := range s.Shards {
+		wg.Add(1)
+		go func(i int, shard *Runner) {
+			defer wg.Done()
+			errs[i] = shard.Run(timeoutInSeconds, stopCh)
+		}(i, shard)
+	}
+	wg.Wait()
+
+	var allErrors *multierror.Error
+	for i, err := range errs {
+		if err != nil {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("%s: %w", s.Shards[i].Name, err))
+		}
+	}
+	return allErrors.ErrorOrNil()
+}
+
+// DownloadTestResults downloads the results of each shard into its own directory in dir, merges their JUnit XML
+// into junit_<Name>.xml in dir, and ensures that it indicates all tests passed. Once merged, the shards' own JUnit
+// XML is removed so that their tests aren't counted twice. With a single shard the results are downloaded into dir
+// as they are. Should only be called after tests are finished.
+func (s *ShardedRunner) DownloadTestResults(dir string) ([]string, error) {
+	if len(s.Shards) == 1 {
+		return s.Shards[0].DownloadTestResults(dir)
+	}
+
+	var files, shardReports []string
+	var allErrors *multierror.Error
+	var suites [][]byte
+	for _, shard := range s.Shards {
+		shardFiles, err := shard.DownloadResults(filepath.Join(dir, shard.Name))
+		if err != nil {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("%s: %w", shard.Name, err))
+		}
+
+		var hadXML bool
+		for _, file := range shardFiles {
+			if match, _ := filepath.Match("junit*.xml", file); !match {
+				files = append(files, path.Join(shard.Name, file))
+				continue
+			}
+			shardReports = append(shardReports, path.Join(shard.Name, file))
+			data, err := os.ReadFile(filepath.Join(dir, shard.Name, filepath.FromSlash(file)))
+			if err != nil {
+				allErrors = multierror.Append(allErrors, fmt.Errorf("%s: %w", shard.Name, err))
+				continue
+			}
+			suites = append(suites, data)
+			hadXML = true
+		}
+		if !hadXML {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("%s: results did not contain Junit XML report", shard.Name))
+		}
+	}
+
+	// The shards' reports are only kept if they couldn't be merged.
+	merged, err := mergeJUnit(suites)
+	if err != nil {
+		return append(files, shardReports...), multierror.Append(allErrors, fmt.Errorf("failed merging Junit XML reports: %w", err))
+	}
+	report := fmt.Sprintf("junit_%s.xml", s.Name)
+	if err := os.WriteFile(filepath.Join(dir, report), merged, os.FileMode(0o644)); err != nil {
+		return append(files, shardReports...), multierror.Append(allErrors, err)
+	}
+	files = append(files, report)
+	for _, shardReport := range shardReports {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(shardReport))); err != nil {
+			allErrors = multierror.Append(allErrors, err)
+		}
+	}
+
+	if _, err := ensurePassingXML(map[string][]byte{report: merged}); err != nil {
+		allErrors = multierror.Append(allErrors, fmt.Errorf("failed checking results for Junit XML report: %w", err))
+	}
+	return files, allErrors.ErrorOrNil()
+}
+
+// junitSuites is the root of a JUnit XML report with several suites.
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite keeps everything within a suite as is, so that merging suites doesn't change their test cases.
+type junitSuite struct {
+	XMLName  xml.Name     `xml:"testsuite"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     float64      `xml:"time,attr"`
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Elements []xmlElement `xml:",any"`
+}
+
+type xmlElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+}
+
+// mergeJUnit combines the suites of several JUnit XML reports, merging the suites of the same name. The time of a
+// merged suite is the longest of its parts, since shards run in parallel. A single suite is written without a
+// testsuites element, as a single shard would have written it.
+func mergeJUnit(reports [][]byte) ([]byte, error) {
+	var merged []*junitSuite
+	byName := map[string]*junitSuite{}
+	for _, report := range reports {
+		var suites junitSuites
+		if err := xml.Unmarshal(report, &suites); err != nil {
+			var suite junitSuite
+			if err := xml.Unmarshal(report, &suite); err != nil {
+				return nil, err
+			}
+			suites.Suites = []junitSuite{suite}
+		}
+
+		for _, suite := range suites.Suites {
+			existing, ok := byName[suite.Name]
+			if !ok {
+				suite := suite
+				byName[suite.Name] = &suite
+				merged = append(merged, &suite)
+				continue
+			}
+			existing.Tests += suite.Tests
+			existing.Skipped += suite.Skipped
+			existing.Failures += suite.Failures
+			existing.Errors += suite.Errors
+			if suite.Time > existing.Time {
+				existing.Time = suite.Time
+			}
+			for _, element := range suite.Elements {
+				// Shards share the same suite properties.
+				if element.XMLName.Local != "properties" {
+					existing.Elements = append(existing.Elements, element)
+				}
+			}
+		}
+	}
+
+	var out interface{}
+	switch len(merged) {
+	case 0:
+		return nil, fmt.Errorf("no suites to merge")
+	case 1:
+		out = merged[0]
+	default:
+		suites := junitSuites{}
+		for _, suite := range merged {
+			suites.Suites = append(suites.Suites, *suite)
+		}
+		out = suites
+	}
+
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
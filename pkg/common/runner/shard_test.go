@@ -0,0 +1,197 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	junit "github.com/joshdk/go-junit"
+	kubev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	kubetest "k8s.io/client-go/testing"
+)
+
+const (
+	shardXML0 = `<testsuite name="openshift-tests" tests="2" skipped="1" failures="0" time="120" timestamp="2022-01-01T00:00:00">
+    <properties><property name="TestVersion" value="4.11"></property></properties>
+    <testcase name="[sig-a] first" time="1"></testcase>
+    <testcase name="[sig-a] second" time="2"><skipped message="skipped"></skipped></testcase>
+</testsuite>`
+	shardXML1 = `<testsuite name="openshift-tests" tests="1" skipped="0" failures="0" time="300">
+    <properties><property name="TestVersion" value="4.11"></property></properties>
+    <testcase name="[sig-b] third" time="3"><system-out>output</system-out></testcase>
+</testsuite>`
+	monitorXML = `<testsuites><testsuite name="monitor" tests="1" failures="1" time="5">
+    <testcase name="[sig-c] monitor"><failure message="alert fired">alert fired</failure></testcase>
+</testsuite></testsuites>`
+)
+
+func TestMergeJUnit(t *testing.T) {
+	merged, err := mergeJUnit([][]byte{[]byte(shardXML0), []byte(shardXML1)})
+	if err != nil {
+		t.Fatalf("unexpected error merging suites: %v", err)
+	}
+	if !strings.Contains(string(merged), `<testsuite name="openshift-tests" tests="3" skipped="1" failures="0" errors="0" time="300" timestamp="2022-01-01T00:00:00">`) {
+		t.Errorf("expected a single suite with summed counts and the longest time, got %s", merged)
+	}
+	if strings.Count(string(merged), "<properties>") != 1 {
+		t.Errorf("expected the suite properties once, got %s", merged)
+	}
+
+	suites, err := junit.Ingest(merged)
+	if err != nil {
+		t.Fatalf("unable to parse merged suites: %v", err)
+	}
+	if len(suites) != 1 {
+		t.Fatalf("expected 1 suite, got %d", len(suites))
+	}
+	var names []string
+	for _, test := range suites[0].Tests {
+		names = append(names, test.Name)
+	}
+	if expected := []string{"[sig-a] first", "[sig-a] second", "[sig-b] third"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected tests %v, got %v", expected, names)
+	}
+	if suites[0].Tests[1].Status != junit.StatusSkipped || suites[0].Tests[2].SystemOut != "output" {
+		t.Errorf("expected the test cases to be kept as they were, got %+v", suites[0].Tests)
+	}
+
+	merged, err = mergeJUnit([][]byte{[]byte(shardXML0), []byte(monitorXML)})
+	if err != nil {
+		t.Fatalf("unexpected error merging suites: %v", err)
+	}
+	if suites, err = junit.Ingest(merged); err != nil || len(suites) != 2 {
+		t.Errorf("expected differently named suites to be kept apart, got %d suites: %v", len(suites), err)
+	}
+
+	if _, err = mergeJUnit([][]byte{[]byte(badXML)}); err == nil {
+		t.Error("expected an error merging invalid XML")
+	}
+}
+
+func TestNewShardedRunner(t *testing.T) {
+	def := *DefaultRunner
+	r := &def
+	r.Name = "conformance"
+
+	single := NewShardedRunner(r, []string{"run"})
+	if len(single.Shards) != 1 || single.Shards[0] != r || r.Cmd != "run" {
+		t.Errorf("expected a single command to run in the runner itself, got %+v", single)
+	}
+
+	sharded := NewShardedRunner(r, []string{"run 0", "run 1"})
+	if len(sharded.Shards) != 2 || sharded.Name != "conformance" {
+		t.Fatalf("expected 2 shards of conformance, got %+v", sharded)
+	}
+	for i, shard := range sharded.Shards {
+		if expected := "conformance-shard-" + string(rune('0'+i)); shard.Name != expected {
+			t.Errorf("expected shard %d to be named %s, got %s", i, expected, shard.Name)
+		}
+		if expected := "run " + string(rune('0'+i)); shard.Cmd != expected {
+			t.Errorf("expected shard %d to run %q, got %q", i, expected, shard.Cmd)
+		}
+	}
+	sharded.Shards[0].PodSpec.Containers[0].Name = "changed"
+	if sharded.Shards[1].PodSpec.Containers[0].Name == "changed" || r.PodSpec.Containers[0].Name == "changed" {
+		t.Error("expected each shard to have its own PodSpec")
+	}
+}
+
+// shardReactor serves the results of the shard whose service is requested.
+type shardReactor map[string]ResultsServerReactor
+
+func (s shardReactor) React(action kubetest.Action) (bool, rest.ResponseWrapper, error) {
+	proxyAction := action.(kubetest.ProxyGetActionImpl)
+	for name, reactor := range s {
+		if strings.HasPrefix(proxyAction.Name, name+"-") {
+			return reactor.React(action)
+		}
+	}
+	return true, response{}, nil
+}
+
+func TestShardedRunnerDownloadTestResults(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Results map[string]map[string][]byte
+		Error   string
+	}{
+		{
+			Name: "passing",
+			Results: map[string]map[string][]byte{
+				"e2e-shard-0": {"junit_e2e.xml": []byte(shardXML0), "e2e-out.txt": []byte("zero")},
+				"e2e-shard-1": {"junit_e2e.xml": []byte(shardXML1), "e2e-out.txt": []byte("one")},
+			},
+		},
+		{
+			Name: "failing",
+			Results: map[string]map[string][]byte{
+				"e2e-shard-0": {"junit_e2e.xml": []byte(shardXML0)},
+				"e2e-shard-1": {"junit_e2e.xml": []byte(shardXML1), "junit_monitor.xml": []byte(monitorXML)},
+			},
+			Error: "at least one test failed",
+		},
+		{
+			Name: "missingXML",
+			Results: map[string]map[string][]byte{
+				"e2e-shard-0": {"junit_e2e.xml": []byte(shardXML0)},
+				"e2e-shard-1": {"e2e-out.txt": []byte("one")},
+			},
+			Error: "e2e-shard-1: results did not contain Junit XML report",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			reactors := shardReactor{}
+			for shard, results := range test.Results {
+				reactors[shard] = ResultsServerReactor{Manifest: manifest(results), Results: results}
+			}
+			client := fake.NewSimpleClientset()
+			client.AddProxyReactor("services", reactors.React)
+
+			r := &Runner{Name: "e2e", Kube: client}
+			s := NewShardedRunner(r, []string{"run 0", "run 1"})
+			for _, shard := range s.Shards {
+				svc, err := shard.createService(new(kubev1.Pod))
+				if err != nil {
+					t.Fatalf("Failed to create example service: %v", err)
+				}
+				shard.svc = svc
+			}
+
+			dir := t.TempDir()
+			files, err := s.DownloadTestResults(dir)
+			if test.Error == "" && err != nil {
+				t.Fatalf("Failed to download results: %v", err)
+			} else if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Fatalf("expected an error containing %q, got %v", test.Error, err)
+			}
+
+			var expected []string
+			for shard, results := range test.Results {
+				for file := range results {
+					if !strings.HasPrefix(file, "junit") {
+						expected = append(expected, shard+"/"+file)
+					}
+				}
+			}
+			expected = append(expected, "junit_e2e.xml")
+			sort.Strings(expected)
+			sort.Strings(files)
+			if !reflect.DeepEqual(files, expected) {
+				t.Errorf("expected files %v, got %v", expected, files)
+			}
+
+			// Only the merged report is left, so that no test is counted twice.
+			reports, _ := filepath.Glob(filepath.Join(dir, "junit*.xml"))
+			shardReports, _ := filepath.Glob(filepath.Join(dir, "*", "junit*.xml"))
+			if len(reports) != 1 || len(shardReports) != 0 {
+				t.Errorf("expected a single merged report, got %v and %v", reports, shardReports)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package spi
+
+import (
+	"context"
+	"time"
+)
+
+// Background implements the context-less methods of Provider by calling their Context counterparts
+// on a ContextProvider with context.Background().
+//
+// Providers embed it, set to themselves, to satisfy the context-less half of Provider. Methods a
+// provider defines itself take precedence over those of Background.
+type Background struct {
+	provider ContextProvider
+}
+
+// NewBackground returns a Background that calls the Context methods of provider.
+func NewBackground(provider ContextProvider) Background {
+	return Background{provider: provider}
+}
+
+// IsValidClusterName calls IsValidClusterNameContext with a background context.
+func (b Background) IsValidClusterName(clusterName string) (bool, error) {
+	return b.provider.IsValidClusterNameContext(context.Background(), clusterName)
+}
+
+// LaunchCluster calls LaunchClusterContext with a background context.
+func (b Background) LaunchCluster(clusterName string) (string, error) {
+	return b.provider.LaunchClusterContext(context.Background(), clusterName)
+}
+
+// DeleteCluster calls DeleteClusterContext with a background context.
+func (b Background) DeleteCluster(clusterID string) error {
+	return b.provider.DeleteClusterContext(context.Background(), clusterID)
+}
+
+// ScaleCluster calls ScaleClusterContext with a background context.
+func (b Background) ScaleCluster(clusterID string, numComputeNodes int) error {
+	return b.provider.ScaleClusterContext(context.Background(), clusterID, numComputeNodes)
+}
+
+// ListClusters calls ListClustersContext with a background context.
+func (b Background) ListClusters(query string) ([]*Cluster, error) {
+	return b.provider.ListClustersContext(context.Background(), query)
+}
+
+// GetCluster calls GetClusterContext with a background context.
+func (b Background) GetCluster(clusterID string) (*Cluster, error) {
+	return b.provider.GetClusterContext(context.Background(), clusterID)
+}
+
+// ClusterKubeconfig calls ClusterKubeconfigContext with a background context.
+func (b Background) ClusterKubeconfig(clusterID string) ([]byte, error) {
+	return b.provider.ClusterKubeconfigContext(context.Background(), clusterID)
+}
+
+// CheckQuota calls CheckQuotaContext with a background context.
+func (b Background) CheckQuota(sku string) (bool, error) {
+	return b.provider.CheckQuotaContext(context.Background(), sku)
+}
+
+// InstallAddons calls InstallAddonsContext with a background context.
+func (b Background) InstallAddons(clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error) {
+	return b.provider.InstallAddonsContext(context.Background(), clusterID, addonIDs, params)
+}
+
+// Versions calls VersionsContext with a background context.
+func (b Background) Versions() (*VersionList, error) {
+	return b.provider.VersionsContext(context.Background())
+}
+
+// Logs calls LogsContext with a background context.
+func (b Background) Logs(clusterID string) (map[string][]byte, error) {
+	return b.provider.LogsContext(context.Background(), clusterID)
+}
+
+// ExtendExpiry calls ExtendExpiryContext with a background context.
+func (b Background) ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error {
+	return b.provider.ExtendExpiryContext(context.Background(), clusterID, hours, minutes, seconds)
+}
+
+// Expire calls ExpireContext with a background context.
+func (b Background) Expire(clusterID string) error {
+	return b.provider.ExpireContext(context.Background(), clusterID)
+}
+
+// AddProperty calls AddPropertyContext with a background context.
+func (b Background) AddProperty(cluster *Cluster, tag string, value string) error {
+	return b.provider.AddPropertyContext(context.Background(), cluster, tag, value)
+}
+
+// Upgrade calls UpgradeContext with a background context.
+func (b Background) Upgrade(clusterID string, version string, t time.Time) error {
+	return b.provider.UpgradeContext(context.Background(), clusterID, version, t)
+}
+
+// GetUpgradePolicyID calls GetUpgradePolicyIDContext with a background context.
+func (b Background) GetUpgradePolicyID(clusterID string) (string, error) {
+	return b.provider.GetUpgradePolicyIDContext(context.Background(), clusterID)
+}
+
+// UpdateSchedule calls UpdateScheduleContext with a background context.
+func (b Background) UpdateSchedule(clusterID string, version string, t time.Time, policyID string) error {
+	return b.provider.UpdateScheduleContext(context.Background(), clusterID, version, t, policyID)
+}
+
+// DetermineMachineType calls DetermineMachineTypeContext with a background context.
+func (b Background) DetermineMachineType(cloudProvider string) (string, error) {
+	return b.provider.DetermineMachineTypeContext(context.Background(), cloudProvider)
+}
+
+// Hibernate calls HibernateContext with a background context.
+func (b Background) Hibernate(clusterID string) bool {
+	return b.provider.HibernateContext(context.Background(), clusterID)
+}
+
+// Resume calls ResumeContext with a background context.
+func (b Background) Resume(clusterID string) bool {
+	return b.provider.ResumeContext(context.Background(), clusterID)
+}
+
+// AddClusterProxy calls AddClusterProxyContext with a background context.
+func (b Background) AddClusterProxy(clusterId string, httpsProxy string, httpProxy string, userCABundle string) error {
+	return b.provider.AddClusterProxyContext(context.Background(), clusterId, httpsProxy, httpProxy, userCABundle)
+}
+
+// RemoveClusterProxy calls RemoveClusterProxyContext with a background context.
+func (b Background) RemoveClusterProxy(clusterId string) error {
+	return b.provider.RemoveClusterProxyContext(context.Background(), clusterId)
+}
+
+// RemoveUserCABundle calls RemoveUserCABundleContext with a background context.
+func (b Background) RemoveUserCABundle(clusterId string) error {
+	return b.provider.RemoveUserCABundleContext(context.Background(), clusterId)
+}
@@ -0,0 +1,31 @@
+package spi
+
+import (
+	"context"
+	"testing"
+)
+
+// launchProvider implements LaunchClusterContext and panics on any other ContextProvider method.
+type launchProvider struct {
+	ContextProvider
+	ctx  context.Context
+	name string
+}
+
+func (l *launchProvider) LaunchClusterContext(ctx context.Context, clusterName string) (string, error) {
+	l.ctx, l.name = ctx, clusterName
+	return "id-" + clusterName, nil
+}
+
+func TestBackground(t *testing.T) {
+	provider := &launchProvider{}
+	background := NewBackground(provider)
+
+	id, err := background.LaunchCluster("test")
+	if err != nil || id != "id-test" {
+		t.Errorf("expected id-test, got %q: %v", id, err)
+	}
+	if provider.name != "test" || provider.ctx != context.Background() {
+		t.Errorf("expected LaunchClusterContext to be called with a background context, got %v and %q", provider.ctx, provider.name)
+	}
+}
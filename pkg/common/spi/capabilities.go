@@ -0,0 +1,63 @@
+package spi
+
+// Capability names a feature that a provider may or may not support.
+type Capability string
+
+const (
+	// HibernationCapability indicates the provider can hibernate and resume clusters.
+	HibernationCapability Capability = "hibernation"
+
+	// AddonsCapability indicates the provider can install addons onto clusters.
+	AddonsCapability Capability = "addons"
+
+	// ManagedUpgradesCapability indicates the provider can schedule managed upgrades.
+	ManagedUpgradesCapability Capability = "managed-upgrades"
+
+	// ClusterProxyCapability indicates the provider can configure a cluster-wide proxy.
+	ClusterProxyCapability Capability = "cluster-proxy"
+
+	// ExtendExpiryCapability indicates the provider can extend or expire a cluster's expiration time.
+	ExtendExpiryCapability Capability = "extend-expiry"
+
+	// ScalingCapability indicates the provider can scale the compute nodes of a cluster.
+	ScalingCapability Capability = "scaling"
+
+	// RecyclingCapability indicates the provider can hand out previously used clusters.
+	RecyclingCapability Capability = "recycling"
+)
+
+// Capabilities describes which optional features a provider supports.
+//
+// Callers should consult this before calling provider methods tied to an optional feature
+// rather than relying on the provider name or on how an unsupported call fails.
+type Capabilities struct {
+	Hibernation     bool
+	Addons          bool
+	ManagedUpgrades bool
+	ClusterProxy    bool
+	ExtendExpiry    bool
+	Scaling         bool
+	Recycling       bool
+}
+
+// Supports reports whether the given capability is enabled. Unknown capabilities are unsupported.
+func (c Capabilities) Supports(capability Capability) bool {
+	switch capability {
+	case HibernationCapability:
+		return c.Hibernation
+	case AddonsCapability:
+		return c.Addons
+	case ManagedUpgradesCapability:
+		return c.ManagedUpgrades
+	case ClusterProxyCapability:
+		return c.ClusterProxy
+	case ExtendExpiryCapability:
+		return c.ExtendExpiry
+	case ScalingCapability:
+		return c.Scaling
+	case RecyclingCapability:
+		return c.Recycling
+	default:
+		return false
+	}
+}
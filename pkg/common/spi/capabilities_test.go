@@ -0,0 +1,57 @@
+package spi
+
+import "testing"
+
+func TestCapabilitiesSupports(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Capabilities Capabilities
+		Capability   Capability
+		Expected     bool
+	}{
+		{
+			Name:         "none supported",
+			Capabilities: Capabilities{},
+			Capability:   HibernationCapability,
+			Expected:     false,
+		},
+		{
+			Name:         "hibernation supported",
+			Capabilities: Capabilities{Hibernation: true},
+			Capability:   HibernationCapability,
+			Expected:     true,
+		},
+		{
+			Name:         "other capability supported",
+			Capabilities: Capabilities{Hibernation: true},
+			Capability:   ScalingCapability,
+			Expected:     false,
+		},
+		{
+			Name:         "managed upgrades supported",
+			Capabilities: Capabilities{ManagedUpgrades: true},
+			Capability:   ManagedUpgradesCapability,
+			Expected:     true,
+		},
+		{
+			Name: "unknown capability",
+			Capabilities: Capabilities{
+				Hibernation:     true,
+				Addons:          true,
+				ManagedUpgrades: true,
+				ClusterProxy:    true,
+				ExtendExpiry:    true,
+				Scaling:         true,
+				Recycling:       true,
+			},
+			Capability: Capability("teleportation"),
+			Expected:   false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.Capabilities.Supports(test.Capability); got != test.Expected {
+			t.Errorf("%s: expected Supports(%s) to be %t, got %t", test.Name, test.Capability, test.Expected, got)
+		}
+	}
+}
@@ -199,6 +199,13 @@ func (cb *ClusterBuilder) State(state ClusterState) *ClusterBuilder {
 	return cb
 }
 
+// StoredState sets the state for a cluster builder without recording it as the run's cluster status, for
+// clusters rebuilt from stored state rather than observed by this run.
+func (cb *ClusterBuilder) StoredState(state ClusterState) *ClusterBuilder {
+	cb.state = state
+	return cb
+}
+
 // Flavour sets the flavour for a cluster builder.
 func (cb *ClusterBuilder) Flavour(flavour string) *ClusterBuilder {
 	cb.flavour = flavour
@@ -2,6 +2,7 @@
 package spi
 
 import (
+	"context"
 	"time"
 )
 
@@ -12,7 +13,13 @@ type AddOnID = string
 type AddOnParams = map[string]string
 
 // Provider is the interface that must be implemented in order to provision clusters in osde2e.
+//
+// Every method that talks to the provider backend has a Context variant in ContextProvider.
+// The plain methods are kept for existing callers and are expected to behave like their
+// Context variant called with context.Background().
 type Provider interface {
+	ContextProvider
+
 	// IsValidClusterName validates that the proposed name used for creating the cluster.
 	//
 	// Currently this validates if the proposed clusterName already exists before attempting to
@@ -110,6 +117,12 @@ type Provider interface {
 	// This simply returns the name of the Provider
 	Type() string
 
+	// Capabilities describes which optional features this provider supports.
+	//
+	// OSDe2e uses this to decide whether to hibernate, install addons, upgrade, extend expiry and
+	// so on, and to skip specs that depend on a feature the provider lacks.
+	Capabilities() Capabilities
+
 	// ExtendExpiry extends the expiration time of an existing cluster.
 	ExtendExpiry(clusterID string, hours uint64, minutes uint64, seconds uint64) error
 
@@ -132,13 +145,11 @@ type Provider interface {
 	DetermineMachineType(cloudProvider string) (string, error)
 
 	// Hibernate triggers a hibernation of the cluster
-	// If hibernation is unsupported by the provider, it will log that it's unsupported
-	// but still return True.
+	// If hibernation is unsupported by the provider (see Capabilities), it returns false.
 	Hibernate(clusterID string) bool
 
 	// Resume triggers a hibernated cluster to wake up
-	// If hibernation is unsupported by the provider, it will log that it's unsupported
-	// but still return True.
+	// If hibernation is unsupported by the provider (see Capabilities), it returns false.
 	Resume(clusterID string) bool
 
 	// AddClusterProxy adds a cluster-wide proxy to the cluster.
@@ -153,3 +164,79 @@ type Provider interface {
 	// LoadUserCaBundleData loads CA contents from CA cert file
 	LoadUserCaBundleData(file string) (string, error)
 }
+
+// ContextProvider is the context-aware half of Provider.
+//
+// Each method mirrors the Provider method of the same name without the Context suffix.
+// Implementations must stop waiting on their backend and return promptly once ctx is
+// done, returning ctx.Err() (possibly wrapped) where the method returns an error.
+type ContextProvider interface {
+	// IsValidClusterNameContext is IsValidClusterName with a context.
+	IsValidClusterNameContext(ctx context.Context, clusterName string) (bool, error)
+
+	// LaunchClusterContext is LaunchCluster with a context.
+	LaunchClusterContext(ctx context.Context, clusterName string) (string, error)
+
+	// DeleteClusterContext is DeleteCluster with a context.
+	DeleteClusterContext(ctx context.Context, clusterID string) error
+
+	// ScaleClusterContext is ScaleCluster with a context.
+	ScaleClusterContext(ctx context.Context, clusterID string, numComputeNodes int) error
+
+	// ListClustersContext is ListClusters with a context.
+	ListClustersContext(ctx context.Context, query string) ([]*Cluster, error)
+
+	// GetClusterContext is GetCluster with a context.
+	GetClusterContext(ctx context.Context, clusterID string) (*Cluster, error)
+
+	// ClusterKubeconfigContext is ClusterKubeconfig with a context.
+	ClusterKubeconfigContext(ctx context.Context, clusterID string) ([]byte, error)
+
+	// CheckQuotaContext is CheckQuota with a context.
+	CheckQuotaContext(ctx context.Context, sku string) (bool, error)
+
+	// InstallAddonsContext is InstallAddons with a context.
+	InstallAddonsContext(ctx context.Context, clusterID string, addonIDs []AddOnID, params map[AddOnID]AddOnParams) (int, error)
+
+	// VersionsContext is Versions with a context.
+	VersionsContext(ctx context.Context) (*VersionList, error)
+
+	// LogsContext is Logs with a context.
+	LogsContext(ctx context.Context, clusterID string) (map[string][]byte, error)
+
+	// ExtendExpiryContext is ExtendExpiry with a context.
+	ExtendExpiryContext(ctx context.Context, clusterID string, hours uint64, minutes uint64, seconds uint64) error
+
+	// ExpireContext is Expire with a context.
+	ExpireContext(ctx context.Context, clusterID string) error
+
+	// AddPropertyContext is AddProperty with a context.
+	AddPropertyContext(ctx context.Context, cluster *Cluster, tag string, value string) error
+
+	// UpgradeContext is Upgrade with a context.
+	UpgradeContext(ctx context.Context, clusterID string, version string, t time.Time) error
+
+	// GetUpgradePolicyIDContext is GetUpgradePolicyID with a context.
+	GetUpgradePolicyIDContext(ctx context.Context, clusterID string) (string, error)
+
+	// UpdateScheduleContext is UpdateSchedule with a context.
+	UpdateScheduleContext(ctx context.Context, clusterID string, version string, t time.Time, policyID string) error
+
+	// DetermineMachineTypeContext is DetermineMachineType with a context.
+	DetermineMachineTypeContext(ctx context.Context, cloudProvider string) (string, error)
+
+	// HibernateContext is Hibernate with a context.
+	HibernateContext(ctx context.Context, clusterID string) bool
+
+	// ResumeContext is Resume with a context.
+	ResumeContext(ctx context.Context, clusterID string) bool
+
+	// AddClusterProxyContext is AddClusterProxy with a context.
+	AddClusterProxyContext(ctx context.Context, clusterId string, httpsProxy string, httpProxy string, userCABundle string) error
+
+	// RemoveClusterProxyContext is RemoveClusterProxy with a context.
+	RemoveClusterProxyContext(ctx context.Context, clusterId string) error
+
+	// RemoveUserCABundleContext is RemoveUserCABundle with a context.
+	RemoveUserCABundleContext(ctx context.Context, clusterId string) error
+}
@@ -0,0 +1,151 @@
+package spi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	queryTermRegex = regexp.MustCompile(`(?i)^\s*([a-z0-9_.-]+)\s*(=|\slike\s)\s*'([^']*)'\s*$`)
+	queryAndRegex  = regexp.MustCompile(`(?i)^\s+and\s+`)
+)
+
+// ClusterQuery is a parsed ListClusters query.
+//
+// Queries use the subset of the OCM search language osde2e relies on: terms of the form
+// "key = 'value'" or "key like 'value%'" joined with "and", where % matches any run of characters.
+// The supported keys are id, name, state, version.id, cloud_provider.id, product.id, region.id and
+// properties.<name>.
+type ClusterQuery struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	key   string
+	match func(string) bool
+}
+
+// ParseClusterQuery parses a ListClusters query. An empty query matches every cluster.
+func ParseClusterQuery(query string) (*ClusterQuery, error) {
+	clusterQuery := &ClusterQuery{}
+	if strings.TrimSpace(query) == "" {
+		return clusterQuery, nil
+	}
+
+	for _, term := range splitQueryTerms(query) {
+		parts := queryTermRegex.FindStringSubmatch(term)
+		if parts == nil {
+			return nil, fmt.Errorf("unsupported query term %q", term)
+		}
+		key, operator, value := parts[1], strings.ToLower(strings.TrimSpace(parts[2])), parts[3]
+
+		if !IsClusterQueryKey(key) {
+			return nil, fmt.Errorf("unsupported query key %q", key)
+		}
+
+		match := func(field string) bool { return field == value }
+		if operator == "like" {
+			pattern := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(value), "%", ".*") + "$")
+			match = pattern.MatchString
+		}
+
+		clusterQuery.terms = append(clusterQuery.terms, queryTerm{key: key, match: match})
+	}
+
+	return clusterQuery, nil
+}
+
+// splitQueryTerms splits a query on "and", except within quoted values.
+func splitQueryTerms(query string) []string {
+	var terms []string
+	start, quoted := 0, false
+	for i := 0; i < len(query); i++ {
+		switch {
+		case query[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		default:
+			if and := queryAndRegex.FindString(query[i:]); and != "" {
+				terms = append(terms, query[start:i])
+				i += len(and) - 1
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, query[start:])
+}
+
+// IsClusterQueryKey reports whether key can be used in a ListClusters query.
+func IsClusterQueryKey(key string) bool {
+	if strings.HasPrefix(key, "properties.") {
+		return len(key) > len("properties.")
+	}
+
+	switch key {
+	case "id", "name", "state", "version.id", "cloud_provider.id", "product.id", "region.id":
+		return true
+	}
+	return false
+}
+
+// Matches reports whether the cluster satisfies the query.
+func (q *ClusterQuery) Matches(cluster *Cluster) bool {
+	return q.MatchesFields(cluster.queryField)
+}
+
+// MatchesFields reports whether the query is satisfied by a cluster whose fields are looked up by
+// key. This lets providers evaluate queries against their own cluster representation.
+func (q *ClusterQuery) MatchesFields(field func(key string) string) bool {
+	for _, term := range q.terms {
+		if !term.match(field(term.key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the clusters that satisfy the query, in their original order.
+func (q *ClusterQuery) Filter(clusters []*Cluster) []*Cluster {
+	filtered := []*Cluster{}
+	for _, cluster := range clusters {
+		if q.Matches(cluster) {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return filtered
+}
+
+// FilterClusters parses a ListClusters query and returns the clusters that satisfy it.
+func FilterClusters(query string, clusters []*Cluster) ([]*Cluster, error) {
+	clusterQuery, err := ParseClusterQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return clusterQuery.Filter(clusters), nil
+}
+
+// queryField returns the value of a query key for the cluster.
+func (c *Cluster) queryField(key string) string {
+	if strings.HasPrefix(key, "properties.") {
+		return c.properties[strings.TrimPrefix(key, "properties.")]
+	}
+
+	switch key {
+	case "id":
+		return c.id
+	case "name":
+		return c.name
+	case "state":
+		return string(c.state)
+	case "version.id":
+		return c.version
+	case "cloud_provider.id":
+		return c.cloudProvider
+	case "product.id":
+		return c.product
+	case "region.id":
+		return c.region
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package spi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queryTestClusters() []*Cluster {
+	return []*Cluster{
+		NewClusterBuilder().
+			ID("1").
+			Name("osde2e-abc12").
+			Version("openshift-v4.11.12").
+			CloudProvider("aws").
+			Product("osd").
+			Region("us-east-1").
+			State(ClusterStateHibernating).
+			Properties(map[string]string{"MadeByOSDe2e": "true", "Status": "completed-passing"}).
+			Build(),
+		NewClusterBuilder().
+			ID("2").
+			Name("osde2e-def34").
+			Version("openshift-v4.12.1-candidate").
+			CloudProvider("gcp").
+			Product("osd").
+			Region("us-east1").
+			State(ClusterStateReady).
+			Properties(map[string]string{"MadeByOSDe2e": "true", "Status": "completed-failing", "JobName": "upgrade and e2e"}).
+			Build(),
+		NewClusterBuilder().
+			ID("3").
+			Name("someone-elses").
+			Version("openshift-v4.11.12").
+			CloudProvider("aws").
+			Product("rosa").
+			Region("us-east-1").
+			State(ClusterStateReady).
+			Build(),
+	}
+}
+
+func TestClusterQuery(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Query       string
+		ExpectedIDs []string
+	}{
+		{
+			Name:        "empty query",
+			Query:       "",
+			ExpectedIDs: []string{"1", "2", "3"},
+		},
+		{
+			Name:        "property",
+			Query:       "properties.MadeByOSDe2e='true'",
+			ExpectedIDs: []string{"1", "2"},
+		},
+		{
+			Name:        "missing property",
+			Query:       "properties.JobID = '1234'",
+			ExpectedIDs: []string{},
+		},
+		{
+			Name:        "recycling query",
+			Query:       "version.id like 'openshift-v4.11.12%' and cloud_provider.id = 'aws' and product.id = 'osd' and properties.Status = 'completed-passing'",
+			ExpectedIDs: []string{"1"},
+		},
+		{
+			Name:        "like prefix",
+			Query:       "name like 'osde2e-%'",
+			ExpectedIDs: []string{"1", "2"},
+		},
+		{
+			Name:        "like infix",
+			Query:       "version.id LIKE '%-v4.12.%'",
+			ExpectedIDs: []string{"2"},
+		},
+		{
+			Name:        "like without wildcard",
+			Query:       "name like 'osde2e'",
+			ExpectedIDs: []string{},
+		},
+		{
+			Name:        "like escapes regular expressions",
+			Query:       "version.id like 'openshift-v4.11.1.'",
+			ExpectedIDs: []string{},
+		},
+		{
+			Name:        "and within a value",
+			Query:       "properties.JobName = 'upgrade and e2e' and state='ready'",
+			ExpectedIDs: []string{"2"},
+		},
+		{
+			Name:        "and within a like pattern",
+			Query:       "properties.JobName like '% and %'",
+			ExpectedIDs: []string{"2"},
+		},
+		{
+			Name:        "identity fields",
+			Query:       "id = '3' AND state = 'ready' AND region.id = 'us-east-1'",
+			ExpectedIDs: []string{"3"},
+		},
+	}
+
+	for _, test := range tests {
+		clusters, err := FilterClusters(test.Query, queryTestClusters())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.Name, err)
+			continue
+		}
+
+		ids := []string{}
+		for _, cluster := range clusters {
+			ids = append(ids, cluster.ID())
+		}
+		if !reflect.DeepEqual(ids, test.ExpectedIDs) {
+			t.Errorf("%s: expected clusters %v, got %v", test.Name, test.ExpectedIDs, ids)
+		}
+	}
+}
+
+func TestClusterQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"properties.MadeByOSDe2e='true' or name = 'x'",
+		"name = osde2e",
+		"name != 'osde2e'",
+		"flavour.id = 'osd-4'",
+		"properties. = 'true'",
+		"name = 'a' and",
+		"name = 'a and b",
+		"name = 'a' and 'b' = name",
+	} {
+		if _, err := ParseClusterQuery(query); err == nil {
+			t.Errorf("expected an error parsing %q", query)
+		}
+	}
+}
@@ -41,7 +41,8 @@ var (
 )
 
 // RunUpgrade uses the OpenShift extended suite to upgrade a cluster to the image provided in cfg.
-func RunUpgrade() error {
+// Waiting on the upgrade is abandoned if ctx is cancelled.
+func RunUpgrade(ctx context.Context) error {
 	var done bool
 	var msg string
 	var err error
@@ -69,21 +70,21 @@ func RunUpgrade() error {
 	if err != nil {
 		return fmt.Errorf("can't determine provider for managed upgrade: %s", err)
 	}
-	switch provider.Type() {
-	case "rosa":
-		fallthrough
-	case "ocm":
-		desiredUpdate, err = TriggerManagedUpgrade(h)
-		if err != nil {
-			return fmt.Errorf("failed triggering upgrade: %v", err)
-		}
-	default:
+	if !provider.Capabilities().ManagedUpgrades {
 		return fmt.Errorf("unsupported provider for managed upgrades (%s)", provider.Type())
 	}
+	desiredUpdate, err = TriggerManagedUpgrade(h)
+	if err != nil {
+		return fmt.Errorf("failed triggering upgrade: %v", err)
+	}
 
 	// When the upgrade being rescheduled, we should expect that the upgrade will not be triggered
 	if viper.GetBool(config.Upgrade.ManagedUpgradeRescheduled) {
-		time.Sleep(10 * time.Minute)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting on rescheduled upgrade: %w", ctx.Err())
+		case <-time.After(10 * time.Minute):
+		}
 		triggered, err := isUpgradeTriggered(h, desiredUpdate)
 		if triggered {
 			return fmt.Errorf("the upgrade was triggered unexpectly: %v", err)
@@ -96,8 +97,9 @@ func RunUpgrade() error {
 	log.Println("Cluster acknowledged update request.")
 
 	log.Println("Upgrading...")
+	defer cluster.MonitorHealth(ctx, "upgrade", nil)()
 	done = false
-	if err = wait.PollImmediate(10*time.Second, MaxDuration, func() (bool, error) {
+	if err = wait.PollImmediateWithContext(ctx, 10*time.Second, MaxDuration, func(ctx context.Context) (bool, error) {
 		// Keep the managed upgrade's configuration overrides in place, in case Hive has replaced them
 		err = overrideOperatorConfig(h)
 		// Log if it errored, but don't cancel the upgrade because of it
@@ -121,7 +123,7 @@ func RunUpgrade() error {
 
 	metadata.Instance.SetTimeToUpgradedCluster(time.Since(upgradeStarted).Seconds())
 
-	if err = cluster.WaitForClusterReadyPostUpgrade(viper.GetString(config.Cluster.ID), nil); err != nil {
+	if err = cluster.WaitForClusterReadyPostUpgrade(ctx, viper.GetString(config.Cluster.ID), nil); err != nil {
 		return fmt.Errorf("failed waiting for cluster ready: %v", err)
 	}
 
@@ -22,12 +22,18 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.createHealthcheckStmt, err = db.PrepareContext(ctx, createHealthcheck); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateHealthcheck: %w", err)
+	}
 	if q.createJobStmt, err = db.PrepareContext(ctx, createJob); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateJob: %w", err)
 	}
 	if q.createTestcaseStmt, err = db.PrepareContext(ctx, createTestcase); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTestcase: %w", err)
 	}
+	if q.getHealthchecksForJobStmt, err = db.PrepareContext(ctx, getHealthchecksForJob); err != nil {
+		return nil, fmt.Errorf("error preparing query GetHealthchecksForJob: %w", err)
+	}
 	if q.getJobStmt, err = db.PrepareContext(ctx, getJob); err != nil {
 		return nil, fmt.Errorf("error preparing query GetJob: %w", err)
 	}
@@ -43,6 +49,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listAlertableRecentTestFailuresStmt, err = db.PrepareContext(ctx, listAlertableRecentTestFailures); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAlertableRecentTestFailures: %w", err)
 	}
+	if q.listHealthcheckFailuresStmt, err = db.PrepareContext(ctx, listHealthcheckFailures); err != nil {
+		return nil, fmt.Errorf("error preparing query ListHealthcheckFailures: %w", err)
+	}
 	if q.listJobsStmt, err = db.PrepareContext(ctx, listJobs); err != nil {
 		return nil, fmt.Errorf("error preparing query ListJobs: %w", err)
 	}
@@ -57,6 +66,11 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.createHealthcheckStmt != nil {
+		if cerr := q.createHealthcheckStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createHealthcheckStmt: %w", cerr)
+		}
+	}
 	if q.createJobStmt != nil {
 		if cerr := q.createJobStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createJobStmt: %w", cerr)
@@ -67,6 +81,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createTestcaseStmt: %w", cerr)
 		}
 	}
+	if q.getHealthchecksForJobStmt != nil {
+		if cerr := q.getHealthchecksForJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getHealthchecksForJobStmt: %w", cerr)
+		}
+	}
 	if q.getJobStmt != nil {
 		if cerr := q.getJobStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getJobStmt: %w", cerr)
@@ -92,6 +111,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listAlertableRecentTestFailuresStmt: %w", cerr)
 		}
 	}
+	if q.listHealthcheckFailuresStmt != nil {
+		if cerr := q.listHealthcheckFailuresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listHealthcheckFailuresStmt: %w", cerr)
+		}
+	}
 	if q.listJobsStmt != nil {
 		if cerr := q.listJobsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listJobsStmt: %w", cerr)
@@ -146,13 +170,16 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                  DBTX
 	tx                                  *sql.Tx
+	createHealthcheckStmt               *sql.Stmt
 	createJobStmt                       *sql.Stmt
 	createTestcaseStmt                  *sql.Stmt
+	getHealthchecksForJobStmt           *sql.Stmt
 	getJobStmt                          *sql.Stmt
 	getTestcaseStmt                     *sql.Stmt
 	getTestcaseForJobStmt               *sql.Stmt
 	listAlertableFailuresForJobStmt     *sql.Stmt
 	listAlertableRecentTestFailuresStmt *sql.Stmt
+	listHealthcheckFailuresStmt         *sql.Stmt
 	listJobsStmt                        *sql.Stmt
 	listProblematicTestsStmt            *sql.Stmt
 	listTestcasesStmt                   *sql.Stmt
@@ -162,13 +189,16 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                  tx,
 		tx:                                  tx,
+		createHealthcheckStmt:               q.createHealthcheckStmt,
 		createJobStmt:                       q.createJobStmt,
 		createTestcaseStmt:                  q.createTestcaseStmt,
+		getHealthchecksForJobStmt:           q.getHealthchecksForJobStmt,
 		getJobStmt:                          q.getJobStmt,
 		getTestcaseStmt:                     q.getTestcaseStmt,
 		getTestcaseForJobStmt:               q.getTestcaseForJobStmt,
 		listAlertableFailuresForJobStmt:     q.listAlertableFailuresForJobStmt,
 		listAlertableRecentTestFailuresStmt: q.listAlertableRecentTestFailuresStmt,
+		listHealthcheckFailuresStmt:         q.listHealthcheckFailuresStmt,
 		listJobsStmt:                        q.listJobsStmt,
 		listProblematicTestsStmt:            q.listProblematicTestsStmt,
 		listTestcasesStmt:                   q.listTestcasesStmt,
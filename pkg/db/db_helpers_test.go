@@ -263,6 +263,11 @@ var migrationTests = map[int]migrationTestCase{
 		during:   ensureColumns("jobs", "upgrade_version"),
 		postdown: ensureNotColumns("jobs", "upgrade_version"),
 	},
+	4: {
+		preup:    ensureNotTables("healthchecks"),
+		during:   ensureColumns("healthchecks", "job_id", "iteration", "name", "status", "message", "checked"),
+		postdown: ensureNotTables("healthchecks"),
+	},
 }
 
 // TestMigrations runs all configured migrations up and down, verifying their correctness
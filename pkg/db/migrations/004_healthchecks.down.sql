@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS healthchecks;
+DROP TYPE IF EXISTS healthcheck_status;
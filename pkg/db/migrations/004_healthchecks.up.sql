@@ -0,0 +1,11 @@
+CREATE TYPE healthcheck_status AS ENUM ('passed', 'failed', 'error', 'skipped');
+
+CREATE TABLE IF NOT EXISTS healthchecks (
+    id bigserial PRIMARY KEY,
+    job_id bigserial REFERENCES jobs NOT NULL,
+    iteration integer NOT NULL,
+    name text NOT NULL,
+    status healthcheck_status NOT NULL,
+    message text NOT NULL,
+    checked timestamp with time zone NOT NULL
+);
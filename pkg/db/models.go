@@ -9,6 +9,27 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+type HealthcheckStatus string
+
+const (
+	HealthcheckStatusPassed  HealthcheckStatus = "passed"
+	HealthcheckStatusFailed  HealthcheckStatus = "failed"
+	HealthcheckStatusError   HealthcheckStatus = "error"
+	HealthcheckStatusSkipped HealthcheckStatus = "skipped"
+)
+
+func (e *HealthcheckStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HealthcheckStatus(s)
+	case string:
+		*e = HealthcheckStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HealthcheckStatus: %T", src)
+	}
+	return nil
+}
+
 type JobResult string
 
 const (
@@ -50,6 +71,16 @@ func (e *TestResult) Scan(src interface{}) error {
 	return nil
 }
 
+type Healthcheck struct {
+	ID        int64             `json:"id"`
+	JobID     int64             `json:"job_id"`
+	Iteration int32             `json:"iteration"`
+	Name      string            `json:"name"`
+	Status    HealthcheckStatus `json:"status"`
+	Message   string            `json:"message"`
+	Checked   time.Time         `json:"checked"`
+}
+
 type Job struct {
 	ID                 int64           `json:"id"`
 	Provider           string          `json:"provider"`
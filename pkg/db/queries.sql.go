@@ -11,6 +11,42 @@ import (
 	"github.com/lib/pq"
 )
 
+const createHealthcheck = `-- name: CreateHealthcheck :one
+INSERT INTO healthchecks (
+    job_id,
+    iteration,
+    name,
+    status,
+    message,
+    checked
+)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id
+`
+
+type CreateHealthcheckParams struct {
+	JobID     int64             `json:"job_id"`
+	Iteration int32             `json:"iteration"`
+	Name      string            `json:"name"`
+	Status    HealthcheckStatus `json:"status"`
+	Message   string            `json:"message"`
+	Checked   time.Time         `json:"checked"`
+}
+
+func (q *Queries) CreateHealthcheck(ctx context.Context, arg CreateHealthcheckParams) (int64, error) {
+	row := q.queryRow(ctx, q.createHealthcheckStmt, createHealthcheck,
+		arg.JobID,
+		arg.Iteration,
+		arg.Name,
+		arg.Status,
+		arg.Message,
+		arg.Checked,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const createJob = `-- name: CreateJob :one
 INSERT INTO jobs (
     provider,
@@ -131,6 +167,44 @@ func (q *Queries) CreateTestcase(ctx context.Context, arg CreateTestcaseParams)
 	return id, err
 }
 
+const getHealthchecksForJob = `-- name: GetHealthchecksForJob :many
+SELECT id, job_id, iteration, name, status, message, checked
+FROM healthchecks
+WHERE healthchecks.job_id = $1
+ORDER BY healthchecks.iteration, healthchecks.id
+`
+
+func (q *Queries) GetHealthchecksForJob(ctx context.Context, jobID int64) ([]Healthcheck, error) {
+	rows, err := q.query(ctx, q.getHealthchecksForJobStmt, getHealthchecksForJob, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Healthcheck
+	for rows.Next() {
+		var i Healthcheck
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Iteration,
+			&i.Name,
+			&i.Status,
+			&i.Message,
+			&i.Checked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getJob = `-- name: GetJob :one
 SELECT id, provider, job_name, job_id, url, started, finished, duration, cluster_version, cluster_name, cluster_id, multi_az, channel, environment, region, numb_worker_nodes, network_provider, image_content_source, install_config, hibernate_after_use, reused, result, upgrade_version
 FROM jobs
@@ -422,6 +496,61 @@ func (q *Queries) ListAlertableRecentTestFailures(ctx context.Context, names []s
 	return items, nil
 }
 
+const listHealthcheckFailures = `-- name: ListHealthcheckFailures :many
+select
+    jobs.environment,
+    jobs.cluster_version,
+    healthchecks.name,
+    count(*) as failures,
+    count(distinct jobs.id) as jobs
+from jobs
+    join healthchecks
+    on jobs.id = healthchecks.job_id
+where
+    now() - jobs.started < $1::interval
+    and (healthchecks.status = 'failed' or healthchecks.status = 'error')
+group by jobs.environment, jobs.cluster_version, healthchecks.name
+order by failures desc
+`
+
+type ListHealthcheckFailuresRow struct {
+	Environment    string `json:"environment"`
+	ClusterVersion string `json:"cluster_version"`
+	Name           string `json:"name"`
+	Failures       int64  `json:"failures"`
+	Jobs           int64  `json:"jobs"`
+}
+
+// count how often each health check failed or errored per environment and cluster version
+func (q *Queries) ListHealthcheckFailures(ctx context.Context, since pgtype.Interval) ([]ListHealthcheckFailuresRow, error) {
+	rows, err := q.query(ctx, q.listHealthcheckFailuresStmt, listHealthcheckFailures, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListHealthcheckFailuresRow
+	for rows.Next() {
+		var i ListHealthcheckFailuresRow
+		if err := rows.Scan(
+			&i.Environment,
+			&i.ClusterVersion,
+			&i.Name,
+			&i.Failures,
+			&i.Jobs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listJobs = `-- name: ListJobs :many
 SELECT id, provider, job_name, job_id, url, started, finished, duration, cluster_version, cluster_name, cluster_id, multi_az, channel, environment, region, numb_worker_nodes, network_provider, image_content_source, install_config, hibernate_after_use, reused, result, upgrade_version
 FROM jobs
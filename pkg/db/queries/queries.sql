@@ -139,3 +139,39 @@ from
 where counts.error + counts.failure > 1
 ;
 
+
+-- name: CreateHealthcheck :one
+INSERT INTO healthchecks (
+    job_id,
+    iteration,
+    name,
+    status,
+    message,
+    checked
+)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: GetHealthchecksForJob :many
+SELECT *
+FROM healthchecks
+WHERE healthchecks.job_id = $1
+ORDER BY healthchecks.iteration, healthchecks.id;
+
+-- name: ListHealthcheckFailures :many
+-- count how often each health check failed or errored per environment and cluster version
+select
+    jobs.environment,
+    jobs.cluster_version,
+    healthchecks.name,
+    count(*) as failures,
+    count(distinct jobs.id) as jobs
+from jobs
+    join healthchecks
+    on jobs.id = healthchecks.job_id
+where
+    now() - jobs.started < sqlc.arg(since)::interval
+    and (healthchecks.status = 'failed' or healthchecks.status = 'error')
+group by jobs.environment, jobs.cluster_version, healthchecks.name
+order by failures desc
+;
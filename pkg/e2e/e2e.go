@@ -13,10 +13,12 @@ import (
 	"log"
 	"math"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -47,6 +49,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/pagerduty"
 	"github.com/openshift/osde2e/pkg/common/phase"
 	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/providers/kubeconfigprovider"
 	"github.com/openshift/osde2e/pkg/common/prow"
 	"github.com/openshift/osde2e/pkg/common/runner"
 	"github.com/openshift/osde2e/pkg/common/spi"
@@ -73,7 +76,7 @@ var provider spi.Provider
 
 // beforeSuite attempts to populate several required cluster fields (either by provisioning a new cluster, or re-using an existing one)
 // If there is an issue with provisioning, retrieving, or getting the kubeconfig, this will return `false`.
-func beforeSuite() bool {
+func beforeSuite(ctx context.Context) bool {
 	// Skip provisioning if we already have a kubeconfig
 	var err error
 
@@ -81,7 +84,7 @@ func beforeSuite() bool {
 	config.LoadKubeconfig()
 
 	if viper.GetString(config.Kubeconfig.Contents) == "" {
-		cluster, err := clusterutil.ProvisionCluster(nil)
+		cluster, err := clusterutil.ProvisionCluster(ctx, nil)
 		events.HandleErrorWithEvents(err, events.InstallSuccessful, events.InstallFailed)
 		if err != nil {
 			log.Printf("Failed to set up or retrieve cluster: %v", err)
@@ -109,7 +112,7 @@ func beforeSuite() bool {
 		viper.Set(config.CloudProvider.Region, cluster.Region())
 		log.Printf("CLOUD_PROVIDER_REGION set to %s from OCM.", viper.GetString(config.CloudProvider.Region))
 
-		if (!viper.GetBool(config.Addons.SkipAddonList) || viper.GetString(config.Provider) != "mock") && len(cluster.Addons()) > 0 {
+		if (!viper.GetBool(config.Addons.SkipAddonList) || provider.Capabilities().Addons) && len(cluster.Addons()) > 0 {
 			log.Printf("Found addons: %s", strings.Join(cluster.Addons(), ","))
 		}
 
@@ -117,17 +120,18 @@ func beforeSuite() bool {
 		metadata.Instance.SetClusterID(cluster.ID())
 		metadata.Instance.SetRegion(cluster.Region())
 
-		if err = provider.AddProperty(cluster, "UpgradeVersion", viper.GetString(config.Upgrade.ReleaseName)); err != nil {
+		if err = provider.AddPropertyContext(ctx, cluster, "UpgradeVersion", viper.GetString(config.Upgrade.ReleaseName)); err != nil {
 			log.Printf("Error while adding upgrade version property to cluster via OCM: %v", err)
 		}
 
 		if viper.GetString(config.Tests.SkipClusterHealthChecks) != "true" {
-			if viper.GetBool(config.Cluster.Reused) {
+			// Pooled clusters that were already running don't need to wake, unlike other reused clusters.
+			if leased, resumed := clusterutil.PoolLease(); resumed || (viper.GetBool(config.Cluster.Reused) && !leased) {
 				// We should manually run all our health checks if the cluster is waking up
-				err = clusterutil.WaitForClusterReadyPostWake(cluster.ID(), nil)
+				err = clusterutil.WaitForClusterReadyPostWake(ctx, cluster.ID(), nil)
 			} else {
 				// This is a new cluster and we should check the OSD Ready job
-				err = clusterutil.WaitForClusterReadyPostInstall(cluster.ID(), nil)
+				err = clusterutil.WaitForClusterReadyPostInstall(ctx, cluster.ID(), nil)
 			}
 			if err != nil {
 				log.Println("*******************")
@@ -140,8 +144,9 @@ func beforeSuite() bool {
 		}
 
 		var kubeconfigBytes []byte
-		clusterConfigerr := wait.PollImmediate(2*time.Second, 5*time.Minute, func() (bool, error) {
-			kubeconfigBytes, err = provider.ClusterKubeconfig(viper.GetString(config.Cluster.ID))
+		clusterConfigerr := wait.PollImmediateWithContext(ctx, 2*time.Second, 5*time.Minute, func(ctx context.Context) (bool, error) {
+			kubeconfigBytes, err = provider.ClusterKubeconfigContext(ctx, viper.GetString(config.Cluster.ID))
+			metadata.Instance.RecordKubeconfigAttempt(err)
 			if err != nil {
 				log.Printf("Failed to get kubeconfig from OCM: %v\nWaiting two seconds before retrying", err)
 				return false, err
@@ -165,8 +170,8 @@ func beforeSuite() bool {
 	}
 
 	if len(viper.GetString(config.Addons.IDs)) > 0 {
-		if viper.GetString(config.Provider) != "mock" {
-			err = installAddons()
+		if provider.Capabilities().Addons {
+			err = installAddons(ctx)
 			events.HandleErrorWithEvents(err, events.InstallAddonsSuccessful, events.InstallAddonsFailed)
 			if err != nil {
 				log.Printf("Cluster failed installing addons: %v", err)
@@ -174,7 +179,7 @@ func beforeSuite() bool {
 				return false
 			}
 		} else {
-			log.Println("Skipping addon installation due to mock provider.")
+			log.Printf("Skipping addon installation: provider %s does not support addons.", provider.Type())
 			log.Println("If you are running local addon tests, please ensure the addon components are already installed.")
 		}
 	}
@@ -200,6 +205,50 @@ func beforeSuite() bool {
 	return true
 }
 
+// useExistingCluster populates the cluster configuration and metadata from the cluster behind the
+// kubeconfig, in place of provisioning one, and reports its health.
+func useExistingCluster(ctx context.Context) error {
+	cluster, err := provider.GetClusterContext(ctx, viper.GetString(config.Cluster.ID))
+	if err != nil {
+		return fmt.Errorf("could not describe the existing cluster: %v", err)
+	}
+
+	viper.Set(config.Cluster.ID, cluster.ID())
+	log.Printf("CLUSTER_ID set to %s from the cluster.", viper.GetString(config.Cluster.ID))
+
+	viper.Set(config.Cluster.Name, cluster.Name())
+	log.Printf("CLUSTER_NAME set to %s from the cluster.", viper.GetString(config.Cluster.Name))
+
+	viper.Set(config.Cluster.Version, cluster.Version())
+	log.Printf("CLUSTER_VERSION set to %s from the cluster.", viper.GetString(config.Cluster.Version))
+
+	viper.Set(config.CloudProvider.CloudProviderID, cluster.CloudProvider())
+	log.Printf("CLOUD_PROVIDER_ID set to %s from the cluster.", viper.GetString(config.CloudProvider.CloudProviderID))
+
+	viper.Set(config.CloudProvider.Region, cluster.Region())
+	log.Printf("CLOUD_PROVIDER_REGION set to %s from the cluster.", viper.GetString(config.CloudProvider.Region))
+
+	metadata.Instance.SetClusterName(cluster.Name())
+	metadata.Instance.SetClusterID(cluster.ID())
+	metadata.Instance.SetClusterVersion(cluster.Version())
+	metadata.Instance.SetRegion(cluster.Region())
+
+	if viper.GetString(config.Tests.SkipClusterHealthChecks) == "true" {
+		log.Println("Skipping health checks as requested")
+		return nil
+	}
+
+	healthy, failures, err := clusterutil.PollClusterHealth(cluster.ID(), nil)
+	if err != nil || !healthy {
+		log.Println("*******************")
+		log.Printf("Cluster failed health check (%s): %v", strings.Join(failures, ","), err)
+		log.Println("*******************")
+	} else {
+		log.Println("Cluster is healthy and ready for testing")
+	}
+	return nil
+}
+
 func getLogs() {
 	clusterID := viper.GetString(config.Cluster.ID)
 	if provider == nil {
@@ -228,19 +277,19 @@ func writeLogs(m map[string][]byte) {
 }
 
 // installAddons installs addons onto the cluster
-func installAddons() (err error) {
+func installAddons(ctx context.Context) (err error) {
 	clusterID := viper.GetString(config.Cluster.ID)
 	params := make(map[string]map[string]string)
 	strParams := viper.GetString(config.Addons.Parameters)
 	if err := json.Unmarshal([]byte(strParams), &params); err != nil {
 		return fmt.Errorf("failed unmarshalling addon parameters %s: %w", strParams, err)
 	}
-	num, err := provider.InstallAddons(clusterID, strings.Split(viper.GetString(config.Addons.IDs), ","), params)
+	num, err := provider.InstallAddonsContext(ctx, clusterID, strings.Split(viper.GetString(config.Addons.IDs), ","), params)
 	if err != nil {
 		return fmt.Errorf("could not install addons: %s", err.Error())
 	}
 	if num > 0 {
-		if err = cluster.WaitForClusterReadyPostInstall(clusterID, nil); err != nil {
+		if err = cluster.WaitForClusterReadyPostInstall(ctx, clusterID, nil); err != nil {
 			return fmt.Errorf("failed waiting for cluster ready: %v", err)
 		}
 	}
@@ -270,6 +319,22 @@ func RunTests() int {
 func runGinkgoTests() (int, error) {
 	var err error
 
+	// Cancel outstanding provider calls and cluster waits when Prow asks us to stop
+	// or the configured job deadline passes.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if jobTimeout := viper.GetString(config.JobTimeout); jobTimeout != "" {
+		timeout, err := time.ParseDuration(jobTimeout)
+		if err != nil {
+			return Failure, fmt.Errorf("failed parsing job timeout: %v", err)
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	gomega.RegisterFailHandler(ginkgo.Fail)
 	viper.Set(config.Cluster.Passing, false)
 	suiteConfig, reporterConfig := ginkgo.GinkgoConfiguration()
@@ -338,22 +403,24 @@ func runGinkgoTests() (int, error) {
 
 	log.Printf("Outputting log to build log at %s", buildLogPath)
 
-	// Get the cluster ID now to test against later
-	providerCfg := viper.GetString(config.Provider)
-	// setup OSD unless Kubeconfig is present
-	if len(viper.GetString(config.Kubeconfig.Path)) > 0 && providerCfg == "mock" {
+	// The mock provider used to be the way to run against an existing cluster
+	if len(viper.GetString(config.Kubeconfig.Path)) > 0 && viper.GetString(config.Provider) == "mock" {
+		log.Printf("Using the %s provider for the existing Kubeconfig instead of mock", kubeconfigprovider.ProviderName)
+		viper.Set(config.Provider, kubeconfigprovider.ProviderName)
+	}
+
+	if provider, err = providers.ClusterProvider(); err != nil {
+		return Failure, fmt.Errorf("could not setup cluster provider: %v", err)
+	}
+	metadata.Instance.SetEnvironment(provider.Environment())
+
+	// setup OSD unless we are attaching to an existing cluster
+	if provider.Type() == kubeconfigprovider.ProviderName {
 		log.Print("Found an existing Kubeconfig!")
-		if provider, err = providers.ClusterProvider(); err != nil {
-			return Failure, fmt.Errorf("could not setup cluster provider: %v", err)
+		if err = useExistingCluster(ctx); err != nil {
+			return Failure, err
 		}
-		metadata.Instance.SetEnvironment(provider.Environment())
 	} else {
-		if provider, err = providers.ClusterProvider(); err != nil {
-			return Failure, fmt.Errorf("could not setup cluster provider: %v", err)
-		}
-
-		metadata.Instance.SetEnvironment(provider.Environment())
-
 		// configure cluster and upgrade versions
 		if err = ChooseVersions(); err != nil {
 			// if we fail to choose versions, we should gracefully exit
@@ -388,7 +455,7 @@ func runGinkgoTests() (int, error) {
 		viper.Set(config.Suffix, util.RandomStr(5))
 	}
 
-	testsPassed, installTestCaseData := runTestsInPhase(phase.InstallPhase, "OSD e2e suite", suiteConfig, reporterConfig)
+	testsPassed, installTestCaseData := runTestsInPhase(ctx, phase.InstallPhase, "OSD e2e suite", suiteConfig, reporterConfig)
 	getLogs()
 	viper.Set(config.Cluster.Passing, testsPassed)
 	upgradeTestsPassed := true
@@ -401,12 +468,12 @@ func runGinkgoTests() (int, error) {
 			var routeMonitorChan chan struct{}
 			closeMonitorChan := make(chan struct{})
 			if viper.GetBool(config.Upgrade.MonitorRoutesDuringUpgrade) && !suiteConfig.DryRun {
-				routeMonitorChan = setupRouteMonitors(context.TODO(), closeMonitorChan)
+				routeMonitorChan = setupRouteMonitors(ctx, closeMonitorChan)
 				log.Println("Route Monitors created.")
 			}
 
 			// run the upgrade
-			if err = upgrade.RunUpgrade(); err != nil {
+			if err = upgrade.RunUpgrade(ctx); err != nil {
 				events.RecordEvent(events.UpgradeFailed)
 				return Failure, fmt.Errorf("error performing upgrade: %v", err)
 			}
@@ -417,6 +484,7 @@ func runGinkgoTests() (int, error) {
 				log.Println("Running e2e tests POST-UPGRADE...")
 				viper.Set(config.Cluster.Passing, false)
 				upgradeTestsPassed, upgradeTestCaseData = runTestsInPhase(
+					ctx,
 					phase.UpgradePhase,
 					"OSD e2e suite post-upgrade",
 					suiteConfig,
@@ -489,7 +557,17 @@ func runGinkgoTests() (int, error) {
 			}(),
 		}
 		testData := append(installTestCaseData, upgradeTestCaseData...)
-		if err := updateDatabaseAndPagerduty(dbURL, jobData, testData...); err != nil {
+		var healthcheckData []db.CreateHealthcheckParams
+		for _, record := range clusterutil.HealthCheckHistory() {
+			healthcheckData = append(healthcheckData, db.CreateHealthcheckParams{
+				Iteration: int32(record.Iteration),
+				Name:      record.Name,
+				Status:    db.HealthcheckStatus(record.Status),
+				Message:   record.Message,
+				Checked:   record.Checked,
+			})
+		}
+		if err := updateDatabaseAndPagerduty(dbURL, jobData, healthcheckData, testData...); err != nil {
 			log.Printf("failed updating database or pagerduty: %v", err)
 		}
 	}
@@ -564,6 +642,12 @@ const ManyGroupedFailureName = "A lot of tests failed together"
 func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 	var err error
 	clusterStatus := clusterproperties.StatusCompletedFailing
+	// A pooled cluster's lease is ended last, once nothing else will update the cluster's properties.
+	defer func() {
+		if err := clusterutil.EndPoolLease(ctx); err != nil {
+			log.Printf("Error ending the lease on pooled cluster: %v", err)
+		}
+	}()
 	defer ginkgo.GinkgoRecover()
 
 	if viper.GetBool(config.MustGather) {
@@ -572,6 +656,7 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 		h.SetServiceAccount(ctx, "system:serviceaccount:%s:cluster-admin")
 		r := h.Runner(fmt.Sprintf("oc adm must-gather --dest-dir=%v", runner.DefaultRunner.OutputDir))
 		r.Name = "must-gather"
+		h.SetRunnerPodOptions(r, config.Runner.MustGather)
 		r.Tarball = true
 		stopCh := make(chan struct{})
 		err := r.Run(mustGatherTimeoutInSeconds, stopCh)
@@ -580,12 +665,9 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 			log.Printf("Error running must-gather: %s", err.Error())
 			clusterStatus = clusterproperties.StatusCompletedError
 		} else {
-			gatherResults, err := r.RetrieveResults()
-			if err != nil {
+			if _, err := r.DownloadResults(h.ResultsDir()); err != nil {
 				log.Printf("Error retrieving must-gather results: %s", err.Error())
 				clusterStatus = clusterproperties.StatusCompletedError
-			} else {
-				h.WriteResults(gatherResults)
 			}
 		}
 
@@ -690,7 +772,7 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 	// If this is a nightly test, we don't want to expire this immediately
 	if viper.GetString(config.Cluster.InstallSpecificNightly) != "" || viper.GetString(config.Cluster.ReleaseImageLatest) != "" {
 		viper.Set(config.Cluster.HibernateAfterUse, false)
-		if viper.GetString(config.Cluster.ID) != "" {
+		if provider != nil && viper.GetString(config.Cluster.ID) != "" && provider.Capabilities().ExtendExpiry {
 			provider.Expire(viper.GetString(config.Cluster.ID))
 		}
 	}
@@ -698,7 +780,10 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 	// We need a provider to hibernate
 	// We need a cluster to hibernate
 	// We need to check that the test run wants to hibernate after this run
-	if provider != nil && viper.GetString(config.Cluster.ID) != "" && viper.GetBool(config.Cluster.HibernateAfterUse) && !viper.GetBool(config.Cluster.DestroyAfterTest) {
+	hibernate := provider != nil && viper.GetString(config.Cluster.ID) != "" && viper.GetBool(config.Cluster.HibernateAfterUse) && !viper.GetBool(config.Cluster.DestroyAfterTest)
+	if hibernate && !provider.Capabilities().Hibernation {
+		log.Printf("Not hibernating %s: provider %s does not support hibernation", viper.GetString(config.Cluster.ID), provider.Type())
+	} else if hibernate {
 		msg := "Unable to hibernate %s"
 		if provider.Hibernate(viper.GetString(config.Cluster.ID)) {
 			msg = "Hibernating %s"
@@ -708,13 +793,15 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 		// Current default expiration is 6 hours.
 		// If this cluster has addons, we don't want to extend the expiration
 
-		if !viper.GetBool(config.Cluster.Reused) && clusterStatus != clusterproperties.StatusCompletedError && viper.GetString(config.Addons.IDs) == "" {
+		if provider.Capabilities().ExtendExpiry && !viper.GetBool(config.Cluster.Reused) && clusterStatus != clusterproperties.StatusCompletedError && viper.GetString(config.Addons.IDs) == "" {
 			cluster, err := provider.GetCluster(viper.GetString(config.Cluster.ID))
 			if err != nil {
 				log.Printf("Error getting cluster from provider: %s", err.Error())
 			}
 			if !cluster.ExpirationTimestamp().Add(6 * time.Hour).After(cluster.CreationTimestamp().Add(24 * time.Hour)) {
-				if err := provider.ExtendExpiry(viper.GetString(config.Cluster.ID), 6, 0, 0); err != nil {
+				err := provider.ExtendExpiry(viper.GetString(config.Cluster.ID), 6, 0, 0)
+				metadata.Instance.RecordExpiryExtension(6*time.Hour, err)
+				if err != nil {
 					log.Printf("Error extending cluster expiration: %s", err.Error())
 				}
 			}
@@ -725,6 +812,7 @@ func cleanupAfterE2E(ctx context.Context, h *helper.H) (errors []error) {
 
 // nolint:gocyclo
 func runTestsInPhase(
+	ctx context.Context,
 	phase string,
 	description string,
 	suiteConfig types.SuiteConfig,
@@ -745,7 +833,7 @@ func runTestsInPhase(
 	ginkgoPassed := false
 
 	if !suiteConfig.DryRun {
-		if !beforeSuite() {
+		if !beforeSuite(ctx) {
 			log.Println("Error getting kubeconfig from beforeSuite function")
 			return false, testCaseData
 		}
@@ -756,6 +844,10 @@ func runTestsInPhase(
 	func() {
 		defer ginkgo.GinkgoRecover()
 
+		if !suiteConfig.DryRun {
+			defer clusterutil.MonitorHealth(ctx, phase, nil)()
+		}
+
 		ginkgoPassed = ginkgo.RunSpecs(ginkgo.GinkgoT(), description, suiteConfig, reporterConfig)
 	}()
 
@@ -1050,7 +1142,7 @@ func setupRouteMonitors(ctx context.Context, closeChannel chan struct{}) chan st
 	return routeMonitorChan
 }
 
-func updateDatabaseAndPagerduty(dbURL string, jobData db.CreateJobParams, testData ...db.CreateTestcaseParams) error {
+func updateDatabaseAndPagerduty(dbURL string, jobData db.CreateJobParams, healthcheckData []db.CreateHealthcheckParams, testData ...db.CreateTestcaseParams) error {
 	var (
 		problematicSet = make(map[string]db.ListProblematicTestsRow)
 		alertData      map[string][]db.ListAlertableRecentTestFailuresRow
@@ -1089,6 +1181,13 @@ func updateDatabaseAndPagerduty(dbURL string, jobData db.CreateJobParams, testDa
 			}
 		}
 
+		for _, hc := range healthcheckData {
+			hc.JobID = jobID
+			if _, err := q.CreateHealthcheck(context.TODO(), hc); err != nil {
+				return fmt.Errorf("failed creating health check: %w", err)
+			}
+		}
+
 		alertData, err = q.AlertDataForJob(context.TODO(), jobID)
 		if err != nil {
 			return fmt.Errorf("failed creating alert data: %w", err)
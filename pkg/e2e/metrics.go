@@ -18,6 +18,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/events"
 	"github.com/openshift/osde2e/pkg/common/metadata"
+	"github.com/openshift/osde2e/pkg/common/pool"
 	"github.com/openshift/osde2e/pkg/common/providers"
 	"github.com/openshift/osde2e/pkg/common/spi"
 	"github.com/prometheus/client_golang/prometheus"
@@ -90,6 +91,7 @@ func NewMetrics() *Metrics {
 	metricRegistry.MustRegister(addonGatherer)
 	metricRegistry.MustRegister(eventGatherer)
 	metricRegistry.MustRegister(routeGatherer)
+	metricRegistry.MustRegister(pool.Collectors()...)
 
 	provider, err := providers.ClusterProvider()
 	if err != nil {
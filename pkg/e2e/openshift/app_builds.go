@@ -96,18 +96,16 @@ var _ = ginkgo.Describe(appBuildsTestName, func() {
 			r := h.Runner(cmd)
 
 			r.Name = "openshift-tests"
+			r.Tarball = cfg.Tarball
 
 			// run tests
 			stopCh := make(chan struct{})
 			err := r.Run(e2eTimeoutInSeconds, stopCh)
 			Expect(err).NotTo(HaveOccurred())
 
-			// get results
-			results, err := r.RetrieveResults()
+			// download results into the report directory
+			_, err = r.DownloadResults(h.ResultsDir())
 			Expect(err).NotTo(HaveOccurred())
-
-			// write results
-			h.WriteResults(results)
 		}
 	}, float64(e2eTimeoutInSeconds+30))
 })
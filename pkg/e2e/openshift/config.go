@@ -21,30 +21,12 @@ REGION="$(oc get -o jsonpath='{.status.platformStatus.aws.region}' infrastructur
 ZONE="$(oc get -o jsonpath='{.items[0].metadata.labels.failure-domain\.beta\.kubernetes\.io/zone}' nodes)"
 export TEST_PROVIDER="{\"type\":\"aws\",\"region\":\"${REGION}\",\"zone\":\"${ZONE}\",\"multizone\":true,\"multimaster\":true}"
 
+{{if gt .Shards 1 -}}
+# run shard {{.Shard}} of {{.Shards}}, a fixed subset of the sorted suite
+{{if .TestNames}}{{printTests .TestNames}}{{else}}{{unwrap .Env}} openshift-tests {{.TestCmd}} {{.Suite}} --dry-run --provider "${TEST_PROVIDER}"{{end}} | LC_ALL=C sort | awk '(NR - 1) % {{.Shards}} == {{.Shard}}' | {{unwrap .Env}} openshift-tests {{.TestCmd}} --file=- {{unwrap .Flags}} --provider "${TEST_PROVIDER}"
+{{- else -}}
 {{printTests .TestNames}} | {{unwrap .Env}} openshift-tests {{.TestCmd}} {{selectTests .Suite .TestNames}} {{unwrap .Flags}} --provider "${TEST_PROVIDER}"
-
-# create a Tarball of OutputDir if requested
-{{$outDir := .OutputDir}}
-{{if .Tarball}}
-	{{$outDir = "/tmp/out"}}
-        mkdir -p {{$outDir}}
-	tar cvfz {{$outDir}}/{{.Name}}.tgz {{.OutputDir}}
-{{end}}
-
-case $(rpm -qa python) in
-python-2*)
-	MODULE="SimpleHTTPServer"
-	;;
-python-3*)
-	MODULE="http.server"
-	;;
-*)
-	MODULE="http.server"
-	;;
-esac
-
-# make results available using HTTP
-cd {{$outDir}} && echo "Starting server" && python -m "${MODULE}"
+{{- end}}
 `
 
 var cmdTemplate = template.Must(template.New("testCmd").
@@ -62,7 +44,7 @@ type E2EConfig struct {
 	// TestCmd determines which suite the runner executes.
 	TestCmd string
 
-	// Tarball determines whether the results should be tar'd or not
+	// Tarball determines whether the runner should tar the results or not.
 	Tarball bool
 
 	// Suite to be run inside the runner.
@@ -74,6 +56,12 @@ type E2EConfig struct {
 	// Flags to run the suite with.
 	Flags []string
 
+	// Shards is the number of runners the suite is split across. Each runs every Shards-th test of the sorted suite.
+	Shards int
+
+	// Shard is the index of the subset of the suite to run when it is split across Shards runners.
+	Shard int
+
 	// Output Dir is where e2e tests serve up results
 	OutputDir string
 
@@ -90,6 +78,20 @@ func (c E2EConfig) Cmd() string {
 	return cmd.String()
 }
 
+// ShardCmds returns a shell command for each shard of the suite, or just Cmd if it isn't sharded.
+func (c E2EConfig) ShardCmds() []string {
+	if c.Shards <= 1 {
+		return []string{c.Cmd()}
+	}
+	cmds := make([]string, c.Shards)
+	for i := range cmds {
+		shard := c
+		shard.Shard = i
+		cmds[i] = shard.Cmd()
+	}
+	return cmds
+}
+
 func printTests(strs []string) string {
 	testList := strings.Join(strs, "\"\n\"")
 	return fmt.Sprintf("printf '\"%s\"'", testList)
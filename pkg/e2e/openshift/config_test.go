@@ -0,0 +1,80 @@
+package openshift
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeOpenShiftTests lists the suite's tests out of order when given --dry-run with SUITE_ENV set, and otherwise
+// prints the tests it was given to run.
+const fakeOpenShiftTests = `#!/bin/sh
+for arg in "$@"; do
+	if [ "$arg" = "--dry-run" ]; then
+		[ "$SUITE_ENV" = "1" ] || exit 1
+		printf '"c"\n"a"\n"e"\n"b"\n"d"\n"g"\n"f"\n'
+		exit 0
+	fi
+done
+cat
+`
+
+func TestShardCmds(t *testing.T) {
+	RegisterTestingT(t)
+
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "openshift-tests"), []byte(fakeOpenShiftTests), 0o755); err != nil {
+		t.Fatalf("unable to write fake openshift-tests: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		shards      int
+		testNames   []string
+		expected    []string
+	}{
+		{"unsharded", 1, []string{"a", "b"}, []string{`"a"`, `"b"`}},
+		{"suite", 3, nil, []string{`"a"`, `"b"`, `"c"`, `"d"`, `"e"`, `"f"`, `"g"`}},
+		{"more shards than tests", 4, []string{"b", "a"}, []string{`"a"`, `"b"`}},
+	}
+
+	for _, test := range tests {
+		cfg := E2EConfig{
+			Env:       []string{"SUITE_ENV=1"},
+			TestCmd:   "run",
+			Suite:     "openshift/conformance/parallel suite",
+			TestNames: test.testNames,
+			Shards:    test.shards,
+		}
+
+		cmds := cfg.ShardCmds()
+		if len(cmds) != test.shards {
+			t.Errorf("%s: expected %d commands, got %d", test.description, test.shards, len(cmds))
+		}
+
+		// Only what follows the last blank line runs the tests, the rest configures oc in the runner pod.
+		var all []string
+		for i, cmd := range cmds {
+			run := exec.Command("sh", "-c", cmd[strings.LastIndex(cmd, "\n\n"):])
+			run.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			out, err := run.Output()
+			if err != nil {
+				t.Errorf("%s: shard %d failed: %v", test.description, i, err)
+				continue
+			}
+			all = append(all, strings.Fields(string(out))...)
+		}
+
+		// Together the shards run every test exactly once.
+		sort.Strings(all)
+		if !reflect.DeepEqual(all, test.expected) {
+			t.Errorf("%s: expected the shards to run %v, got %v", test.description, test.expected, all)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/openshift/osde2e/pkg/common/alert"
+	viper "github.com/openshift/osde2e/pkg/common/concurrentviper"
+	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/runner"
 	"github.com/openshift/osde2e/pkg/common/util"
@@ -16,6 +18,7 @@ import (
 // DefaultE2EConfig is the base configuration for E2E runs.
 var DefaultE2EConfig = E2EConfig{
 	OutputDir: "/test-run-results",
+	Shards:    1,
 	TestCmd:   "run",
 	Tarball:   false,
 	Suite:     "kubernetes/conformance",
@@ -61,11 +64,8 @@ var _ = ginkgo.Describe(conformanceK8sTestName, func() {
 		err := r.Run(e2eTimeoutInSeconds, stopCh)
 		Expect(err).NotTo(HaveOccurred())
 
-		// get results
-		results, err := r.RetrieveTestResults()
-
-		// write results
-		h.WriteResults(results)
+		// download results into the report directory
+		_, err = r.DownloadTestResults(h.ResultsDir())
 
 		// evaluate results
 		Expect(err).NotTo(HaveOccurred())
@@ -83,23 +83,20 @@ var _ = ginkgo.Describe(conformanceOpenshiftTestName, func() {
 		cfg := DefaultE2EConfig
 		cfg.Suite = "openshift/conformance/parallel suite"
 		cfg.Name = "openshift-conformance"
-		cmd := cfg.Cmd()
-
-		// setup runner
-		r := h.Runner(cmd)
+		cfg.Shards = viper.GetInt(config.Runner.ConformanceShards)
 
-		r.Name = "openshift-conformance"
+		// setup a runner for each shard
+		base := h.RunnerWithNoCommand()
+		base.Name = "openshift-conformance"
+		r := runner.NewShardedRunner(base, cfg.ShardCmds())
 
 		// run tests
 		stopCh := make(chan struct{})
 		err := r.Run(e2eTimeoutInSeconds, stopCh)
 		Expect(err).NotTo(HaveOccurred())
 
-		// get results
-		results, err := r.RetrieveTestResults()
-
-		// write results
-		h.WriteResults(results)
+		// download results into the report directory
+		_, err = r.DownloadTestResults(h.ResultsDir())
 
 		// evaluate results
 		Expect(err).NotTo(HaveOccurred())
@@ -41,11 +41,8 @@ var _ = ginkgo.Describe(disruptiveTestName, func() {
 		err := r.Run(e2eTimeoutInSeconds, stopCh)
 		Expect(err).NotTo(HaveOccurred())
 
-		// get results
-		results, err := r.RetrieveTestResults()
-
-		// write results
-		h.WriteResults(results)
+		// download results into the report directory
+		_, err = r.DownloadTestResults(h.ResultsDir())
 
 		// evaluate results
 		Expect(err).NotTo(HaveOccurred())
@@ -22,6 +22,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/helper"
 	"github.com/openshift/osde2e/pkg/common/logging"
 	"github.com/openshift/osde2e/pkg/common/providers"
+	"github.com/openshift/osde2e/pkg/common/spi"
 	"github.com/openshift/osde2e/pkg/common/util"
 )
 
@@ -50,6 +51,8 @@ var _ = ginkgo.Describe(postInstallProxyTestName, func() {
 
 func testAddProxy() {
 	util.GinkgoIt("can add a proxy to the cluster successfully", func(ctx context.Context) {
+		helper.SkipUnlessCapable(spi.ClusterProxyCapability)
+
 		// setup helper
 		h := helper.New()
 
@@ -134,6 +137,8 @@ func testAddProxy() {
 
 func testRemoveProxy() {
 	util.GinkgoIt("can remove proxy from the cluster successfully", func(ctx context.Context) {
+		helper.SkipUnlessCapable(spi.ClusterProxyCapability)
+
 		// setup helper
 		h := helper.New()
 
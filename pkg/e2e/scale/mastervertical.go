@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/osde2e/pkg/common/cluster"
 	"github.com/openshift/osde2e/pkg/common/config"
 	"github.com/openshift/osde2e/pkg/common/helper"
+	"github.com/openshift/osde2e/pkg/common/spi"
 )
 
 const (
@@ -31,9 +32,11 @@ var _ = ginkgo.Describe(masterVerticalTestName, func() {
 
 	masterVerticalTimeoutInSeconds := 7200
 	util.GinkgoIt("should be tested with MasterVertical", func(ctx context.Context) {
+		helper.SkipUnlessCapable(spi.ScalingCapability)
+
 		var err error
 		// Before we do anything, scale the cluster.
-		err = cluster.ScaleCluster(viper.GetString(config.Cluster.ID), numNodesToScaleTo)
+		err = cluster.ScaleCluster(ctx, viper.GetString(config.Cluster.ID), numNodesToScaleTo)
 		Expect(err).NotTo(HaveOccurred())
 
 		h.SetServiceAccount(ctx, "system:serviceaccount:%s:cluster-admin")
@@ -84,6 +84,7 @@ func (sCfg scaleRunnerConfig) Runner(h *helper.H) *runner.Runner {
 	runner.ImageName = ansibleImage
 	runner.Repos = scaleRepos
 	runner.SkipLogsFromPod = true
+	h.SetRunnerPodOptions(runner, config.Runner.Scale)
 
 	runner.PodSpec.Containers[0].Env = append(runner.PodSpec.Containers[0].Env, kubev1.EnvVar{
 		Name:  "WORKLOAD_JOB_PRIVILEGED",
@@ -69,11 +69,8 @@ var _ = ginkgo.Describe(clusterStateInformingName, func() {
 		err := r.Run(int(prometheusTimeout.Seconds()), stopCh)
 		Expect(err).NotTo(HaveOccurred())
 
-		// get results
-		results, err := r.RetrieveResults()
+		// download results into the report directory
+		_, err = r.DownloadResults(h.ResultsDir())
 		Expect(err).NotTo(HaveOccurred())
-
-		// write results
-		h.WriteResults(results)
 	}, prometheusTimeout.Seconds()+prometheusPodStartedDuration.Seconds())
 })